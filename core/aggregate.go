@@ -0,0 +1,212 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type AggregateFunction string
+
+const (
+	AggregateCount AggregateFunction = "count"
+	AggregateSum   AggregateFunction = "sum"
+	AggregateMin   AggregateFunction = "min"
+	AggregateMax   AggregateFunction = "max"
+	AggregateAvg   AggregateFunction = "avg"
+)
+
+// Aggregate computes the function over the numeric field, the result is keyed by Alias
+type Aggregate struct {
+	Function AggregateFunction
+	Field    string
+	Alias    string
+}
+
+// Aggregation groups the entities by the fields, an empty GroupBy aggregates all of them
+type Aggregation struct {
+	GroupBy    []string
+	Aggregates []Aggregate
+}
+
+type AggregateRow struct {
+	// Keys are the group by values keyed by the field
+	Keys   map[string]interface{}
+	Values map[string]float64
+}
+
+func CountOf(alias string) Aggregate {
+	return Aggregate{Function: AggregateCount, Alias: alias}
+}
+
+func SumOf(field string, alias string) Aggregate {
+	return Aggregate{Function: AggregateSum, Field: field, Alias: alias}
+}
+
+func MinOf(field string, alias string) Aggregate {
+	return Aggregate{Function: AggregateMin, Field: field, Alias: alias}
+}
+
+func MaxOf(field string, alias string) Aggregate {
+	return Aggregate{Function: AggregateMax, Field: field, Alias: alias}
+}
+
+func AvgOf(field string, alias string) Aggregate {
+	return Aggregate{Function: AggregateAvg, Field: field, Alias: alias}
+}
+
+func (a Aggregation) validate() error {
+	if len(a.Aggregates) == 0 {
+		return fmt.Errorf("%w aggregates is required", ErrBadRequest)
+	}
+
+	aliases := make(map[string]bool, len(a.Aggregates))
+
+	for _, v := range a.Aggregates {
+		switch v.Function {
+		case AggregateCount:
+		case AggregateSum, AggregateMin, AggregateMax, AggregateAvg:
+			if v.Field == "" {
+				return fmt.Errorf("%w %s field is required", ErrBadRequest, v.Function)
+			}
+		default:
+			return fmt.Errorf("%w unknown aggregate function %s", ErrBadRequest, v.Function)
+		}
+
+		if v.Alias == "" || aliases[v.Alias] {
+			return fmt.Errorf("%w alias %q is empty or duplicated", ErrBadRequest, v.Alias)
+		}
+
+		aliases[v.Alias] = true
+	}
+
+	return nil
+}
+
+// AggregateEntities evaluates the aggregation in memory, the rows are ordered by their keys
+func AggregateEntities(entities []interface{}, aggregation Aggregation) ([]AggregateRow, error) {
+	type group struct {
+		row     AggregateRow
+		count   int
+		sums    map[string]float64
+		counted map[string]int
+	}
+
+	groups := make(map[string]*group)
+	keys := make([]string, 0)
+
+	for _, entity := range entities {
+		keyValues := make(map[string]interface{}, len(aggregation.GroupBy))
+		keyNames := make([]string, 0, len(aggregation.GroupBy))
+
+		for _, v := range aggregation.GroupBy {
+			value, ok := FieldValue(entity, v)
+			if !ok {
+				return nil, fmt.Errorf("%w unknown field %s", ErrBadRequest, v)
+			}
+
+			value, _ = indirect(value)
+			keyValues[v] = value
+			keyNames = append(keyNames, fmt.Sprintf("%v", value))
+		}
+
+		key := strings.Join(keyNames, "\x00")
+
+		g, ok := groups[key]
+		if !ok {
+			g = &group{
+				row:     AggregateRow{Keys: keyValues, Values: make(map[string]float64)},
+				sums:    make(map[string]float64),
+				counted: make(map[string]int),
+			}
+
+			groups[key] = g
+			keys = append(keys, key)
+		}
+
+		g.count++
+
+		for _, v := range aggregation.Aggregates {
+			if v.Function == AggregateCount {
+				continue
+			}
+
+			value, ok := FieldValue(entity, v.Field)
+			if !ok {
+				return nil, fmt.Errorf("%w unknown field %s", ErrBadRequest, v.Field)
+			}
+
+			value, isNil := indirect(value)
+			if isNil {
+				continue
+			}
+
+			number, ok := toFloat(value)
+			if !ok {
+				return nil, fmt.Errorf("%w field %s is not numeric", ErrBadRequest, v.Field)
+			}
+
+			current, seen := g.row.Values[v.Alias]
+
+			switch v.Function {
+			case AggregateMin:
+				if !seen || number < current {
+					g.row.Values[v.Alias] = number
+				}
+			case AggregateMax:
+				if !seen || number > current {
+					g.row.Values[v.Alias] = number
+				}
+			default:
+				g.sums[v.Alias] += number
+			}
+
+			g.counted[v.Alias]++
+		}
+	}
+
+	rows := make([]AggregateRow, 0, len(groups))
+
+	for _, key := range keys {
+		g := groups[key]
+
+		for _, v := range aggregation.Aggregates {
+			switch v.Function {
+			case AggregateCount:
+				g.row.Values[v.Alias] = float64(g.count)
+			case AggregateSum:
+				g.row.Values[v.Alias] = g.sums[v.Alias]
+			case AggregateMin, AggregateMax:
+				g.row.Values[v.Alias] += 0
+			case AggregateAvg:
+				g.row.Values[v.Alias] = 0
+
+				if g.counted[v.Alias] > 0 {
+					g.row.Values[v.Alias] = g.sums[v.Alias] / float64(g.counted[v.Alias])
+				}
+			}
+		}
+
+		rows = append(rows, g.row)
+	}
+
+	sortAggregateRows(rows, aggregation.GroupBy)
+
+	return rows, nil
+}
+
+// sortAggregateRows orders the rows by their keys in the group by order
+func sortAggregateRows(rows []AggregateRow, groupBy []string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, v := range groupBy {
+			result, ok := compare(rows[i].Keys[v], rows[j].Keys[v])
+			if !ok || result == 0 {
+				continue
+			}
+
+			return result < 0
+		}
+
+		return false
+	})
+}
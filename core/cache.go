@@ -179,3 +179,23 @@ func (c *cacheProxy) Delete(ctx context.Context, key string) error {
 func (c *cacheProxy) BatchSet(ctx context.Context, kvs []KV) error {
 	return c.adapter.BatchSet(ctx, kvs)
 }
+
+func (c *cacheProxy) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	c.cache.Delete(key)
+
+	return c.adapter.SetWithTTL(ctx, key, value, ttl)
+}
+
+// SetIfAbsent is answered by the adapter, the local cache can not be atomic across the processes
+func (c *cacheProxy) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	c.cache.Delete(key)
+
+	return c.adapter.SetIfAbsent(ctx, key, value, ttl)
+}
+
+// Increment is answered by the adapter, the local cache can not be atomic across the processes
+func (c *cacheProxy) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	c.cache.Delete(key)
+
+	return c.adapter.Increment(ctx, key, delta, ttl)
+}
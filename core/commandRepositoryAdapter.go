@@ -17,4 +17,9 @@ type commandRepositoryAdapter interface {
 	AddRange(ctx context.Context, entities []Entitier) error
 	Update(ctx context.Context, entity Entitier) error
 	UpdateRange(ctx context.Context, entities []Entitier) error
+	Upsert(ctx context.Context, entity Entitier) (affected int64, err error)
+	UpsertRange(ctx context.Context, entities []Entitier) (affected int64, err error)
+	Patch(ctx context.Context, id uuid.UUID, fields map[string]interface{}) (affected int64, err error)
+	UpdateWhere(ctx context.Context, query interface{}, fields map[string]interface{}) (affected int64, err error)
+	RemoveWhere(ctx context.Context, query interface{}) (affected int64, err error)
 }
@@ -0,0 +1,182 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type ConnectionState int32
+
+const (
+	StateConnected ConnectionState = iota
+	StateReconnecting
+	StateClosed
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case StateConnected:
+		return "connected"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+type connector interface {
+	IsConnected() bool
+	Connect(ctx context.Context) error
+	Disconnect()
+}
+
+// connectionSupervisor owns the connection of an adapter,
+// calls are guarded by a read lock so reconnecting never closes a client in use,
+// reconnecting stops when the supervisor is closed
+type connectionSupervisor struct {
+	name              string
+	adapter           connector
+	connectionTimeout time.Duration
+	settings          ReconnectSettings
+	state             int32
+	mutex             sync.RWMutex
+	// serializes connecting with closing, it never blocks the calls
+	connecting sync.Mutex
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+func newConnectionSupervisor(name string, adapter connector, connectionTimeout time.Duration, settings ReconnectSettings) *connectionSupervisor {
+	if settings.Multiplier < 1 {
+		settings.Multiplier = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &connectionSupervisor{
+		name:              name,
+		adapter:           adapter,
+		connectionTimeout: connectionTimeout,
+		settings:          settings,
+		ctx:               ctx,
+		cancel:            cancel,
+	}
+}
+
+func (s *connectionSupervisor) GetState() ConnectionState {
+	return ConnectionState(atomic.LoadInt32(&s.state))
+}
+
+func (s *connectionSupervisor) connect() error {
+	ctx, cancel := context.WithTimeout(s.ctx, s.connectionTimeout)
+	defer cancel()
+
+	return s.adapter.Connect(ctx)
+}
+
+func (s *connectionSupervisor) acquire() bool {
+	if s.GetState() != StateConnected {
+		return false
+	}
+
+	s.mutex.RLock()
+
+	// reconnecting may have started while waiting for the lock
+	if s.GetState() != StateConnected {
+		s.mutex.RUnlock()
+
+		return false
+	}
+
+	return true
+}
+
+func (s *connectionSupervisor) release() {
+	s.mutex.RUnlock()
+}
+
+func (s *connectionSupervisor) reconnect() {
+	if !atomic.CompareAndSwapInt32(&s.state, int32(StateConnected), int32(StateReconnecting)) {
+		return
+	}
+
+	s.onStateChange(StateConnected, StateReconnecting)
+
+	go s.supervise()
+}
+
+// supervise connects outside of the lock, the calls are already rejected while reconnecting
+func (s *connectionSupervisor) supervise() {
+	interval := s.settings.InitialInterval
+
+	for {
+		// waits for the calls in use before disconnecting
+		s.mutex.Lock()
+
+		s.adapter.Disconnect()
+
+		s.mutex.Unlock()
+
+		s.connecting.Lock()
+
+		err := s.connect()
+
+		connected := err == nil && atomic.CompareAndSwapInt32(&s.state, int32(StateReconnecting), int32(StateConnected))
+
+		s.connecting.Unlock()
+
+		if connected {
+			s.onStateChange(StateReconnecting, StateConnected)
+
+			return
+		}
+
+		timer := time.NewTimer(interval)
+
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+
+			return
+		case <-timer.C:
+		}
+
+		interval = time.Duration(float64(interval) * s.settings.Multiplier)
+
+		if s.settings.MaxInterval > 0 && interval > s.settings.MaxInterval {
+			interval = s.settings.MaxInterval
+		}
+	}
+}
+
+// close stops reconnecting and disconnects the adapter after the calls in use
+func (s *connectionSupervisor) close() {
+	from := ConnectionState(atomic.SwapInt32(&s.state, int32(StateClosed)))
+
+	if from == StateClosed {
+		return
+	}
+
+	s.cancel()
+
+	s.connecting.Lock()
+	defer s.connecting.Unlock()
+
+	s.mutex.Lock()
+
+	s.adapter.Disconnect()
+
+	s.mutex.Unlock()
+
+	s.onStateChange(from, StateClosed)
+}
+
+func (s *connectionSupervisor) onStateChange(from ConnectionState, to ConnectionState) {
+	if s.settings.OnStateChange != nil {
+		s.settings.OnStateChange(s.name, from.String(), to.String())
+	}
+}
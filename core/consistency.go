@@ -0,0 +1,46 @@
+package core
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type writeMarker struct {
+	written int32
+}
+
+type writeMarkerKey struct{}
+
+type primaryKey struct{}
+
+// WithReadYourWrites tracks the writes of ctx, the reads following a write of ctx go to the primary.
+// The mediator tracks every request, the existing tracking of ctx is kept
+func WithReadYourWrites(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(writeMarkerKey{}).(*writeMarker); ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, writeMarkerKey{}, new(writeMarker))
+}
+
+// WithPrimary reads every query of ctx from the primary
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// RequiresPrimary reports whether the reads of ctx must not go to the replicas
+func RequiresPrimary(ctx context.Context) bool {
+	if primary, _ := ctx.Value(primaryKey{}).(bool); primary {
+		return true
+	}
+
+	marker, ok := ctx.Value(writeMarkerKey{}).(*writeMarker)
+
+	return ok && atomic.LoadInt32(&marker.written) == 1
+}
+
+func markWritten(ctx context.Context) {
+	if marker, ok := ctx.Value(writeMarkerKey{}).(*writeMarker); ok {
+		atomic.StoreInt32(&marker.written, 1)
+	}
+}
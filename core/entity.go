@@ -29,6 +29,10 @@ func (e *Entity) SetID(id uuid.UUID) {
 	e.ID = id
 }
 
+func (e *Entity) GetCreatedAt() time.Time {
+	return e.CreatedAt
+}
+
 func (e *Entity) SetCreatedAt(user string, timestamp time.Time) {
 	e.CreateUser = user
 	e.CreatedAt = timestamp
@@ -1,6 +1,9 @@
 package core
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrInternalServerError = errors.New("internal Server Error")
@@ -8,4 +11,107 @@ var (
 	ErrConflict            = errors.New("your Item already exist")
 	ErrBadRequest          = errors.New("given Param is not valid")
 	ErrForbiddenAcccess    = errors.New("your access is forbidden")
+	ErrUnauthorized        = errors.New("your credentials are not valid")
+	ErrUnavailable         = errors.New("service is unavailable")
+	ErrTimeout             = errors.New("your request is timed out")
+	ErrPreconditionFailed  = errors.New("given Precondition is failed")
+)
+
+// Codes of the driver errors translated by the repository adapters
+const (
+	CodeUniqueViolation     = "unique_violation"
+	CodeForeignKeyViolation = "foreign_key_violation"
+	CodeNotFound            = "not_found"
+	CodeTimeout             = "timeout"
 )
+
+// Error is a structured error of a sentinel kind,
+// errors.Is matches both the kind and the cause
+type Error struct {
+	Kind      error
+	Code      string
+	Message   string
+	Details   map[string]interface{}
+	Cause     error
+	Retryable bool
+}
+
+func NewError(kind error, code string, message string) *Error {
+	if kind == nil {
+		kind = ErrInternalServerError
+	}
+
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *Error) WithDetail(key string, value interface{}) *Error {
+	if e.Details == nil {
+		e.Details = make(map[string]interface{})
+	}
+
+	e.Details[key] = value
+
+	return e
+}
+
+func (e *Error) WithCause(cause error) *Error {
+	e.Cause = cause
+
+	return e
+}
+
+func (e *Error) AsRetryable() *Error {
+	e.Retryable = true
+
+	return e
+}
+
+func (e *Error) Error() string {
+	message := e.Message
+
+	if message == "" {
+		message = e.Kind.Error()
+	}
+
+	if e.Code != "" {
+		message = fmt.Sprintf("%s: %s", e.Code, message)
+	}
+
+	if e.Cause != nil {
+		message = fmt.Sprintf("%s: %v", message, e.Cause)
+	}
+
+	return message
+}
+
+// clientMessage renders the error to the clients, the cause of the core error is never rendered
+// as it carries the driver internals like the constraint and the table names
+func clientMessage(err error) string {
+	var coreError *Error
+
+	if !errors.As(err, &coreError) {
+		return err.Error()
+	}
+
+	if coreError.Message != "" {
+		return coreError.Message
+	}
+
+	if coreError.Kind != nil {
+		return coreError.Kind.Error()
+	}
+
+	return ErrInternalServerError.Error()
+}
+
+func (e *Error) Is(target error) bool {
+	return e.Kind != nil && errors.Is(e.Kind, target)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
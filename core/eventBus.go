@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/enriquebris/goconcurrentqueue"
@@ -16,6 +17,10 @@ type eventBus struct {
 	domainEvents *goconcurrentqueue.FIFO
 	ch           chan rxgo.Item
 	cb           *gobreaker.CircuitBreaker
+	retry        *retrier
+	supervisor   *connectionSupervisor
+	listeners    []DomainEventListener
+	mutex        sync.RWMutex
 	settings     EventBusSettings
 }
 
@@ -30,7 +35,7 @@ func bufferedEventHandler(ctx context.Context, notification interface{}) error {
 }
 
 func (e *eventBus) initialize() *eventBus {
-	if err := e.connect(); err != nil {
+	if err := e.supervisor.connect(); err != nil {
 		panic(err)
 	}
 
@@ -42,19 +47,33 @@ func (e *eventBus) initialize() *eventBus {
 	return e
 }
 
-func (e *eventBus) connect() error {
-	ctx, cancel := context.WithTimeout(context.Background(), e.settings.ConnectionTimeout)
-	defer cancel()
+func (e *eventBus) onCircuitOpen() {
+	e.supervisor.reconnect()
+}
 
-	return e.messaging.Connect(ctx)
+// Close stops reconnecting and disconnects the messaging adapter, the calls fail after it is closed
+func (e *eventBus) Close() {
+	e.supervisor.close()
 }
 
-func (e *eventBus) onCircuitOpen() {
-	e.messaging.Disconnect()
+func (e *eventBus) execute(ctx context.Context, req func() (interface{}, error)) (interface{}, error) {
+	if !e.supervisor.acquire() {
+		return nil, gobreaker.ErrOpenState
+	}
 
-	if !e.messaging.IsConnected() {
-		e.connect()
+	defer e.supervisor.release()
+
+	return e.retry.execute(ctx, e.cb, req)
+}
+
+func (e *eventBus) executeOnce(req func() (interface{}, error)) (interface{}, error) {
+	if !e.supervisor.acquire() {
+		return nil, gobreaker.ErrOpenState
 	}
+
+	defer e.supervisor.release()
+
+	return e.cb.Execute(req)
 }
 
 func (e *eventBus) subscribeBufferedEvent(observable rxgo.Observable) {
@@ -110,37 +129,59 @@ func (e *eventBus) PublishDomainEvents(ctx context.Context) error {
 		item, _ := e.domainEvents.Dequeue()
 		event := item.(DomainEventer)
 
-		_, err = e.cb.Execute(func() (interface{}, error) {
-			err = e.mediator.Publish(ctx, event)
-			if err != nil {
-				return nil, err
-			}
+		// the handlers and the listeners are not idempotent, only publishing to the event source is retried
+		_, err = e.executeOnce(func() (interface{}, error) {
+			return nil, e.mediator.Publish(ctx, event)
+		})
 
-			if event.GetCanNotPublishToEventsource() {
-				return nil, nil
-			}
+		if err != nil {
+			continue
+		}
 
-			event.SetPublishingEvent(ctx, now)
+		e.notifyListeners(ctx, event)
 
-			if event.GetCanBuffered() {
-				e.ch <- rxgo.Item{
-					V: event,
-				}
-				return nil, nil
-			}
+		if event.GetCanNotPublishToEventsource() {
+			continue
+		}
 
-			err = e.Publish(ctx, event)
-			if err != nil {
-				return nil, err
+		event.SetPublishingEvent(ctx, now)
+
+		if event.GetCanBuffered() {
+			e.ch <- rxgo.Item{
+				V: event,
 			}
+			continue
+		}
 
-			return nil, nil
+		_, err = e.execute(ctx, func() (interface{}, error) {
+			return nil, e.Publish(ctx, event)
 		})
 	}
 
 	return err
 }
 
+// AddDomainEventListener registers a listener which is called after a domain event is published to the mediator
+func (e *eventBus) AddDomainEventListener(listener DomainEventListener) {
+	if listener == nil {
+		panic("listener is required")
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.listeners = append(e.listeners, listener)
+}
+
+func (e *eventBus) notifyListeners(ctx context.Context, event DomainEventer) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	for _, listener := range e.listeners {
+		listener(ctx, event)
+	}
+}
+
 func (e *eventBus) Publish(ctx context.Context, event DomainEventer) error {
 	return e.messaging.Publish(ctx, event)
 }
@@ -11,10 +11,12 @@ import (
 
 // Builder Object for EventBus
 type eventBusBuilder struct {
-	mediator   *mediator
-	messaging  messagingAdapter
-	cbSettings CircuitBreakerSettings
-	settings   EventBusSettings
+	mediator          *mediator
+	messaging         messagingAdapter
+	cbSettings        CircuitBreakerSettings
+	retrySettings     RetrySettings
+	reconnectSettings ReconnectSettings
+	settings          EventBusSettings
 }
 
 // Constructor for EventBusBuilder
@@ -26,6 +28,18 @@ func NewEventBusBuilder() *eventBusBuilder {
 		SamplingDuration:         time.Duration(60 * time.Second),
 		SamplingFailureCount:     5,
 	}
+	o.retrySettings = RetrySettings{
+		MaxAttempts:     1,
+		InitialInterval: time.Duration(100 * time.Millisecond),
+		MaxInterval:     time.Duration(10 * time.Second),
+		Multiplier:      2,
+		JitterFactor:    0.5,
+	}
+	o.reconnectSettings = ReconnectSettings{
+		InitialInterval: time.Duration(1 * time.Second),
+		MaxInterval:     time.Duration(60 * time.Second),
+		Multiplier:      2,
+	}
 	o.settings = EventBusSettings{
 		BufferedEventBufferCount: 1000,
 		BufferedEventBufferTime:  time.Duration(1 * time.Second),
@@ -67,6 +81,10 @@ func (b *eventBusBuilder) Create() *eventBus {
 
 	instance.cb = b.cbSettings.ToCircuitBreaker("eventbus", instance.onCircuitOpen)
 
+	instance.retry = b.retrySettings.ToRetrier()
+
+	instance.supervisor = newConnectionSupervisor("eventbus", b.messaging, b.settings.ConnectionTimeout, b.reconnectSettings)
+
 	instance.initialize()
 
 	return instance
@@ -94,6 +112,28 @@ func (b *eventBusBuilder) CircuitBreaker(settings CircuitBreakerSettings) *event
 	return b
 }
 
+// Builder method to set the field retry in EventBusBuilder
+func (b *eventBusBuilder) Retry(settings RetrySettings) *eventBusBuilder {
+	err := model.Copy(&b.retrySettings, settings)
+
+	if err != nil {
+		panic(fmt.Errorf("retry settings mapping errors occurred: %v", err))
+	}
+
+	return b
+}
+
+// Builder method to set the field reconnect in EventBusBuilder
+func (b *eventBusBuilder) Reconnect(settings ReconnectSettings) *eventBusBuilder {
+	err := model.Copy(&b.reconnectSettings, settings)
+
+	if err != nil {
+		panic(fmt.Errorf("reconnect settings mapping errors occurred: %v", err))
+	}
+
+	return b
+}
+
 // Builder method to set the field messaging in EventBusBuilder
 func (b *eventBusBuilder) CustomMediator(mediator *mediator) *eventBusBuilder {
 	if mediator == nil {
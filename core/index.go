@@ -0,0 +1,163 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type IndexField struct {
+	Field      string
+	Descending bool
+}
+
+// Index is declared by the model, adapters create it on connect.
+// TTL expires the entities by the single time field, Text indexes the string fields for the full text search
+type Index struct {
+	Name   string
+	Fields []IndexField
+	Unique bool
+	TTL    time.Duration
+	Text   bool
+}
+
+// Indexer declares the indexes which can not be expressed by the index tags
+type Indexer interface {
+	Indexes() []Index
+}
+
+// IndexesOf returns the indexes of the model, declared by Indexer and by the index tags.
+// The tag is `index:"name,unique,desc,text,ttl=24h"`, the fields with the same name form a compound index
+// in the order of the fields, an empty name indexes the field alone
+func IndexesOf(model interface{}) ([]Index, error) {
+	indexes := make([]Index, 0)
+
+	if indexer, ok := model.(Indexer); ok {
+		indexes = append(indexes, indexer.Indexes()...)
+	}
+
+	typeOf := reflect.TypeOf(model)
+
+	for typeOf != nil && typeOf.Kind() == reflect.Ptr {
+		typeOf = typeOf.Elem()
+	}
+
+	if typeOf == nil || typeOf.Kind() != reflect.Struct {
+		return indexes, nil
+	}
+
+	tagged, err := taggedIndexes(typeOf, make([]Index, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	indexes = append(indexes, tagged...)
+
+	names := make(map[string]bool, len(indexes))
+
+	for _, v := range indexes {
+		if v.Name == "" || len(v.Fields) == 0 {
+			return nil, fmt.Errorf("%w index requires name and fields", ErrInternalServerError)
+		}
+
+		if names[v.Name] {
+			return nil, fmt.Errorf("%w index %s is duplicated", ErrInternalServerError, v.Name)
+		}
+
+		if v.TTL > 0 && len(v.Fields) > 1 {
+			return nil, fmt.Errorf("%w ttl index %s must have a single field", ErrInternalServerError, v.Name)
+		}
+
+		names[v.Name] = true
+	}
+
+	return indexes, nil
+}
+
+func taggedIndexes(typeOf reflect.Type, indexes []Index) ([]Index, error) {
+	for i := 0; i < typeOf.NumField(); i++ {
+		f := typeOf.Field(i)
+
+		if f.Anonymous {
+			fieldType := f.Type
+
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+
+			if fieldType.Kind() == reflect.Struct {
+				var err error
+
+				if indexes, err = taggedIndexes(fieldType, indexes); err != nil {
+					return nil, err
+				}
+			}
+
+			continue
+		}
+
+		tag, ok := f.Tag.Lookup("index")
+		if !ok {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+
+		name := strings.TrimSpace(parts[0])
+		if name == "" {
+			name = "idx_" + strings.ToLower(f.Name)
+		}
+
+		field := IndexField{Field: f.Name}
+		index := Index{Name: name}
+
+		for _, v := range parts[1:] {
+			v = strings.TrimSpace(v)
+
+			switch {
+			case v == "unique":
+				index.Unique = true
+			case v == "desc":
+				field.Descending = true
+			case v == "text":
+				index.Text = true
+			case strings.HasPrefix(v, "ttl="):
+				ttl, err := time.ParseDuration(strings.TrimPrefix(v, "ttl="))
+				if err != nil {
+					return nil, fmt.Errorf("%w index %s: %v", ErrInternalServerError, name, err)
+				}
+
+				index.TTL = ttl
+			case v == "":
+			default:
+				return nil, fmt.Errorf("%w index %s has unknown option %s", ErrInternalServerError, name, v)
+			}
+		}
+
+		merged := false
+
+		for j := range indexes {
+			if indexes[j].Name != name {
+				continue
+			}
+
+			indexes[j].Fields = append(indexes[j].Fields, field)
+			indexes[j].Unique = indexes[j].Unique || index.Unique
+			indexes[j].Text = indexes[j].Text || index.Text
+
+			if index.TTL > 0 {
+				indexes[j].TTL = index.TTL
+			}
+
+			merged = true
+		}
+
+		if !merged {
+			index.Fields = []IndexField{field}
+			indexes = append(indexes, index)
+		}
+	}
+
+	return indexes, nil
+}
@@ -34,6 +34,11 @@ func (m *mediator) AddMiddleware(next behavior) behavior {
 	return m.middleware
 }
 
+// HasRequestHandler reports whether the handler of the request type is added, Send panics without it
+func (m *mediator) HasRequestHandler(request Request) bool {
+	return m.requestHandlers.Has(reflect.TypeOf(request).Elem().Name())
+}
+
 func (m *mediator) Send(ctx context.Context, request Request) Result {
 	if err := ctx.Err(); err != nil {
 		return Result{E: err}
@@ -47,7 +52,7 @@ func (m *mediator) Send(ctx context.Context, request Request) Result {
 		panic("request handler not found, you should register handler before use it")
 	}
 
-	span, ctx := opentracing.StartSpanFromContext(ctx, typeName)
+	span, ctx := opentracing.StartSpanFromContext(WithReadYourWrites(ctx), typeName)
 	if span != nil {
 		defer span.Finish()
 	}
@@ -67,6 +72,7 @@ func (m *mediator) Send(ctx context.Context, request Request) Result {
 
 func (m *mediator) next(ctx context.Context, request Request, handler RequestHandler) Result {
 	if m.middleware != nil {
+		ctx = context.WithValue(ctx, handlerKey{}, handler)
 		m.middleware.setParameters(ctx, request, handler)
 		return m.middleware.Run(ctx, request)
 	} else {
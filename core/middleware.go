@@ -1,6 +1,9 @@
 package core
 
-import "context"
+import (
+	"context"
+	"sync"
+)
 
 type behavior interface {
 	AddNext(next behavior) behavior
@@ -14,27 +17,61 @@ type Middleware struct {
 	request Request
 	handler RequestHandler
 	next    behavior
+	mutex   sync.RWMutex
 }
 
+type handlerKey struct{}
+
 func (m *Middleware) AddNext(next behavior) behavior {
 	m.next = next
 	return m.next
 }
 
+// Next reads the parameters which are shared by the concurrent requests.
+//
+// Deprecated: use NextWith
 func (m *Middleware) Next() Result {
-	if err := m.ctx.Err(); err != nil {
+	m.mutex.RLock()
+	ctx, request, handler := m.ctx, m.request, m.handler
+	m.mutex.RUnlock()
+
+	if err := ctx.Err(); err != nil {
 		return Result{E: err}
 	}
 
 	if m.next != nil {
-		m.next.setParameters(m.ctx, m.request, m.handler)
-		return m.next.Run(m.ctx, m.request)
+		m.next.setParameters(ctx, request, handler)
+		return m.next.Run(ctx, request)
 	} else {
-		return m.handler(m.ctx, m.request)
+		return handler(ctx, request)
+	}
+}
+
+// NextWith is the concurrency safe Next, every middleware should use it
+func (m *Middleware) NextWith(ctx context.Context, request Request) Result {
+	if err := ctx.Err(); err != nil {
+		return Result{E: err}
+	}
+
+	handler, ok := ctx.Value(handlerKey{}).(RequestHandler)
+	if !ok {
+		m.mutex.RLock()
+		handler = m.handler
+		m.mutex.RUnlock()
+	}
+
+	if m.next != nil {
+		m.next.setParameters(ctx, request, handler)
+		return m.next.Run(ctx, request)
+	} else {
+		return handler(ctx, request)
 	}
 }
 
 func (m *Middleware) setParameters(ctx context.Context, request Request, handler RequestHandler) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	m.ctx = ctx
 	m.request = request
 	m.handler = handler
@@ -0,0 +1,151 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+type Order struct {
+	Field      string
+	Descending bool
+}
+
+// QueryOptions is passed to the query adapters to order, page and project the results
+type QueryOptions struct {
+	OrderBy []Order
+	Offset  int
+	Limit   int
+	Fields  []string
+}
+
+type PageRequest struct {
+	OrderBy []Order
+	Offset  int
+	Limit   int
+	// Cursor is the NextCursor of the previous page, it takes precedence over Offset
+	Cursor string
+	// Fields projects the results, ID and the order fields are always included
+	Fields []string
+}
+
+type Page struct {
+	Items      interface{}
+	TotalCount int64
+	NextCursor string
+	HasMore    bool
+}
+
+// CompareByOrder orders two entities in memory, it returns negative when a comes first
+func CompareByOrder(a interface{}, b interface{}, orderBy []Order) int {
+	for _, v := range orderBy {
+		x, _ := FieldValue(a, v.Field)
+		y, _ := FieldValue(b, v.Field)
+
+		result, ok := compare(x, y)
+		if !ok || result == 0 {
+			continue
+		}
+
+		if v.Descending {
+			return -result
+		}
+
+		return result
+	}
+
+	return 0
+}
+
+// keysetOrder appends ID as the tie breaker of the keyset
+func keysetOrder(orderBy []Order) []Order {
+	orders := make([]Order, 0, len(orderBy)+1)
+
+	for _, v := range orderBy {
+		if v.Field == "ID" {
+			return append(orders, v)
+		}
+
+		orders = append(orders, v)
+	}
+
+	return append(orders, Order{Field: "ID"})
+}
+
+// keysetSpecification matches the entities after the cursor
+// (f1 > v1) OR (f1 = v1 AND f2 > v2) OR ...
+func keysetSpecification(orderBy []Order, values []interface{}) *Specification {
+	specs := make([]*Specification, 0, len(orderBy))
+
+	for i, v := range orderBy {
+		and := make([]*Specification, 0, i+1)
+
+		for j := 0; j < i; j++ {
+			and = append(and, Eq(orderBy[j].Field, values[j]))
+		}
+
+		if v.Descending {
+			and = append(and, Lt(v.Field, values[i]))
+		} else {
+			and = append(and, Gt(v.Field, values[i]))
+		}
+
+		specs = append(specs, And(and...))
+	}
+
+	return Or(specs...)
+}
+
+func encodeCursor(entity interface{}, orderBy []Order) (string, error) {
+	values := make([]interface{}, 0, len(orderBy))
+
+	for _, v := range orderBy {
+		value, ok := FieldValue(entity, v.Field)
+		if !ok {
+			return "", fmt.Errorf("%w unknown field %s", ErrBadRequest, v.Field)
+		}
+
+		values = append(values, value)
+	}
+
+	bytes, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// decodeCursor restores the typed values of the order fields from the model
+func decodeCursor(cursor string, model interface{}, orderBy []Order) ([]interface{}, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w invalid cursor", ErrBadRequest)
+	}
+
+	raws := make([]json.RawMessage, 0)
+
+	if err := json.Unmarshal(bytes, &raws); err != nil || len(raws) != len(orderBy) {
+		return nil, fmt.Errorf("%w invalid cursor", ErrBadRequest)
+	}
+
+	values := make([]interface{}, len(raws))
+
+	for i, v := range orderBy {
+		field, ok := FieldValue(model, v.Field)
+		if !ok {
+			return nil, fmt.Errorf("%w unknown field %s", ErrBadRequest, v.Field)
+		}
+
+		value := reflect.New(reflect.TypeOf(field))
+
+		if err := json.Unmarshal(raws[i], value.Interface()); err != nil {
+			return nil, fmt.Errorf("%w invalid cursor", ErrBadRequest)
+		}
+
+		values[i] = value.Elem().Interface()
+	}
+
+	return values, nil
+}
@@ -0,0 +1,68 @@
+package core
+
+import "context"
+
+// Principal is the authenticated caller of the request
+type Principal struct {
+	UserId string
+	Roles  []string
+	Claims map[string]interface{}
+}
+
+type principalKey struct{}
+
+type verifiedKey struct{}
+
+type userIdKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+
+	return principal, ok && principal != nil
+}
+
+// WithUserId keeps the raw user id of the request which is supplied by the client,
+// the routers put it whatever the name of their header is
+func WithUserId(ctx context.Context, userId string) context.Context {
+	return context.WithValue(ctx, userIdKey{}, userId)
+}
+
+func UserIdFromContext(ctx context.Context) (string, bool) {
+	userId, ok := ctx.Value(userIdKey{}).(string)
+
+	return userId, ok && userId != ""
+}
+
+// WithVerifier marks ctx as passed through a token verifier,
+// the raw user id in ctx is never trusted then because it is supplied by the client
+func WithVerifier(ctx context.Context) context.Context {
+	return context.WithValue(ctx, verifiedKey{}, true)
+}
+
+func HasVerifier(ctx context.Context) bool {
+	verified, _ := ctx.Value(verifiedKey{}).(bool)
+
+	return verified
+}
+
+func (p *Principal) IsInRole(roles ...string) bool {
+	for _, role := range roles {
+		for _, v := range p.Roles {
+			if v == role {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (p *Principal) GetClaim(key string) (interface{}, bool) {
+	value, ok := p.Claims[key]
+
+	return value, ok
+}
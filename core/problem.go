@@ -0,0 +1,94 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// ProblemDetails is the RFC 7807 representation of a failed Result
+type ProblemDetails struct {
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title"`
+	Status     int                    `json:"status"`
+	Detail     string                 `json:"detail,omitempty"`
+	Instance   string                 `json:"instance,omitempty"`
+	Code       string                 `json:"code,omitempty"`
+	Retryable  bool                   `json:"retryable,omitempty"`
+	Errors     []FieldError           `json:"errors,omitempty"`
+	Extensions map[string]interface{} `json:"-"`
+}
+
+func (r *Result) ToProblemDetails(instance string) *ProblemDetails {
+	statusCode := r.ToHttpStatus()
+
+	problem := &ProblemDetails{
+		Type:     "about:blank",
+		Title:    http.StatusText(statusCode),
+		Status:   statusCode,
+		Instance: instance,
+	}
+
+	if r.E == nil {
+		return problem
+	}
+
+	// server errors do not leak their internals
+	if statusCode < http.StatusInternalServerError {
+		problem.Detail = clientMessage(r.E)
+	}
+
+	var coreError *Error
+
+	if errors.As(r.E, &coreError) {
+		problem.Code = coreError.Code
+		problem.Retryable = coreError.Retryable
+		problem.Extensions = coreError.Details
+	}
+
+	var validationError *ValidationError
+
+	if errors.As(r.E, &validationError) {
+		problem.Errors = validationError.Fields
+	}
+
+	return problem
+}
+
+func (p *ProblemDetails) MarshalJSON() ([]byte, error) {
+	type problemDetails ProblemDetails
+
+	body, err := json.Marshal((*problemDetails)(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return body, err
+	}
+
+	members := make(map[string]interface{})
+
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+
+	// standard members always win over extensions
+	if err := json.Unmarshal(body, &members); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(members)
+}
+
+// WriteProblem writes the Result as a problem+json response
+func (r *Result) WriteProblem(w http.ResponseWriter, instance string) error {
+	problem := r.ToProblemDetails(instance)
+
+	if problem.Status == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(problem.Status)
+
+	return json.NewEncoder(w).Encode(problem)
+}
@@ -18,4 +18,7 @@ type queryRepositoryAdapter interface {
 	CountWithFilter(ctx context.Context, query interface{}, args interface{}) (count int64, err error)
 	List(ctx context.Context, dest interface{}) (err error)
 	ListWithFilter(ctx context.Context, query interface{}, args interface{}, dest interface{}) error
+	ListWithOptions(ctx context.Context, query interface{}, options QueryOptions, dest interface{}) error
+	Iterate(ctx context.Context, query interface{}, fn func(entity Entitier) error) error
+	Aggregate(ctx context.Context, query interface{}, aggregation Aggregation) ([]AggregateRow, error)
 }
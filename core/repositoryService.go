@@ -14,50 +14,93 @@ import (
 type repositoryService struct {
 	tableName         string
 	userIdKey         string
+	model             Entitier
 	queryRepository   queryRepositoryAdapter
 	commandRepository commandRepositoryAdapter
 	cb                *gobreaker.CircuitBreaker
+	retry             *retrier
+	querySupervisor   *connectionSupervisor
+	commandSupervisor *connectionSupervisor
 	settings          RepositoryServiceSettings
 }
 
 func (r *repositoryService) initialize() *repositoryService {
-	if err := r.queryRepositoryConnect(); err != nil {
+	if err := r.querySupervisor.connect(); err != nil {
 		panic(err)
 	}
 
-	if err := r.commandRepositoryConnect(); err != nil {
-		panic(err)
+	if r.commandSupervisor != r.querySupervisor {
+		if err := r.commandSupervisor.connect(); err != nil {
+			panic(err)
+		}
 	}
 
 	return r
 }
 
-func (r *repositoryService) queryRepositoryConnect() error {
-	ctx, cancel := context.WithTimeout(context.Background(), r.settings.ConnectionTimeout)
-	defer cancel()
+func (r *repositoryService) onCircuitOpen() {
+	r.querySupervisor.reconnect()
 
-	return r.queryRepository.Connect(ctx)
+	r.commandSupervisor.reconnect()
 }
 
-func (r *repositoryService) commandRepositoryConnect() error {
-	ctx, cancel := context.WithTimeout(context.Background(), r.settings.ConnectionTimeout)
-	defer cancel()
+// Close stops reconnecting and disconnects the repository adapters, the calls fail after it is closed
+func (r *repositoryService) Close() {
+	r.querySupervisor.close()
 
-	return r.commandRepository.Connect(ctx)
+	if r.commandSupervisor != r.querySupervisor {
+		r.commandSupervisor.close()
+	}
 }
 
-func (r *repositoryService) onCircuitOpen() {
-	r.queryRepository.Disconnect()
+func (r *repositoryService) executeQuery(ctx context.Context, req func() (interface{}, error)) (interface{}, error) {
+	if !r.querySupervisor.acquire() {
+		return nil, gobreaker.ErrOpenState
+	}
+
+	defer r.querySupervisor.release()
 
-	r.commandRepository.Disconnect()
+	return r.execute(ctx, req)
+}
 
-	if !r.queryRepository.IsConnected() {
-		r.queryRepositoryConnect()
+func (r *repositoryService) executeCommand(ctx context.Context, req func() (interface{}, error)) (interface{}, error) {
+	if !r.commandSupervisor.acquire() {
+		return nil, gobreaker.ErrOpenState
 	}
 
-	if !r.commandRepository.IsConnected() {
-		r.commandRepositoryConnect()
+	defer r.commandSupervisor.release()
+
+	resp, err := r.execute(ctx, req)
+
+	if err == nil {
+		markWritten(ctx)
 	}
+
+	return resp, err
+}
+
+// execute answers the business errors through the circuit breaker as successes,
+// a missing or conflicting entity is answered by a healthy database
+func (r *repositoryService) execute(ctx context.Context, req func() (interface{}, error)) (interface{}, error) {
+	var rejected error
+
+	resp, err := r.retry.execute(ctx, r.cb, func() (interface{}, error) {
+		resp, err := req()
+
+		if isBusinessError(err) {
+			rejected = err
+
+			return resp, nil
+		}
+
+		return resp, err
+	})
+
+	if err == nil && rejected != nil {
+		return resp, rejected
+	}
+
+	return resp, err
 }
 
 func (r *repositoryService) Find(ctx context.Context, id uuid.UUID, dest Entitier) Result {
@@ -71,8 +114,22 @@ func (r *repositoryService) Find(ctx context.Context, id uuid.UUID, dest Entitie
 		defer span.Finish()
 	}
 
-	_, err := r.cb.Execute(func() (interface{}, error) {
-		return nil, r.queryRepository.Find(ctx, id, dest)
+	_, err := r.executeQuery(ctx, func() (interface{}, error) {
+		// the entity is loaded into a fresh value, dest never holds the entity out of scope
+		found := reflect.New(reflect.TypeOf(dest).Elem()).Interface().(Entitier)
+
+		if err := r.queryRepository.Find(ctx, id, found); err != nil {
+			return nil, err
+		}
+
+		// guards adapters which are not scoped by tenant or by the deleted scope
+		if !InScope(ctx, found) {
+			return nil, ErrNotFound
+		}
+
+		reflect.ValueOf(dest).Elem().Set(reflect.ValueOf(found).Elem())
+
+		return nil, nil
 	})
 
 	return Result{V: dest, E: err}
@@ -85,7 +142,7 @@ func (r *repositoryService) Any(ctx context.Context) Result {
 		defer span.Finish()
 	}
 
-	resp, err := r.cb.Execute(func() (interface{}, error) {
+	resp, err := r.executeQuery(ctx, func() (interface{}, error) {
 		return r.queryRepository.Any(ctx)
 	})
 
@@ -103,7 +160,7 @@ func (r *repositoryService) AnyWithFilter(ctx context.Context, query interface{}
 		defer span.Finish()
 	}
 
-	resp, err := r.cb.Execute(func() (interface{}, error) {
+	resp, err := r.executeQuery(ctx, func() (interface{}, error) {
 		return r.queryRepository.AnyWithFilter(ctx, query, args)
 	})
 
@@ -121,7 +178,7 @@ func (r *repositoryService) Count(ctx context.Context) Result {
 		defer span.Finish()
 	}
 
-	resp, err := r.cb.Execute(func() (interface{}, error) {
+	resp, err := r.executeQuery(ctx, func() (interface{}, error) {
 		return r.queryRepository.Count(ctx)
 	})
 
@@ -139,7 +196,7 @@ func (r *repositoryService) CountWithFilter(ctx context.Context, query interface
 		defer span.Finish()
 	}
 
-	resp, err := r.cb.Execute(func() (interface{}, error) {
+	resp, err := r.executeQuery(ctx, func() (interface{}, error) {
 		return r.queryRepository.CountWithFilter(ctx, query, args)
 	})
 
@@ -177,7 +234,7 @@ func (r *repositoryService) List(ctx context.Context, dest interface{}) Result {
 		defer span.Finish()
 	}
 
-	_, err := r.cb.Execute(func() (interface{}, error) {
+	_, err := r.executeQuery(ctx, func() (interface{}, error) {
 		return nil, r.queryRepository.List(ctx, dest)
 	})
 
@@ -211,13 +268,181 @@ func (r *repositoryService) ListWithFilter(ctx context.Context, query interface{
 		defer span.Finish()
 	}
 
-	_, err := r.cb.Execute(func() (interface{}, error) {
+	_, err := r.executeQuery(ctx, func() (interface{}, error) {
 		return nil, r.queryRepository.ListWithFilter(ctx, query, args, dest)
 	})
 
 	return Result{V: dest, E: err}
 }
 
+// Page lists a page of the entities which satisfy the specification, spec is optional
+func (r *repositoryService) Page(ctx context.Context, spec *Specification, page PageRequest, dest interface{}) Result {
+	if dest == nil {
+		return Result{E: fmt.Errorf("%w dest is required", ErrInternalServerError)}
+	}
+
+	resultsVal := reflect.ValueOf(dest)
+
+	if resultsVal.Kind() != reflect.Ptr || resultsVal.Elem().Kind() != reflect.Slice {
+		panic("dest must be a pointer to a slice")
+	}
+
+	if page.Limit < 1 {
+		return Result{E: fmt.Errorf("%w limit is required", ErrBadRequest)}
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Repository:Page")
+
+	if span != nil {
+		defer span.Finish()
+	}
+
+	orderBy := keysetOrder(page.OrderBy)
+
+	options := QueryOptions{
+		OrderBy: orderBy,
+		Offset:  page.Offset,
+		Limit:   page.Limit + 1,
+	}
+
+	if len(page.Fields) > 0 {
+		options.Fields = append(options.Fields, page.Fields...)
+
+		for _, v := range orderBy {
+			options.Fields = append(options.Fields, v.Field)
+		}
+	}
+
+	query := spec
+
+	if page.Cursor != "" {
+		values, err := decodeCursor(page.Cursor, r.model, orderBy)
+		if err != nil {
+			return Result{E: err}
+		}
+
+		options.Offset = 0
+
+		if spec == nil {
+			query = keysetSpecification(orderBy, values)
+		} else {
+			query = And(spec, keysetSpecification(orderBy, values))
+		}
+	}
+
+	resp, err := r.executeQuery(ctx, func() (interface{}, error) {
+		if spec == nil {
+			return r.queryRepository.Count(ctx)
+		}
+
+		return r.queryRepository.CountWithFilter(ctx, spec, nil)
+	})
+
+	if err != nil {
+		return Result{E: err}
+	}
+
+	result := &Page{
+		Items:      dest,
+		TotalCount: resp.(int64),
+	}
+
+	_, err = r.executeQuery(ctx, func() (interface{}, error) {
+		if query == nil {
+			return nil, r.queryRepository.ListWithOptions(ctx, nil, options, dest)
+		}
+
+		return nil, r.queryRepository.ListWithOptions(ctx, query, options, dest)
+	})
+
+	if err != nil {
+		return Result{E: err}
+	}
+
+	sliceVal := resultsVal.Elem()
+
+	if sliceVal.Len() > page.Limit {
+		sliceVal.Set(sliceVal.Slice(0, page.Limit))
+
+		result.HasMore = true
+
+		result.NextCursor, err = encodeCursor(sliceVal.Index(page.Limit-1).Interface(), orderBy)
+		if err != nil {
+			return Result{E: err}
+		}
+	}
+
+	return Result{V: result, E: nil}
+}
+
+// Iterate streams the entities which satisfy the specification one by one, spec is optional,
+// it is not retried because the entities could be handled already
+func (r *repositoryService) Iterate(ctx context.Context, spec *Specification, fn func(entity Entitier) error) Result {
+	if fn == nil {
+		return Result{E: fmt.Errorf("%w fn is required", ErrInternalServerError)}
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Repository:Iterate")
+
+	if span != nil {
+		defer span.Finish()
+	}
+
+	if !r.querySupervisor.acquire() {
+		return Result{E: gobreaker.ErrOpenState}
+	}
+
+	defer r.querySupervisor.release()
+
+	_, err := r.cb.Execute(func() (interface{}, error) {
+		if spec == nil {
+			return nil, r.queryRepository.Iterate(ctx, nil, fn)
+		}
+
+		return nil, r.queryRepository.Iterate(ctx, spec, fn)
+	})
+
+	return Result{V: nil, E: err}
+}
+
+// Aggregate groups the entities satisfying spec, the result is []AggregateRow ordered by the group keys
+func (r *repositoryService) Aggregate(ctx context.Context, spec *Specification, aggregation Aggregation) Result {
+	if err := aggregation.validate(); err != nil {
+		return Result{E: err}
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Repository:Aggregate")
+
+	if span != nil {
+		defer span.Finish()
+	}
+
+	rows, err := r.executeQuery(ctx, func() (interface{}, error) {
+		if spec == nil {
+			return r.queryRepository.Aggregate(ctx, nil, aggregation)
+		}
+
+		return r.queryRepository.Aggregate(ctx, spec, aggregation)
+	})
+
+	if err != nil {
+		return Result{E: err}
+	}
+
+	// every backend answers one row when nothing is grouped
+	if result := rows.([]AggregateRow); len(aggregation.GroupBy) == 0 && len(result) == 0 {
+		row := AggregateRow{Keys: map[string]interface{}{}, Values: make(map[string]float64)}
+
+		for _, v := range aggregation.Aggregates {
+			row.Values[v.Alias] = 0
+		}
+
+		rows = []AggregateRow{row}
+	}
+
+	return Result{V: rows, E: nil}
+}
+
 func (r *repositoryService) Remove(ctx context.Context, id uuid.UUID) Result {
 	if id == uuid.Nil {
 		return Result{E: fmt.Errorf("%w id is required", ErrInternalServerError)}
@@ -229,7 +454,11 @@ func (r *repositoryService) Remove(ctx context.Context, id uuid.UUID) Result {
 		defer span.Finish()
 	}
 
-	_, err := r.cb.Execute(func() (interface{}, error) {
+	if _, ok := r.model.(SoftDeleter); ok {
+		return r.softRemove(ctx, []uuid.UUID{id})
+	}
+
+	_, err := r.executeCommand(ctx, func() (interface{}, error) {
 		return nil, r.commandRepository.Remove(ctx, id)
 	})
 
@@ -247,7 +476,11 @@ func (r *repositoryService) RemoveRange(ctx context.Context, ids []uuid.UUID) Re
 		defer span.Finish()
 	}
 
-	_, err := r.cb.Execute(func() (interface{}, error) {
+	if _, ok := r.model.(SoftDeleter); ok {
+		return r.softRemove(ctx, ids)
+	}
+
+	_, err := r.executeCommand(ctx, func() (interface{}, error) {
 		return nil, r.commandRepository.RemoveRange(ctx, ids)
 	})
 
@@ -265,9 +498,11 @@ func (r *repositoryService) Add(ctx context.Context, entity Entitier) Result {
 		defer span.Finish()
 	}
 
-	_, err := r.cb.Execute(func() (interface{}, error) {
-		user, _ := ctx.Value(r.userIdKey).(string)
+	_, err := r.executeCommand(ctx, func() (interface{}, error) {
+		user := r.currentUser(ctx)
 
+		r.stampTenant(ctx, entity)
+		stampVersion(entity)
 		entity.SetCreatedAt(user, time.Now())
 
 		return nil, r.commandRepository.Add(ctx, entity)
@@ -287,12 +522,14 @@ func (r *repositoryService) AddRange(ctx context.Context, entities []Entitier) R
 		defer span.Finish()
 	}
 
-	_, err := r.cb.Execute(func() (interface{}, error) {
-		user, _ := ctx.Value(r.userIdKey).(string)
+	_, err := r.executeCommand(ctx, func() (interface{}, error) {
+		user := r.currentUser(ctx)
 
 		now := time.Now()
 
 		for _, v := range entities {
+			r.stampTenant(ctx, v)
+			stampVersion(v)
 			v.SetCreatedAt(user, now)
 		}
 
@@ -313,9 +550,10 @@ func (r *repositoryService) Update(ctx context.Context, entity Entitier) Result
 		defer span.Finish()
 	}
 
-	_, err := r.cb.Execute(func() (interface{}, error) {
-		user, _ := ctx.Value(r.userIdKey).(string)
+	_, err := r.executeCommand(ctx, func() (interface{}, error) {
+		user := r.currentUser(ctx)
 
+		r.stampTenant(ctx, entity)
 		entity.SetUpdatedAt(user, time.Now())
 
 		return nil, r.commandRepository.Update(ctx, entity)
@@ -335,12 +573,13 @@ func (r *repositoryService) UpdateRange(ctx context.Context, entities []Entitier
 		defer span.Finish()
 	}
 
-	_, err := r.cb.Execute(func() (interface{}, error) {
-		user, _ := ctx.Value(r.userIdKey).(string)
+	_, err := r.executeCommand(ctx, func() (interface{}, error) {
+		user := r.currentUser(ctx)
 
 		now := time.Now()
 
 		for _, v := range entities {
+			r.stampTenant(ctx, v)
 			v.SetUpdatedAt(user, now)
 		}
 
@@ -349,3 +588,310 @@ func (r *repositoryService) UpdateRange(ctx context.Context, entities []Entitier
 
 	return Result{V: nil, E: err}
 }
+
+// currentUser prefers the authenticated principal over the raw user id in ctx,
+// the raw user id is ignored when a verifier is configured
+func (r *repositoryService) currentUser(ctx context.Context) string {
+	if principal, ok := PrincipalFromContext(ctx); ok && principal.UserId != "" {
+		return principal.UserId
+	}
+
+	if HasVerifier(ctx) {
+		return ""
+	}
+
+	if user, ok := UserIdFromContext(ctx); ok {
+		return user
+	}
+
+	user, _ := ctx.Value(r.userIdKey).(string)
+
+	return user
+}
+
+// stampCreated stamps the creation of the entity which is not stamped yet
+func stampCreated(entity Entitier, user string, now time.Time) {
+	if created, ok := entity.(interface{ GetCreatedAt() time.Time }); ok && !created.GetCreatedAt().IsZero() {
+		return
+	}
+
+	entity.SetCreatedAt(user, now)
+}
+
+// stampVersion starts the version of new entity
+func stampVersion(entity Entitier) {
+	if versioned, ok := entity.(Versioned); ok && versioned.GetVersion() == 0 {
+		versioned.SetVersion(1)
+	}
+}
+
+func (r *repositoryService) stampTenant(ctx context.Context, entity Entitier) {
+	if tenantId, ok := TenantScope(ctx, entity); ok {
+		entity.(TenantEntitier).SetTenantId(tenantId)
+	}
+}
+
+// softRemove stamps the deleted entities by one conditional update on the primary,
+// the found entities stay removed when the others are not found, they can be restored
+func (r *repositoryService) softRemove(ctx context.Context, ids []uuid.UUID) Result {
+	unique := make(map[uuid.UUID]bool, len(ids))
+
+	for _, v := range ids {
+		unique[v] = true
+	}
+
+	affected, err := r.softRemoveWhere(ctx, In("ID", ids))
+
+	if err != nil {
+		return Result{E: err}
+	}
+
+	if affected != int64(len(unique)) {
+		return Result{E: NewError(ErrNotFound, CodeNotFound, fmt.Sprintf("%d of %d entities are not found", int64(len(unique))-affected, len(unique)))}
+	}
+
+	return Result{V: nil, E: nil}
+}
+
+func (r *repositoryService) softRemoveWhere(ctx context.Context, spec *Specification) (int64, error) {
+	affected, err := r.executeCommand(ctx, func() (interface{}, error) {
+		user := r.currentUser(ctx)
+
+		now := time.Now()
+
+		return r.commandRepository.UpdateWhere(ctx, spec, map[string]interface{}{
+			DeletedAtField:  &now,
+			DeleteUserField: user,
+			"UpdatedAt":     now,
+			"UpdateUser":    user,
+		})
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return affected.(int64), nil
+}
+
+func (r *repositoryService) Restore(ctx context.Context, id uuid.UUID) Result {
+	if id == uuid.Nil {
+		return Result{E: fmt.Errorf("%w id is required", ErrInternalServerError)}
+	}
+
+	if _, ok := r.model.(SoftDeleter); !ok {
+		return Result{E: fmt.Errorf("%w model is not soft deleter", ErrInternalServerError)}
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Repository:Restore")
+
+	if span != nil {
+		defer span.Finish()
+	}
+
+	ctx = WithDeletedScope(ctx, DeletedOnly)
+
+	entity := r.newModel()
+
+	_, err := r.executeQuery(ctx, func() (interface{}, error) {
+		return nil, r.queryRepository.Find(ctx, id, entity)
+	})
+
+	if err != nil {
+		return Result{E: err}
+	}
+
+	_, err = r.executeCommand(ctx, func() (interface{}, error) {
+		user := r.currentUser(ctx)
+
+		entity.(SoftDeleter).ClearDeletedAt()
+		entity.SetUpdatedAt(user, time.Now())
+
+		return nil, r.commandRepository.Update(WithDeletedScope(ctx, DeletedIncluded), entity)
+	})
+
+	return Result{V: entity, E: err}
+}
+
+func (r *repositoryService) ListDeleted(ctx context.Context, dest interface{}) Result {
+	if _, ok := r.model.(SoftDeleter); !ok {
+		return Result{E: fmt.Errorf("%w model is not soft deleter", ErrInternalServerError)}
+	}
+
+	return r.List(WithDeletedScope(ctx, DeletedOnly), dest)
+}
+
+// Purge removes the entity permanently whether it is deleted softly or not
+func (r *repositoryService) Purge(ctx context.Context, id uuid.UUID) Result {
+	if id == uuid.Nil {
+		return Result{E: fmt.Errorf("%w id is required", ErrInternalServerError)}
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Repository:Purge")
+
+	if span != nil {
+		defer span.Finish()
+	}
+
+	ctx = WithDeletedScope(ctx, DeletedIncluded)
+
+	_, err := r.executeCommand(ctx, func() (interface{}, error) {
+		return nil, r.commandRepository.Remove(ctx, id)
+	})
+
+	return Result{V: nil, E: err}
+}
+
+func (r *repositoryService) newModel() Entitier {
+	return reflect.New(reflect.TypeOf(r.model).Elem()).Interface().(Entitier)
+}
+
+// Upsert adds the entity or replaces it when the id exists, the creation stamp of an existing entity is kept
+func (r *repositoryService) Upsert(ctx context.Context, entity Entitier) Result {
+	if entity == nil {
+		return Result{E: fmt.Errorf("%w entity is required", ErrInternalServerError)}
+	}
+
+	return r.UpsertRange(ctx, []Entitier{entity})
+}
+
+func (r *repositoryService) UpsertRange(ctx context.Context, entities []Entitier) Result {
+	if len(entities) == 0 {
+		return Result{E: fmt.Errorf("%w entities is required", ErrInternalServerError)}
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Repository:UpsertRange")
+
+	if span != nil {
+		defer span.Finish()
+	}
+
+	affected, err := r.executeCommand(ctx, func() (interface{}, error) {
+		user := r.currentUser(ctx)
+
+		now := time.Now()
+
+		for _, v := range entities {
+			if v.GetID() == uuid.Nil {
+				v.SetID(uuid.New())
+			}
+
+			r.stampTenant(ctx, v)
+			stampVersion(v)
+			stampCreated(v, user, now)
+			v.SetUpdatedAt(user, now)
+		}
+
+		return r.commandRepository.UpsertRange(ctx, entities)
+	})
+
+	return Result{V: affected, E: err}
+}
+
+// Patch updates the given fields of the entity, fields are keyed by the model field names
+func (r *repositoryService) Patch(ctx context.Context, id uuid.UUID, fields map[string]interface{}) Result {
+	if id == uuid.Nil {
+		return Result{E: fmt.Errorf("%w id is required", ErrInternalServerError)}
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Repository:Patch")
+
+	if span != nil {
+		defer span.Finish()
+	}
+
+	fields, err := r.patchFields(ctx, fields)
+
+	if err != nil {
+		return Result{E: err}
+	}
+
+	affected, err := r.executeCommand(ctx, func() (interface{}, error) {
+		return r.commandRepository.Patch(ctx, id, fields)
+	})
+
+	return Result{V: affected, E: err}
+}
+
+// UpdateWhere updates the given fields of every entity satisfying spec
+func (r *repositoryService) UpdateWhere(ctx context.Context, spec *Specification, fields map[string]interface{}) Result {
+	if spec == nil {
+		return Result{E: fmt.Errorf("%w spec is required", ErrInternalServerError)}
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Repository:UpdateWhere")
+
+	if span != nil {
+		defer span.Finish()
+	}
+
+	fields, err := r.patchFields(ctx, fields)
+
+	if err != nil {
+		return Result{E: err}
+	}
+
+	affected, err := r.executeCommand(ctx, func() (interface{}, error) {
+		return r.commandRepository.UpdateWhere(ctx, spec, fields)
+	})
+
+	return Result{V: affected, E: err}
+}
+
+// RemoveWhere removes every entity satisfying spec, soft deleters are removed softly
+func (r *repositoryService) RemoveWhere(ctx context.Context, spec *Specification) Result {
+	if spec == nil {
+		return Result{E: fmt.Errorf("%w spec is required", ErrInternalServerError)}
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Repository:RemoveWhere")
+
+	if span != nil {
+		defer span.Finish()
+	}
+
+	if _, ok := r.model.(SoftDeleter); ok {
+		affected, err := r.softRemoveWhere(ctx, spec)
+
+		return Result{V: affected, E: err}
+	}
+
+	affected, err := r.executeCommand(ctx, func() (interface{}, error) {
+		return r.commandRepository.RemoveWhere(ctx, spec)
+	})
+
+	return Result{V: affected, E: err}
+}
+
+// patchFields validates the patched fields and stamps the update
+func (r *repositoryService) patchFields(ctx context.Context, fields map[string]interface{}) (map[string]interface{}, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w fields is required", ErrBadRequest)
+	}
+
+	modelType := reflect.TypeOf(r.model)
+
+	if modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+
+	patched := make(map[string]interface{}, len(fields)+2)
+
+	for k, v := range fields {
+		switch k {
+		case "ID", "CreateUser", "CreatedAt", "TenantId", "Version", DeletedAtField, DeleteUserField:
+			return nil, fmt.Errorf("%w field %s can not be patched", ErrBadRequest, k)
+		}
+
+		if _, ok := modelType.FieldByName(k); !ok {
+			return nil, fmt.Errorf("%w unknown field %s", ErrBadRequest, k)
+		}
+
+		patched[k] = v
+	}
+
+	patched["UpdateUser"] = r.currentUser(ctx)
+	patched["UpdatedAt"] = time.Now()
+
+	return patched, nil
+}
@@ -19,6 +19,8 @@ type repositoryServiceBuilder struct {
 	queryRepository   queryRepositoryAdapter
 	commandRepository commandRepositoryAdapter
 	cbSettings        CircuitBreakerSettings
+	retrySettings     RetrySettings
+	reconnectSettings ReconnectSettings
 	settings          RepositoryServiceSettings
 }
 
@@ -42,6 +44,18 @@ func NewRepositoryServiceBuilder(model Entitier, tableName string) *repositorySe
 		SamplingDuration:         time.Duration(60 * time.Second),
 		SamplingFailureCount:     5,
 	}
+	o.retrySettings = RetrySettings{
+		MaxAttempts:     1,
+		InitialInterval: time.Duration(100 * time.Millisecond),
+		MaxInterval:     time.Duration(10 * time.Second),
+		Multiplier:      2,
+		JitterFactor:    0.5,
+	}
+	o.reconnectSettings = ReconnectSettings{
+		InitialInterval: time.Duration(1 * time.Second),
+		MaxInterval:     time.Duration(60 * time.Second),
+		Multiplier:      2,
+	}
 	o.settings = RepositoryServiceSettings{
 		ConnectionTimeout: time.Duration(10 * time.Second),
 	}
@@ -81,6 +95,7 @@ func (b *repositoryServiceBuilder) Create() *repositoryService {
 	instance := &repositoryService{
 		tableName:         b.tableName,
 		userIdKey:         b.userIdKey,
+		model:             b.model,
 		queryRepository:   b.queryRepository,
 		commandRepository: b.commandRepository,
 		settings:          b.settings,
@@ -88,6 +103,17 @@ func (b *repositoryServiceBuilder) Create() *repositoryService {
 
 	instance.cb = b.cbSettings.ToCircuitBreaker(b.tableName+"-repository", instance.onCircuitOpen)
 
+	instance.retry = b.retrySettings.ToRetrier()
+
+	instance.querySupervisor = newConnectionSupervisor(b.tableName+"-query-repository", b.queryRepository, b.settings.ConnectionTimeout, b.reconnectSettings)
+
+	// query and command adapters may share the same connection
+	if interface{}(b.queryRepository) == interface{}(b.commandRepository) {
+		instance.commandSupervisor = instance.querySupervisor
+	} else {
+		instance.commandSupervisor = newConnectionSupervisor(b.tableName+"-command-repository", b.commandRepository, b.settings.ConnectionTimeout, b.reconnectSettings)
+	}
+
 	instance.initialize()
 
 	return instance
@@ -115,6 +141,28 @@ func (b *repositoryServiceBuilder) CircuitBreaker(settings CircuitBreakerSetting
 	return b
 }
 
+// Builder method to set the field retry in RepositoryServiceBuilder
+func (b *repositoryServiceBuilder) Retry(settings RetrySettings) *repositoryServiceBuilder {
+	err := model.Copy(&b.retrySettings, settings)
+
+	if err != nil {
+		panic(fmt.Errorf("retry settings mapping errors occurred: %v", err))
+	}
+
+	return b
+}
+
+// Builder method to set the field reconnect in RepositoryServiceBuilder
+func (b *repositoryServiceBuilder) Reconnect(settings ReconnectSettings) *repositoryServiceBuilder {
+	err := model.Copy(&b.reconnectSettings, settings)
+
+	if err != nil {
+		panic(fmt.Errorf("reconnect settings mapping errors occurred: %v", err))
+	}
+
+	return b
+}
+
 // Builder method to set the field queryRepository in RepositoryServiceBuilder
 func (b *repositoryServiceBuilder) UserIdKeyInContext(useridKey string) *repositoryServiceBuilder {
 	if strings.TrimSpace(useridKey) == "" {
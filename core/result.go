@@ -3,9 +3,14 @@ package core
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/sony/gobreaker"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type Result struct {
@@ -17,15 +22,19 @@ func (r *Result) ToHttpStatus() int {
 	switch {
 	case errors.Is(r.E, ErrBadRequest):
 		return http.StatusBadRequest
+	case errors.Is(r.E, ErrUnauthorized):
+		return http.StatusUnauthorized
 	case errors.Is(r.E, ErrConflict):
 		return http.StatusConflict
 	case errors.Is(r.E, ErrForbiddenAcccess):
 		return http.StatusForbidden
 	case errors.Is(r.E, ErrNotFound):
 		return http.StatusNotFound
-	case errors.Is(r.E, context.DeadlineExceeded):
+	case errors.Is(r.E, ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(r.E, context.DeadlineExceeded), errors.Is(r.E, ErrTimeout):
 		return http.StatusGatewayTimeout
-	case errors.Is(r.E, gobreaker.ErrOpenState):
+	case errors.Is(r.E, gobreaker.ErrOpenState), errors.Is(r.E, ErrUnavailable):
 		return http.StatusServiceUnavailable
 	case errors.Is(r.E, gobreaker.ErrTooManyRequests):
 		return http.StatusTooManyRequests
@@ -37,3 +46,99 @@ func (r *Result) ToHttpStatus() int {
 		return http.StatusNoContent
 	}
 }
+
+func (r *Result) ToGrpcCode() codes.Code {
+	switch {
+	case r.E == nil:
+		return codes.OK
+	case errors.Is(r.E, ErrBadRequest):
+		return codes.InvalidArgument
+	case errors.Is(r.E, ErrUnauthorized):
+		return codes.Unauthenticated
+	case errors.Is(r.E, ErrConflict):
+		return codes.AlreadyExists
+	case errors.Is(r.E, ErrForbiddenAcccess):
+		return codes.PermissionDenied
+	case errors.Is(r.E, ErrNotFound):
+		return codes.NotFound
+	case errors.Is(r.E, ErrPreconditionFailed):
+		return codes.FailedPrecondition
+	case errors.Is(r.E, context.DeadlineExceeded), errors.Is(r.E, ErrTimeout):
+		return codes.DeadlineExceeded
+	case errors.Is(r.E, context.Canceled):
+		return codes.Canceled
+	case errors.Is(r.E, gobreaker.ErrOpenState), errors.Is(r.E, ErrUnavailable):
+		return codes.Unavailable
+	case errors.Is(r.E, gobreaker.ErrTooManyRequests):
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
+
+func (r *Result) ToGrpcStatus() *status.Status {
+	code := r.ToGrpcCode()
+
+	if code == codes.OK {
+		return status.New(codes.OK, "")
+	}
+
+	message := clientMessage(r.E)
+
+	if code == codes.Internal {
+		message = ErrInternalServerError.Error()
+	}
+
+	s := status.New(code, message)
+
+	details := make([]proto.Message, 0)
+
+	var coreError *Error
+
+	if errors.As(r.E, &coreError) {
+		if coreError.Code != "" {
+			metadata := make(map[string]string)
+
+			for k, v := range coreError.Details {
+				metadata[k] = fmt.Sprint(v)
+			}
+
+			details = append(details, &errdetails.ErrorInfo{
+				Reason:   coreError.Code,
+				Metadata: metadata,
+			})
+		}
+
+		if coreError.Retryable {
+			details = append(details, &errdetails.RetryInfo{})
+		}
+	}
+
+	var validationError *ValidationError
+
+	if errors.As(r.E, &validationError) {
+		violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(validationError.Fields))
+
+		for _, v := range validationError.Fields {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       v.Namespace,
+				Description: v.Message,
+			})
+		}
+
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: violations,
+		})
+	}
+
+	if len(details) == 0 {
+		return s
+	}
+
+	withDetails, err := s.WithDetails(details...)
+	if err != nil {
+		return s
+	}
+
+	return withDetails
+}
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/sony/gobreaker"
+)
+
+type retrier struct {
+	settings RetrySettings
+}
+
+func (s *RetrySettings) ToRetrier() *retrier {
+	if s.MaxAttempts < 1 {
+		s.MaxAttempts = 1
+	}
+
+	if s.Multiplier < 1 {
+		s.Multiplier = 1
+	}
+
+	return &retrier{
+		settings: *s,
+	}
+}
+
+func (r *retrier) execute(ctx context.Context, cb *gobreaker.CircuitBreaker, req func() (interface{}, error)) (interface{}, error) {
+	interval := r.settings.InitialInterval
+
+	for attempt := 1; ; attempt++ {
+		resp, err := cb.Execute(req)
+
+		if err == nil || attempt >= r.settings.MaxAttempts || !r.isRetryable(err) {
+			return resp, err
+		}
+
+		wait := r.jitter(interval)
+
+		// do not start an attempt that can not finish before the deadline
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return resp, err
+		}
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return resp, err
+		case <-timer.C:
+		}
+
+		interval = time.Duration(float64(interval) * r.settings.Multiplier)
+
+		if r.settings.MaxInterval > 0 && interval > r.settings.MaxInterval {
+			interval = r.settings.MaxInterval
+		}
+	}
+}
+
+func (r *retrier) isRetryable(err error) bool {
+	// the circuit breaker has the final say, retrying an open circuit is pointless
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+		return false
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if r.settings.IsRetryable != nil {
+		return r.settings.IsRetryable(err)
+	}
+
+	return IsTransientError(err)
+}
+
+func (r *retrier) jitter(interval time.Duration) time.Duration {
+	if r.settings.JitterFactor <= 0 {
+		return interval
+	}
+
+	delta := r.settings.JitterFactor * float64(interval)
+	min := float64(interval) - delta
+	max := float64(interval) + delta
+
+	return time.Duration(min + rand.Float64()*(max-min))
+}
+
+// IsTransientError is the default retryable-error classifier,
+// only the retryable errors, the unavailable or timed out services and the network errors may succeed by retrying
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var coreError *Error
+
+	if errors.As(err, &coreError) && coreError.Retryable {
+		return true
+	}
+
+	var netError net.Error
+
+	return errors.Is(err, ErrUnavailable) ||
+		errors.Is(err, ErrTimeout) ||
+		errors.As(err, &netError) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+func isBusinessError(err error) bool {
+	return errors.Is(err, ErrNotFound) ||
+		errors.Is(err, ErrConflict) ||
+		errors.Is(err, ErrBadRequest) ||
+		errors.Is(err, ErrForbiddenAcccess) ||
+		errors.Is(err, ErrUnauthorized) ||
+		errors.Is(err, ErrPreconditionFailed)
+}
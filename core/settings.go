@@ -1,6 +1,10 @@
 package core
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
 	"strings"
 	"time"
 
@@ -37,6 +41,72 @@ type CircuitBreakerSettings struct {
 	OnStateChange            func(name string, from string, to string)
 }
 
+type RetrySettings struct {
+	MaxAttempts     int           `model:",omitempty"`
+	InitialInterval time.Duration `model:",omitempty"`
+	MaxInterval     time.Duration `model:",omitempty"`
+	Multiplier      float64       `model:",omitempty"`
+	JitterFactor    float64       `model:",omitempty"`
+	IsRetryable     func(err error) bool
+}
+
+type ReconnectSettings struct {
+	InitialInterval time.Duration `model:",omitempty"`
+	MaxInterval     time.Duration `model:",omitempty"`
+	Multiplier      float64       `model:",omitempty"`
+	OnStateChange   func(name string, from string, to string)
+}
+
+// TLSSettings loads the certificates from PEM files, CertFile and KeyFile authenticate the client for the mutual TLS.
+// CAFile verifies the server instead of the system roots
+type TLSSettings struct {
+	Enabled            bool
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+// ToTLSConfig returns nil when the TLS is not enabled
+func (s *TLSSettings) ToTLSConfig() (*tls.Config, error) {
+	if !s.Enabled {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		ServerName:         s.ServerName,
+		InsecureSkipVerify: s.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if s.CAFile != "" {
+		pem, err := ioutil.ReadFile(s.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s has no certificate", s.CAFile)
+		}
+
+		config.RootCAs = pool
+	}
+
+	if s.CertFile != "" || s.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(s.CertFile, s.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
 func (s *CircuitBreakerSettings) ToCircuitBreaker(defaultName string, onCircuitOpen func()) *gobreaker.CircuitBreaker {
 	if strings.TrimSpace(s.Name) == "" {
 		s.Name = defaultName
@@ -65,7 +135,9 @@ func (s *CircuitBreakerSettings) ToCircuitBreaker(defaultName string, onCircuitO
 				s.OnStateChange(name, from.String(), to.String())
 			}
 
-			onCircuitOpen()
+			if to == gobreaker.StateOpen {
+				onCircuitOpen()
+			}
 		},
 	}
 
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"context"
+	"time"
+)
+
+// SoftDeleteEntity is embedded with Entity to remove the model softly
+type SoftDeleteEntity struct {
+	DeletedAt  *time.Time `gorm:"index" bson:"deletedAt"`
+	DeleteUser string     `bson:"deleteUser"`
+}
+
+// the field names of SoftDeleteEntity, the soft remove updates them and Patch never does
+const (
+	DeletedAtField  = "DeletedAt"
+	DeleteUserField = "DeleteUser"
+)
+
+type SoftDeleter interface {
+	IsDeleted() bool
+	SetDeletedAt(user string, timestamp time.Time)
+	ClearDeletedAt()
+}
+
+type DeletedScope int
+
+const (
+	// DeletedExcluded is the default scope, deleted entities are filtered out
+	DeletedExcluded DeletedScope = iota
+	DeletedIncluded
+	DeletedOnly
+)
+
+type deletedScopeKey struct{}
+
+func (e *SoftDeleteEntity) IsDeleted() bool {
+	return e.DeletedAt != nil
+}
+
+func (e *SoftDeleteEntity) SetDeletedAt(user string, timestamp time.Time) {
+	e.DeleteUser = user
+	e.DeletedAt = &timestamp
+}
+
+func (e *SoftDeleteEntity) ClearDeletedAt() {
+	e.DeleteUser = ""
+	e.DeletedAt = nil
+}
+
+func WithDeletedScope(ctx context.Context, scope DeletedScope) context.Context {
+	return context.WithValue(ctx, deletedScopeKey{}, scope)
+}
+
+// DeletedScopeOf returns the deleted scope of ctx when the model is removed softly,
+// adapters use it to filter their queries
+func DeletedScopeOf(ctx context.Context, model interface{}) (DeletedScope, bool) {
+	if _, ok := model.(SoftDeleter); !ok {
+		return DeletedIncluded, false
+	}
+
+	scope, _ := ctx.Value(deletedScopeKey{}).(DeletedScope)
+
+	return scope, true
+}
+
+// InDeletedScope evaluates the scope of ctx against the entity in memory
+func InDeletedScope(ctx context.Context, entity interface{}) bool {
+	scope, ok := DeletedScopeOf(ctx, entity)
+	if !ok {
+		return true
+	}
+
+	switch scope {
+	case DeletedOnly:
+		return entity.(SoftDeleter).IsDeleted()
+	case DeletedIncluded:
+		return true
+	default:
+		return !entity.(SoftDeleter).IsDeleted()
+	}
+}
@@ -0,0 +1,362 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Operator string
+
+const (
+	OpEq   Operator = "eq"
+	OpNe   Operator = "ne"
+	OpIn   Operator = "in"
+	OpGt   Operator = "gt"
+	OpLt   Operator = "lt"
+	OpLike Operator = "like"
+	OpAnd  Operator = "and"
+	OpOr   Operator = "or"
+	OpNot  Operator = "not"
+)
+
+// Specification is the backend neutral filter on the Go field names of the model,
+// it is passed as the query of the *WithFilter methods and translated by each adapter
+type Specification struct {
+	Operator Operator
+	Field    string
+	Value    interface{}
+	Specs    []*Specification
+
+	// like is compiled once by the first evaluation of the Like specification
+	like     *regexp.Regexp
+	likeOnce sync.Once
+}
+
+func Eq(field string, value interface{}) *Specification {
+	return &Specification{Operator: OpEq, Field: field, Value: value}
+}
+
+func Ne(field string, value interface{}) *Specification {
+	return &Specification{Operator: OpNe, Field: field, Value: value}
+}
+
+// In matches any of values, values should be a slice
+func In(field string, values interface{}) *Specification {
+	return &Specification{Operator: OpIn, Field: field, Value: values}
+}
+
+func Gt(field string, value interface{}) *Specification {
+	return &Specification{Operator: OpGt, Field: field, Value: value}
+}
+
+func Lt(field string, value interface{}) *Specification {
+	return &Specification{Operator: OpLt, Field: field, Value: value}
+}
+
+// Like matches the sql like pattern, % is any characters and _ is a single character
+func Like(field string, pattern string) *Specification {
+	return &Specification{Operator: OpLike, Field: field, Value: pattern}
+}
+
+func And(specs ...*Specification) *Specification {
+	return &Specification{Operator: OpAnd, Specs: specs}
+}
+
+func Or(specs ...*Specification) *Specification {
+	return &Specification{Operator: OpOr, Specs: specs}
+}
+
+func Not(spec *Specification) *Specification {
+	return &Specification{Operator: OpNot, Specs: []*Specification{spec}}
+}
+
+// IsSatisfiedBy evaluates the specification against the entity in memory
+func (s *Specification) IsSatisfiedBy(entity interface{}) bool {
+	switch s.Operator {
+	case OpAnd:
+		for _, v := range s.Specs {
+			if !v.IsSatisfiedBy(entity) {
+				return false
+			}
+		}
+
+		return true
+	case OpOr:
+		for _, v := range s.Specs {
+			if v.IsSatisfiedBy(entity) {
+				return true
+			}
+		}
+
+		return len(s.Specs) == 0
+	case OpNot:
+		return len(s.Specs) == 1 && !s.Specs[0].IsSatisfiedBy(entity)
+	}
+
+	field, ok := FieldValue(entity, s.Field)
+	if !ok {
+		return false
+	}
+
+	switch s.Operator {
+	case OpEq:
+		return equals(field, s.Value)
+	case OpNe:
+		return !equals(field, s.Value)
+	case OpIn:
+		values := reflect.ValueOf(s.Value)
+
+		if values.Kind() != reflect.Slice && values.Kind() != reflect.Array {
+			return false
+		}
+
+		for i := 0; i < values.Len(); i++ {
+			if equals(field, values.Index(i).Interface()) {
+				return true
+			}
+		}
+
+		return false
+	case OpGt:
+		result, ok := compare(field, s.Value)
+
+		return ok && result > 0
+	case OpLt:
+		result, ok := compare(field, s.Value)
+
+		return ok && result < 0
+	case OpLike:
+		text, ok := field.(string)
+
+		return ok && s.likeRegexp().MatchString(text)
+	default:
+		return false
+	}
+}
+
+func (s *Specification) likeRegexp() *regexp.Regexp {
+	s.likeOnce.Do(func() {
+		pattern, _ := s.Value.(string)
+
+		s.like = regexp.MustCompile(LikeToRegexp(pattern))
+	})
+
+	return s.like
+}
+
+// SpecificationOf evaluates the specification and the equality of the field map e.g. map[string]interface{}{"Expect": 1}
+// for the adapters in memory, the raw queries of the other adapters are rejected instead of matching everything
+func SpecificationOf(query interface{}) (*Specification, error) {
+	switch q := query.(type) {
+	case nil:
+		return nil, nil
+	case *Specification:
+		return q, nil
+	case map[string]interface{}:
+		fields := make([]string, 0, len(q))
+
+		for k := range q {
+			fields = append(fields, k)
+		}
+
+		sort.Strings(fields)
+
+		specs := make([]*Specification, 0, len(fields))
+
+		for _, v := range fields {
+			specs = append(specs, Eq(v, q[v]))
+		}
+
+		return And(specs...), nil
+	default:
+		return nil, fmt.Errorf("%w query %T is not supported in memory", ErrBadRequest, query)
+	}
+}
+
+// FieldValue returns the value of the field by its Go name, embedded fields are promoted
+// and dotted paths like Entity.ID are allowed
+func FieldValue(entity interface{}, field string) (interface{}, bool) {
+	value := reflect.ValueOf(entity)
+
+	for _, name := range strings.Split(field, ".") {
+		for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return nil, false
+			}
+
+			value = value.Elem()
+		}
+
+		if value.Kind() != reflect.Struct {
+			return nil, false
+		}
+
+		value = value.FieldByName(name)
+
+		if !value.IsValid() {
+			return nil, false
+		}
+	}
+
+	return value.Interface(), true
+}
+
+// LikeToRegexp converts the sql like pattern to the anchored regular expression
+func LikeToRegexp(pattern string) string {
+	var builder strings.Builder
+
+	builder.WriteString("^")
+
+	for _, v := range pattern {
+		switch v {
+		case '%':
+			builder.WriteString(".*")
+		case '_':
+			builder.WriteString(".")
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(v)))
+		}
+	}
+
+	builder.WriteString("$")
+
+	return builder.String()
+}
+
+func indirect(value interface{}) (interface{}, bool) {
+	v := reflect.ValueOf(value)
+
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, true
+		}
+
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return nil, true
+	}
+
+	return v.Interface(), false
+}
+
+func equals(a interface{}, b interface{}) bool {
+	a, aNil := indirect(a)
+	b, bNil := indirect(b)
+
+	if aNil || bNil {
+		return aNil == bNil
+	}
+
+	if result, ok := compare(a, b); ok {
+		return result == 0
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// compare orders numbers, strings, times and byte arrays like uuid
+func compare(a interface{}, b interface{}) (int, bool) {
+	a, aNil := indirect(a)
+	b, bNil := indirect(b)
+
+	if aNil || bNil {
+		return 0, false
+	}
+
+	if x, ok := toInt(a); ok {
+		if y, ok := toInt(b); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			default:
+				return 0, true
+			}
+		}
+	}
+
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			default:
+				return 0, true
+			}
+		}
+
+		return 0, false
+	}
+
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			switch {
+			case x.Before(y):
+				return -1, true
+			case x.After(y):
+				return 1, true
+			default:
+				return 0, true
+			}
+		}
+	}
+
+	x := reflect.ValueOf(a)
+	y := reflect.ValueOf(b)
+
+	if x.Kind() == reflect.Array && x.Type() == y.Type() && x.Type().Elem().Kind() == reflect.Uint8 {
+		xs := make([]byte, x.Len())
+		ys := make([]byte, y.Len())
+
+		reflect.Copy(reflect.ValueOf(xs), x)
+		reflect.Copy(reflect.ValueOf(ys), y)
+
+		return bytes.Compare(xs, ys), true
+	}
+
+	return 0, false
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func toInt(value interface{}) (int64, bool) {
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return int64(v.Uint()), true
+	default:
+		return 0, false
+	}
+}
@@ -1,6 +1,9 @@
 package core
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type KV struct {
 	K string
@@ -16,4 +19,10 @@ type stateAdapter interface {
 	Set(ctx context.Context, key string, value interface{}) error
 	BatchSet(ctx context.Context, kvs []KV) error
 	Delete(ctx context.Context, key string) error
+	// SetWithTTL expires the key after ttl, zero ttl never expires
+	SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	// SetIfAbsent sets the key atomically across the processes, ok is false when the key exists
+	SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration) (ok bool, err error)
+	// Increment adds delta to the counter atomically across the processes, the new counter expires after ttl
+	Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
 }
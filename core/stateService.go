@@ -4,37 +4,44 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/sony/gobreaker"
 )
 
 type stateService struct {
-	state    stateAdapter
-	cb       *gobreaker.CircuitBreaker
-	settings StateServiceSettings
+	state      stateAdapter
+	cb         *gobreaker.CircuitBreaker
+	retry      *retrier
+	supervisor *connectionSupervisor
+	settings   StateServiceSettings
 }
 
 func (s *stateService) initialize() *stateService {
-	if err := s.connect(); err != nil {
+	if err := s.supervisor.connect(); err != nil {
 		panic(err)
 	}
 
 	return s
 }
 
-func (s *stateService) connect() error {
-	ctx, cancel := context.WithTimeout(context.Background(), s.settings.ConnectionTimeout)
-	defer cancel()
-
-	return s.state.Connect(ctx)
+func (s *stateService) onCircuitOpen() {
+	s.supervisor.reconnect()
 }
 
-func (s *stateService) onCircuitOpen() {
-	s.state.Disconnect()
+// Close stops reconnecting and disconnects the state adapter, the calls fail after it is closed
+func (s *stateService) Close() {
+	s.supervisor.close()
+}
 
-	if !s.state.IsConnected() {
-		s.connect()
+func (s *stateService) execute(ctx context.Context, req func() (interface{}, error)) (interface{}, error) {
+	if !s.supervisor.acquire() {
+		return nil, gobreaker.ErrOpenState
 	}
+
+	defer s.supervisor.release()
+
+	return s.retry.execute(ctx, s.cb, req)
 }
 
 func (s *stateService) Has(ctx context.Context, key string) Result {
@@ -42,8 +49,8 @@ func (s *stateService) Has(ctx context.Context, key string) Result {
 		return Result{V: false, E: fmt.Errorf("%w key is required", ErrInternalServerError)}
 	}
 
-	resp, err := s.cb.Execute(func() (interface{}, error) {
-		ok := s.state.Has(ctx, key)
+	resp, err := s.execute(ctx, func() (interface{}, error) {
+		ok := s.state.Has(ctx, s.tenantKey(ctx, key))
 
 		return ok, nil
 	})
@@ -74,8 +81,8 @@ func (s *stateService) Get(ctx context.Context, key string, dest interface{}) Re
 		panic("dest must be a pointer")
 	}
 
-	_, err := s.cb.Execute(func() (interface{}, error) {
-		return nil, s.state.Get(ctx, key, dest)
+	_, err := s.execute(ctx, func() (interface{}, error) {
+		return nil, s.state.Get(ctx, s.tenantKey(ctx, key), dest)
 	})
 
 	return Result{V: dest, E: err}
@@ -90,20 +97,80 @@ func (s *stateService) Set(ctx context.Context, key string, value interface{}) R
 		return Result{V: nil, E: fmt.Errorf("%w value is required", ErrInternalServerError)}
 	}
 
-	_, err := s.cb.Execute(func() (interface{}, error) {
-		return nil, s.state.Set(ctx, key, value)
+	_, err := s.execute(ctx, func() (interface{}, error) {
+		return nil, s.state.Set(ctx, s.tenantKey(ctx, key), value)
+	})
+
+	return Result{V: nil, E: err}
+}
+
+func (s *stateService) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) Result {
+	if key == "" {
+		return Result{V: nil, E: fmt.Errorf("%w key is required", ErrInternalServerError)}
+	}
+
+	if value == nil {
+		return Result{V: nil, E: fmt.Errorf("%w value is required", ErrInternalServerError)}
+	}
+
+	_, err := s.execute(ctx, func() (interface{}, error) {
+		return nil, s.state.SetWithTTL(ctx, s.tenantKey(ctx, key), value, ttl)
 	})
 
 	return Result{V: nil, E: err}
 }
 
+// SetIfAbsent results false when the key exists, it is atomic across the processes sharing the state store
+func (s *stateService) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration) Result {
+	if key == "" {
+		return Result{V: false, E: fmt.Errorf("%w key is required", ErrInternalServerError)}
+	}
+
+	if value == nil {
+		return Result{V: false, E: fmt.Errorf("%w value is required", ErrInternalServerError)}
+	}
+
+	resp, err := s.execute(ctx, func() (interface{}, error) {
+		return s.state.SetIfAbsent(ctx, s.tenantKey(ctx, key), value, ttl)
+	})
+
+	if err != nil {
+		return Result{V: false, E: err}
+	}
+
+	return Result{V: resp.(bool), E: nil}
+}
+
+// Increment results the counter after adding delta, it is atomic across the processes sharing the state store
+func (s *stateService) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) Result {
+	if key == "" {
+		return Result{V: int64(0), E: fmt.Errorf("%w key is required", ErrInternalServerError)}
+	}
+
+	resp, err := s.execute(ctx, func() (interface{}, error) {
+		return s.state.Increment(ctx, s.tenantKey(ctx, key), delta, ttl)
+	})
+
+	if err != nil {
+		return Result{V: int64(0), E: err}
+	}
+
+	return Result{V: resp.(int64), E: nil}
+}
+
 func (s *stateService) BatchSet(ctx context.Context, kvs []KV) Result {
 	if len(kvs) == 0 {
 		return Result{V: nil, E: fmt.Errorf("%w kvs is required", ErrInternalServerError)}
 	}
 
-	_, err := s.cb.Execute(func() (interface{}, error) {
-		return nil, s.state.BatchSet(ctx, kvs)
+	_, err := s.execute(ctx, func() (interface{}, error) {
+		tenantKvs := make([]KV, len(kvs))
+
+		for i, v := range kvs {
+			tenantKvs[i] = KV{K: s.tenantKey(ctx, v.K), V: v.V}
+		}
+
+		return nil, s.state.BatchSet(ctx, tenantKvs)
 	})
 
 	return Result{V: nil, E: err}
@@ -114,9 +181,18 @@ func (s *stateService) Delete(ctx context.Context, key string) Result {
 		return Result{V: nil, E: fmt.Errorf("%w key is required", ErrInternalServerError)}
 	}
 
-	_, err := s.cb.Execute(func() (interface{}, error) {
-		return nil, s.state.Delete(ctx, key)
+	_, err := s.execute(ctx, func() (interface{}, error) {
+		return nil, s.state.Delete(ctx, s.tenantKey(ctx, key))
 	})
 
 	return Result{V: nil, E: err}
 }
+
+// tenantKey prefixes the key by the tenant of ctx
+func (s *stateService) tenantKey(ctx context.Context, key string) string {
+	if tenantId, ok := TenantFromContext(ctx); ok {
+		return fmt.Sprintf("tenant:%s:%s", tenantId, key)
+	}
+
+	return key
+}
@@ -9,9 +9,11 @@ import (
 
 // Builder Object for StateService
 type stateServiceBuilder struct {
-	state      stateAdapter
-	cbSettings CircuitBreakerSettings
-	settings   StateServiceSettings
+	state             stateAdapter
+	cbSettings        CircuitBreakerSettings
+	retrySettings     RetrySettings
+	reconnectSettings ReconnectSettings
+	settings          StateServiceSettings
 }
 
 // Constructor for StateServiceBuilder
@@ -23,6 +25,18 @@ func NewStateServiceBuilder() *stateServiceBuilder {
 		SamplingDuration:         time.Duration(60 * time.Second),
 		SamplingFailureCount:     5,
 	}
+	o.retrySettings = RetrySettings{
+		MaxAttempts:     1,
+		InitialInterval: time.Duration(100 * time.Millisecond),
+		MaxInterval:     time.Duration(10 * time.Second),
+		Multiplier:      2,
+		JitterFactor:    0.5,
+	}
+	o.reconnectSettings = ReconnectSettings{
+		InitialInterval: time.Duration(1 * time.Second),
+		MaxInterval:     time.Duration(60 * time.Second),
+		Multiplier:      2,
+	}
 	o.settings = StateServiceSettings{
 		ConnectionTimeout: time.Duration(10 * time.Second),
 	}
@@ -54,6 +68,10 @@ func (b *stateServiceBuilder) Create() *stateService {
 
 	instance.cb = b.cbSettings.ToCircuitBreaker("state service", instance.onCircuitOpen)
 
+	instance.retry = b.retrySettings.ToRetrier()
+
+	instance.supervisor = newConnectionSupervisor("state service", b.state, b.settings.ConnectionTimeout, b.reconnectSettings)
+
 	instance.initialize()
 
 	return instance
@@ -81,6 +99,28 @@ func (b *stateServiceBuilder) CircuitBreaker(settings CircuitBreakerSettings) *s
 	return b
 }
 
+// Builder method to set the field retry in StateServiceBuilder
+func (b *stateServiceBuilder) Retry(settings RetrySettings) *stateServiceBuilder {
+	err := model.Copy(&b.retrySettings, settings)
+
+	if err != nil {
+		panic(fmt.Errorf("retry settings mapping errors occurred: %v", err))
+	}
+
+	return b
+}
+
+// Builder method to set the field reconnect in StateServiceBuilder
+func (b *stateServiceBuilder) Reconnect(settings ReconnectSettings) *stateServiceBuilder {
+	err := model.Copy(&b.reconnectSettings, settings)
+
+	if err != nil {
+		panic(fmt.Errorf("reconnect settings mapping errors occurred: %v", err))
+	}
+
+	return b
+}
+
 // Builder method to set the field state in StateServiceBuilder
 func (b *stateServiceBuilder) UseCache(settings CacheSettings) *stateServiceBuilder {
 	if b.state == nil {
@@ -0,0 +1,52 @@
+package core
+
+import "context"
+
+// TenantEntity is embedded with Entity to scope the model by tenant
+type TenantEntity struct {
+	TenantId string `gorm:"index" bson:"tenantId"`
+}
+
+type TenantEntitier interface {
+	GetTenantId() string
+	SetTenantId(tenantId string)
+}
+
+type tenantKey struct{}
+
+func (e *TenantEntity) GetTenantId() string {
+	return e.TenantId
+}
+
+func (e *TenantEntity) SetTenantId(tenantId string) {
+	e.TenantId = tenantId
+}
+
+func WithTenant(ctx context.Context, tenantId string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantId)
+}
+
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantId, ok := ctx.Value(tenantKey{}).(string)
+
+	return tenantId, ok && tenantId != ""
+}
+
+// TenantScope returns the tenant of ctx when the model is scoped by tenant,
+// adapters use it to filter their queries
+func TenantScope(ctx context.Context, model interface{}) (string, bool) {
+	if _, ok := model.(TenantEntitier); !ok {
+		return "", false
+	}
+
+	return TenantFromContext(ctx)
+}
+
+// InScope evaluates the tenant and the deleted scope of ctx against the entity in memory
+func InScope(ctx context.Context, entity interface{}) bool {
+	if tenantId, ok := TenantScope(ctx, entity); ok && entity.(TenantEntitier).GetTenantId() != tenantId {
+		return false
+	}
+
+	return InDeletedScope(ctx, entity)
+}
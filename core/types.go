@@ -13,3 +13,7 @@ type NotificationHandler func(
 	notification interface{}) error
 
 type ReplyHandler func(receivedData interface{})
+
+type DomainEventListener func(
+	ctx context.Context,
+	event DomainEventer)
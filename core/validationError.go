@@ -0,0 +1,43 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+type FieldError struct {
+	Field     string `json:"field"`
+	Namespace string `json:"namespace"`
+	Tag       string `json:"tag"`
+	Param     string `json:"param,omitempty"`
+	Message   string `json:"message"`
+}
+
+// ValidationError is an ErrBadRequest which reports every invalid field
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func NewValidationError(fields ...FieldError) *ValidationError {
+	return &ValidationError{
+		Fields: fields,
+	}
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+
+	for _, v := range e.Fields {
+		if v.Message != "" {
+			messages = append(messages, v.Message)
+		} else {
+			messages = append(messages, fmt.Sprintf("%s failed on the '%s' tag", v.Namespace, v.Tag))
+		}
+	}
+
+	return fmt.Sprintf("%v: %s", ErrBadRequest, strings.Join(messages, ", "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrBadRequest
+}
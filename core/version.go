@@ -0,0 +1,20 @@
+package core
+
+// VersionEntity is embedded with Entity to update the model optimistically,
+// the version is incremented by every update
+type VersionEntity struct {
+	Version int64 `bson:"version"`
+}
+
+type Versioned interface {
+	GetVersion() int64
+	SetVersion(version int64)
+}
+
+func (e *VersionEntity) GetVersion() int64 {
+	return e.Version
+}
+
+func (e *VersionEntity) SetVersion(version int64) {
+	e.Version = version
+}
@@ -4,10 +4,18 @@ go 1.16
 
 require (
 	github.com/dapr/go-sdk v1.2.0
+	github.com/denisenkom/go-mssqldb v0.9.0
 	github.com/enriquebris/goconcurrentqueue v0.6.0
+	github.com/go-playground/locales v0.14.0
+	github.com/go-playground/universal-translator v0.18.0
 	github.com/go-playground/validator/v10 v10.9.0
 	github.com/go-redis/redis/v8 v8.11.3
+	github.com/go-sql-driver/mysql v1.6.0
+	github.com/golang/protobuf v1.5.2
 	github.com/google/uuid v1.3.0
+	github.com/jackc/pgconn v1.8.1
+	github.com/jackc/pgx/v4 v4.11.0
+	github.com/mattn/go-sqlite3 v1.14.5
 	github.com/nats-io/nats.go v1.11.0
 	github.com/opentracing/opentracing-go v1.2.0
 	github.com/openzipkin-contrib/zipkin-go-opentracing v0.4.5
@@ -15,11 +23,15 @@ require (
 	github.com/orcaman/concurrent-map v0.0.0-20210501183033-44dafcb38ecc
 	github.com/patrickmn/go-cache v2.1.0+incompatible
 	github.com/reactivex/rxgo/v2 v2.5.0
+	github.com/sijms/go-ora/v2 v2.2.22
 	github.com/sony/gobreaker v0.4.1
 	github.com/syndtr/goleveldb v1.0.0
 	go.etcd.io/etcd/client/v3 v3.5.0
 	go.mongodb.org/mongo-driver v1.7.1
 	go.uber.org/zap v1.19.0
+	google.golang.org/genproto v0.0.0-20210602131652-f16073e35f0c
+	google.golang.org/grpc v1.38.0
+	google.golang.org/protobuf v1.26.0
 	gopkg.in/jeevatkm/go-model.v1 v1.1.0
 	gorm.io/driver/mysql v1.1.2
 	gorm.io/driver/postgres v1.1.0
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strconv"
+	"time"
 
 	dapr "github.com/dapr/go-sdk/client"
 
@@ -90,6 +92,35 @@ func (a *adapter) Set(ctx context.Context, key string, value interface{}) error
 	return nil
 }
 
+// SetWithTTL requires the state store which supports the ttlInSeconds metadata
+func (a *adapter) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if ttl <= 0 {
+		return a.Set(ctx, key, value)
+	}
+
+	bytes, err := json.Marshal(value)
+
+	if err != nil {
+		return err
+	}
+
+	seconds := int64((ttl + time.Second - 1) / time.Second)
+
+	return a.client.SaveBulkState(ctx, a.settings.StoreName, &dapr.SetStateItem{
+		Key:      key,
+		Value:    bytes,
+		Metadata: map[string]string{"ttlInSeconds": strconv.FormatInt(seconds, 10)},
+	})
+}
+
+func (a *adapter) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return false, errors.New("not supported operation")
+}
+
+func (a *adapter) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	return 0, errors.New("not supported operation")
+}
+
 func (a *adapter) BatchSet(ctx context.Context, kvs []core.KV) error {
 	for _, v := range kvs {
 		err := a.Set(ctx, v.K, v.V)
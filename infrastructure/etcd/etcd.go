@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -136,17 +137,132 @@ func (a *adapter) Get(ctx context.Context, key string, dest interface{}) error {
 }
 
 func (a *adapter) Set(ctx context.Context, key string, value interface{}) error {
+	return a.SetWithTTL(ctx, key, value, 0)
+}
+
+func (a *adapter) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
 	bytes, err := json.Marshal(value)
 
 	if err != nil {
 		return err
 	}
 
-	_, err = a.client.etcd.Put(ctx, key, string(bytes))
+	opts, err := a.lease(ctx, ttl)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = a.client.etcd.Put(ctx, key, string(bytes), opts...)
 
 	return err
 }
 
+// SetIfAbsent puts the key in the transaction which requires the key is never created
+func (a *adapter) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	bytes, err := json.Marshal(value)
+
+	if err != nil {
+		return false, err
+	}
+
+	opts, err := a.lease(ctx, ttl)
+
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := a.client.etcd.Txn(ctx).
+		If(etcd.Compare(etcd.CreateRevision(key), "=", 0)).
+		Then(etcd.OpPut(key, string(bytes), opts...)).
+		Commit()
+
+	if err != nil {
+		return false, err
+	}
+
+	return resp.Succeeded, nil
+}
+
+// Increment compares and swaps the counter until no concurrent increment interleaves,
+// the lease of ttl is granted only when the counter is created
+func (a *adapter) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		value, err := a.client.etcd.Get(ctx, key)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if value.Count < 1 {
+			opts, err := a.lease(ctx, ttl)
+
+			if err != nil {
+				return 0, err
+			}
+
+			resp, err := a.client.etcd.Txn(ctx).
+				If(etcd.Compare(etcd.CreateRevision(key), "=", 0)).
+				Then(etcd.OpPut(key, strconv.FormatInt(delta, 10), opts...)).
+				Commit()
+
+			if err != nil {
+				return 0, err
+			}
+
+			if resp.Succeeded {
+				return delta, nil
+			}
+
+			continue
+		}
+
+		kv := value.Kvs[0]
+
+		count, err := strconv.ParseInt(string(kv.Value), 10, 64)
+
+		if err != nil {
+			return 0, err
+		}
+
+		count += delta
+
+		resp, err := a.client.etcd.Txn(ctx).
+			If(etcd.Compare(etcd.ModRevision(key), "=", kv.ModRevision)).
+			Then(etcd.OpPut(key, strconv.FormatInt(count, 10), etcd.WithIgnoreLease())).
+			Commit()
+
+		if err != nil {
+			return 0, err
+		}
+
+		if resp.Succeeded {
+			return count, nil
+		}
+	}
+}
+
+// lease expires the key by the lease of ttl rounded up to seconds
+func (a *adapter) lease(ctx context.Context, ttl time.Duration) ([]etcd.OpOption, error) {
+	if ttl <= 0 {
+		return nil, nil
+	}
+
+	seconds := int64((ttl + time.Second - 1) / time.Second)
+
+	lease, err := a.client.etcd.Grant(ctx, seconds)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []etcd.OpOption{etcd.WithLease(lease.ID)}, nil
+}
+
 func (a *adapter) BatchSet(ctx context.Context, kvs []core.KV) error {
 	for _, v := range kvs {
 		err := a.Set(ctx, v.K, v.V)
@@ -0,0 +1,118 @@
+package gorms
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"gorm.io/gorm/clause"
+)
+
+// Aggregate translates the aggregation to GROUP BY, the aliases are generated to stay portable
+func (a *adapter) Aggregate(ctx context.Context, query interface{}, aggregation core.Aggregation) ([]core.AggregateRow, error) {
+	db, err := a.reader(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := query.(*core.Specification); ok {
+		db, err = a.where(db, query, nil)
+		if err != nil {
+			return nil, err
+		}
+	} else if query != nil {
+		db = db.Where(query)
+	}
+
+	selects := make([]string, 0, len(aggregation.GroupBy)+len(aggregation.Aggregates))
+	vars := make([]interface{}, 0, len(selects))
+	groupBy := clause.GroupBy{}
+	orderBy := clause.OrderBy{}
+
+	for i, v := range aggregation.GroupBy {
+		name, err := a.column(db, v)
+		if err != nil {
+			return nil, err
+		}
+
+		column := clause.Column{Name: name}
+
+		selects = append(selects, fmt.Sprintf("? AS k%d", i))
+		vars = append(vars, column)
+		groupBy.Columns = append(groupBy.Columns, column)
+		orderBy.Columns = append(orderBy.Columns, clause.OrderByColumn{Column: column})
+	}
+
+	for i, v := range aggregation.Aggregates {
+		if v.Function == core.AggregateCount {
+			selects = append(selects, fmt.Sprintf("COUNT(*) AS a%d", i))
+
+			continue
+		}
+
+		name, err := a.column(db, v.Field)
+		if err != nil {
+			return nil, err
+		}
+
+		selects = append(selects, fmt.Sprintf("%s(?) AS a%d", strings.ToUpper(string(v.Function)), i))
+		vars = append(vars, clause.Column{Name: name})
+	}
+
+	db = db.Select(strings.Join(selects, ", "), vars...)
+
+	if len(groupBy.Columns) > 0 {
+		db = db.Clauses(groupBy, orderBy)
+	}
+
+	rows, err := db.Rows()
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	result := make([]core.AggregateRow, 0)
+
+	for rows.Next() {
+		keys := make([]interface{}, len(aggregation.GroupBy))
+		values := make([]sql.NullFloat64, len(aggregation.Aggregates))
+		dest := make([]interface{}, 0, len(keys)+len(values))
+
+		for i := range keys {
+			dest = append(dest, &keys[i])
+		}
+
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		row := core.AggregateRow{
+			Keys:   make(map[string]interface{}, len(keys)),
+			Values: make(map[string]float64, len(values)),
+		}
+
+		for i, v := range aggregation.GroupBy {
+			// some drivers scan the text as bytes
+			if b, ok := keys[i].([]byte); ok {
+				keys[i] = string(b)
+			}
+
+			row.Keys[v] = keys[i]
+		}
+
+		for i, v := range aggregation.Aggregates {
+			row.Values[v.Alias] = values[i].Float64
+		}
+
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
@@ -0,0 +1,107 @@
+package gorms
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"gorm.io/gorm"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+// clientKey identifies the client by the full configuration, the adapters of the same configuration share the client
+func (a *adapter) clientKey(connectionString string) string {
+	config, _ := json.Marshal(struct {
+		ConnectionString string
+		Pool             GormPoolSettings
+		TLS              core.TLSSettings
+	}{
+		ConnectionString: connectionString,
+		Pool:             a.settings.Pool,
+		TLS:              a.settings.TLS,
+	})
+
+	sum := sha256.Sum256(config)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// openClient returns the shared client of the configuration, opens it when it is not connected.
+// The caller holds the lock of clientsInstance
+func (a *adapter) openClient(connectionString string) (*clientProxy, error) {
+	key := a.clientKey(connectionString)
+
+	if cli, ok := clientsInstance.clients[key]; ok && cli.isConnected {
+		cli.refs++
+
+		return cli, nil
+	}
+
+	dialector, err := a.open(connectionString, a.settings.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := registerTranslator(db); err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	pool := a.settings.Pool
+
+	if pool.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(pool.MaxOpenConns)
+	}
+
+	if pool.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(pool.MaxIdleConns)
+	}
+
+	if pool.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	}
+
+	if pool.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+	}
+
+	client := &clientProxy{
+		db:          db.Session(&gorm.Session{SkipDefaultTransaction: true}),
+		key:         key,
+		refs:        1,
+		isConnected: true,
+	}
+
+	clientsInstance.clients[key] = client
+
+	return client, nil
+}
+
+// releaseClient closes the pool once no adapter holds the client, the caller holds the lock of clientsInstance
+func releaseClient(client *clientProxy) {
+	client.refs--
+
+	if client.refs > 0 {
+		return
+	}
+
+	client.isConnected = false
+
+	if clientsInstance.clients[client.key] == client {
+		delete(clientsInstance.clients, client.key)
+	}
+
+	if db, err := client.db.DB(); err == nil {
+		db.Close()
+	}
+}
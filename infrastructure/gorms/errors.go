@@ -0,0 +1,132 @@
+package gorms
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+// registerTranslator translates the driver errors of every statement of db
+func registerTranslator(db *gorm.DB) error {
+	callbacks := db.Callback()
+
+	translator := func(db *gorm.DB) {
+		if db.Error != nil {
+			db.Error = translate(db.Error)
+		}
+	}
+
+	if err := callbacks.Create().After("gorm:create").Register("core:translate", translator); err != nil {
+		return err
+	}
+
+	if err := callbacks.Query().After("gorm:query").Register("core:translate", translator); err != nil {
+		return err
+	}
+
+	if err := callbacks.Update().After("gorm:update").Register("core:translate", translator); err != nil {
+		return err
+	}
+
+	if err := callbacks.Delete().After("gorm:delete").Register("core:translate", translator); err != nil {
+		return err
+	}
+
+	if err := callbacks.Row().After("gorm:row").Register("core:translate", translator); err != nil {
+		return err
+	}
+
+	return callbacks.Raw().After("gorm:raw").Register("core:translate", translator)
+}
+
+// translate maps the constraint violations and the timeouts to the core errors of the safe messages,
+// the driver error is kept as the cause which is never rendered to the clients
+func translate(err error) error {
+	var coreErr *core.Error
+
+	if err == nil || errors.As(err, &coreErr) || errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	var pgErr *pgconn.PgError
+	var mysqlErr *mysql.MySQLError
+	var mssqlErr mssql.Error
+	var sqliteErr sqlite3.Error
+	var netErr net.Error
+
+	switch {
+	case errors.As(err, &pgErr):
+		switch pgErr.Code {
+		case "23505":
+			return conflict(err)
+		case "23503":
+			return foreignKey(err)
+		case "57014":
+			return timeout(err)
+		}
+	case errors.As(err, &mysqlErr):
+		switch mysqlErr.Number {
+		case 1062:
+			return conflict(err)
+		case 1451, 1452:
+			return foreignKey(err)
+		case 3024:
+			return timeout(err)
+		}
+	case errors.As(err, &mssqlErr):
+		switch mssqlErr.Number {
+		case 2601, 2627:
+			return conflict(err)
+		case 547:
+			return foreignKey(err)
+		}
+	case errors.As(err, &sqliteErr):
+		switch sqliteErr.ExtendedCode {
+		case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+			return conflict(err)
+		case sqlite3.ErrConstraintForeignKey:
+			return foreignKey(err)
+		}
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return timeout(err)
+	}
+
+	// the oracle drivers share the error codes of the message
+	message := err.Error()
+
+	switch {
+	case strings.Contains(message, "ORA-00001"):
+		return conflict(err)
+	case strings.Contains(message, "ORA-02291"), strings.Contains(message, "ORA-02292"):
+		return foreignKey(err)
+	case strings.Contains(message, "ORA-01013"):
+		return timeout(err)
+	}
+
+	return err
+}
+
+func conflict(err error) error {
+	return core.NewError(core.ErrConflict, core.CodeUniqueViolation, "unique constraint violated").WithCause(err)
+}
+
+func foreignKey(err error) error {
+	return core.NewError(core.ErrBadRequest, core.CodeForeignKeyViolation, "foreign key constraint violated").WithCause(err)
+}
+
+func timeout(err error) error {
+	return core.NewError(context.DeadlineExceeded, core.CodeTimeout, "statement timed out").WithCause(err)
+}
+
+func notFound(message string) error {
+	return core.NewError(core.ErrNotFound, core.CodeNotFound, message)
+}
@@ -4,24 +4,37 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jybbang/go-core-architecture/core"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type adapter struct {
-	tableName string
-	model     core.Entitier
-	dialector gorm.Dialector
-	client    *clientProxy
-	settings  GormSettings
+	tableName     string
+	model         core.Entitier
+	open          func(connectionString string, tlsSettings core.TLSSettings) (gorm.Dialector, error)
+	client        *clientProxy
+	tenantClients map[string]*clientProxy
+	replicas      *replicaSet
+	connected     bool
+	settings      GormSettings
+	// migrated is guarded by the lock of clientsInstance
+	migrated map[string]bool
+	sync.RWMutex
 }
 
+// clientProxy is shared by the adapters of the same configuration,
+// refs and isConnected are guarded by the lock of clientsInstance
 type clientProxy struct {
 	db          *gorm.DB
+	key         string
+	refs        int
 	isConnected bool
 }
 
@@ -32,7 +45,38 @@ type clients struct {
 
 type GormSettings struct {
 	ConnectionString string
-	CanCreateTable   bool
+	// DriverName of database/sql replaces the bundled driver of the oracle adapter
+	DriverName     string
+	CanCreateTable bool
+	// TenantConnectionStrings maps tenant id to its own database
+	TenantConnectionStrings map[string]string
+	// AutoMigrate creates the table and adds its missing columns and indexes
+	AutoMigrate bool
+	// Migrations are the versioned migrations of the repository, see MigrateUp
+	Migrations []Migration
+	// MigrateOnConnect applies the pending migrations on connect
+	MigrateOnConnect bool
+	// BatchSize is the number of rows per statement of the bulk commands, default 100
+	BatchSize int
+	// Replicas are the connection strings of the read replicas, the queries read from them
+	// unless the context requires the primary, see core.WithReadYourWrites
+	Replicas []string
+	// ReplicaPolicy balances the queries between the healthy replicas
+	ReplicaPolicy ReplicaPolicy
+	// ReplicaHealthCheckInterval ejects and restores the replicas, default 10s
+	ReplicaHealthCheckInterval time.Duration
+	// Pool applies to the primary, the tenant and the replica databases
+	Pool GormPoolSettings
+	// TLS replaces the TLS parameters of the connection strings, sqlite does not support it
+	TLS core.TLSSettings
+}
+
+// GormPoolSettings of the database/sql pool, zero keeps the driver defaults
+type GormPoolSettings struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
 }
 
 var clientsInstance *clients
@@ -43,66 +87,221 @@ func init() {
 	}
 }
 
-func (a *adapter) migration() {
-	if !a.client.db.Migrator().HasTable(a.tableName) && a.settings.CanCreateTable {
-		if !a.client.db.Migrator().HasTable(a.model) {
-			a.client.db.Migrator().CreateTable(a.model)
+func (a *adapter) migration(client *clientProxy) error {
+	db := client.db.Table(a.tableName)
+
+	if a.settings.AutoMigrate {
+		if err := db.AutoMigrate(a.model); err != nil {
+			return err
+		}
+	} else if a.settings.CanCreateTable && !db.Migrator().HasTable(a.tableName) {
+		if err := db.Migrator().CreateTable(a.model); err != nil {
+			return err
 		}
+	}
 
-		a.client.db.Migrator().RenameTable(a.model, a.tableName)
+	if a.settings.MigrateOnConnect {
+		if _, err := a.migrateUp(client.db); err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
 func (a *adapter) IsConnected() bool {
-	return a.client.isConnected
+	a.RLock()
+	defer a.RUnlock()
+
+	return a.connected
 }
 
 func (a *adapter) Connect(ctx context.Context) error {
+	client, err := a.connect(ctx, a.settings.ConnectionString)
+	if err != nil {
+		return err
+	}
+
+	replicas, err := a.connectReplicas(ctx)
+	if err != nil {
+		clientsInstance.Lock()
+		releaseClient(client)
+		clientsInstance.Unlock()
+
+		return err
+	}
+
+	a.Lock()
+	previous, previousTenants, previousReplicas, connected := a.client, a.tenantClients, a.replicas, a.connected
+	a.client = client
+	a.tenantClients = make(map[string]*clientProxy)
+	a.replicas = replicas
+	a.connected = true
+	a.Unlock()
+
+	clientsInstance.Lock()
+	defer clientsInstance.Unlock()
+
+	if connected {
+		releaseClient(previous)
+	}
+
+	// the tenant clients connect lazily again
+	for _, v := range previousTenants {
+		releaseClient(v)
+	}
+
+	if previousReplicas != nil {
+		previousReplicas.close()
+	}
+
+	return nil
+}
+
+func (a *adapter) connect(ctx context.Context, connectionString string) (*clientProxy, error) {
 	clientsInstance.Lock()
 	defer clientsInstance.Unlock()
 
-	connectionString := a.settings.ConnectionString
+	return a.connectClient(ctx, connectionString)
+}
 
+// connectClient migrates the database once per connection string, the caller holds the lock of clientsInstance
+func (a *adapter) connectClient(ctx context.Context, connectionString string) (*clientProxy, error) {
 	if strings.TrimSpace(connectionString) == "" {
-		return fmt.Errorf("connectionString is required")
+		return nil, fmt.Errorf("connectionString is required")
 	}
 
-	cli, ok := clientsInstance.clients[connectionString]
+	client, err := a.openClient(connectionString)
+	if err != nil {
+		return nil, err
+	}
 
-	if !ok || !cli.isConnected {
-		db, err := gorm.Open(a.dialector, &gorm.Config{})
+	// Check context cancellation
+	if err := ctx.Err(); err != nil {
+		releaseClient(client)
 
-		if err != nil {
-			return err
-		}
+		return nil, err
+	}
 
-		tx := db.Session(&gorm.Session{SkipDefaultTransaction: true})
+	key := a.clientKey(connectionString)
 
-		// Check context cancellation
-		if err := ctx.Err(); err != nil {
-			return err
+	if a.tableName != "" && !a.migrated[key] {
+		if err := a.migration(client); err != nil {
+			releaseClient(client)
+
+			return nil, err
 		}
 
-		clientsInstance.clients[connectionString] = &clientProxy{
-			db:          tx,
-			isConnected: true,
+		if a.migrated == nil {
+			a.migrated = make(map[string]bool)
 		}
+
+		a.migrated[key] = true
 	}
 
-	a.client = clientsInstance.clients[connectionString]
+	return client, nil
+}
+
+func (a *adapter) Disconnect() {
+	clientsInstance.Lock()
+	defer clientsInstance.Unlock()
 
-	if a.tableName != "" {
-		a.migration()
+	a.Lock()
+	defer a.Unlock()
+
+	// the pools are closed once no other adapter shares them, the next Connect opens them again
+	if a.connected {
+		releaseClient(a.client)
 	}
 
-	return nil
+	a.connected = false
+
+	for _, v := range a.tenantClients {
+		releaseClient(v)
+	}
+
+	a.tenantClients = make(map[string]*clientProxy)
+
+	if a.replicas != nil {
+		a.replicas.close()
+		a.replicas = nil
+	}
 }
 
-func (a *adapter) Disconnect() {
+// tenantClient returns the client of the tenant database, connects lazily on first use
+func (a *adapter) tenantClient(ctx context.Context) (*clientProxy, error) {
+	tenantId, ok := core.TenantFromContext(ctx)
+	if !ok {
+		return a.client, nil
+	}
+
+	connectionString, ok := a.settings.TenantConnectionStrings[tenantId]
+	if !ok {
+		return a.client, nil
+	}
+
+	if client, ok := a.connectedTenant(tenantId); ok {
+		return client, nil
+	}
+
 	clientsInstance.Lock()
 	defer clientsInstance.Unlock()
 
-	a.client.isConnected = false
+	// another call may have connected the tenant while waiting for the lock
+	if client, ok := a.connectedTenant(tenantId); ok {
+		return client, nil
+	}
+
+	client, err := a.connectClient(ctx, connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	a.tenantClients[tenantId] = client
+
+	return client, nil
+}
+
+func (a *adapter) connectedTenant(tenantId string) (*clientProxy, bool) {
+	a.RLock()
+	defer a.RUnlock()
+
+	client, ok := a.tenantClients[tenantId]
+
+	return client, ok && client.isConnected
+}
+
+// scoped returns the table of the tenant database filtered by the tenant
+func (a *adapter) scoped(ctx context.Context) (*gorm.DB, error) {
+	client, err := a.tenantClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.scope(ctx, client.db), nil
+}
+
+func (a *adapter) scope(ctx context.Context, db *gorm.DB) *gorm.DB {
+	db = db.WithContext(ctx).Table(a.tableName)
+
+	if tenantId, ok := core.TenantScope(ctx, a.model); ok {
+		db = db.Where("tenant_id = ?", tenantId)
+	}
+
+	if scope, ok := core.DeletedScopeOf(ctx, a.model); ok {
+		switch scope {
+		case core.DeletedExcluded:
+			db = db.Where("deleted_at IS NULL")
+		case core.DeletedOnly:
+			db = db.Where("deleted_at IS NOT NULL")
+		}
+	}
+
+	// the session is safe to be reused by the following statements
+	return db.Session(&gorm.Session{})
 }
 
 func (a *adapter) SetModel(model core.Entitier, tableName string) {
@@ -111,7 +310,12 @@ func (a *adapter) SetModel(model core.Entitier, tableName string) {
 }
 
 func (a *adapter) Find(ctx context.Context, id uuid.UUID, dest core.Entitier) error {
-	result := a.client.db.WithContext(ctx).Table(a.tableName).Take(dest, id)
+	db, err := a.reader(ctx)
+	if err != nil {
+		return err
+	}
+
+	result := db.Take(dest, id)
 
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return core.ErrNotFound
@@ -135,9 +339,14 @@ func (a *adapter) AnyWithFilter(ctx context.Context, query interface{}, args int
 }
 
 func (a *adapter) Count(ctx context.Context) (count int64, err error) {
+	db, err := a.reader(ctx)
+	if err != nil {
+		return 0, err
+	}
+
 	resp := new(int64)
 
-	result := a.client.db.WithContext(ctx).Table(a.tableName).Count(resp)
+	result := db.Count(resp)
 
 	if result.Error != nil {
 		return 0, result.Error
@@ -147,9 +356,19 @@ func (a *adapter) Count(ctx context.Context) (count int64, err error) {
 }
 
 func (a *adapter) CountWithFilter(ctx context.Context, query interface{}, args interface{}) (count int64, err error) {
+	db, err := a.reader(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	db, err = a.where(db, query, args)
+	if err != nil {
+		return 0, err
+	}
+
 	resp := new(int64)
 
-	result := a.client.db.WithContext(ctx).Table(a.tableName).Where(query, args).Count(resp)
+	result := db.Count(resp)
 
 	if result.Error != nil {
 		return 0, result.Error
@@ -159,53 +378,219 @@ func (a *adapter) CountWithFilter(ctx context.Context, query interface{}, args i
 }
 
 func (a *adapter) List(ctx context.Context, dest interface{}) error {
-	result := a.client.db.WithContext(ctx).Table(a.tableName).Find(dest)
+	db, err := a.reader(ctx)
+	if err != nil {
+		return err
+	}
+
+	result := db.Find(dest)
 
 	return result.Error
 }
 
 func (a *adapter) ListWithFilter(ctx context.Context, query interface{}, args interface{}, dest interface{}) error {
-	result := a.client.db.WithContext(ctx).Table(a.tableName).Where(query, args).Find(dest)
+	db, err := a.reader(ctx)
+	if err != nil {
+		return err
+	}
+
+	db, err = a.where(db, query, args)
+	if err != nil {
+		return err
+	}
+
+	result := db.Find(dest)
 
 	return result.Error
 }
 
+func (a *adapter) ListWithOptions(ctx context.Context, query interface{}, options core.QueryOptions, dest interface{}) error {
+	db, err := a.reader(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := query.(*core.Specification); ok {
+		db, err = a.where(db, query, nil)
+		if err != nil {
+			return err
+		}
+	} else if query != nil {
+		db = db.Where(query)
+	}
+
+	for _, v := range options.OrderBy {
+		column, err := a.column(db, v.Field)
+		if err != nil {
+			return err
+		}
+
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: v.Descending})
+	}
+
+	if len(options.Fields) > 0 {
+		columns := make([]string, 0, len(options.Fields))
+
+		for _, v := range options.Fields {
+			column, err := a.column(db, v)
+			if err != nil {
+				return err
+			}
+
+			columns = append(columns, column)
+		}
+
+		db = db.Select(columns)
+	}
+
+	if options.Offset > 0 {
+		db = db.Offset(options.Offset)
+	}
+
+	if options.Limit > 0 {
+		db = db.Limit(options.Limit)
+	}
+
+	result := db.Find(dest)
+
+	return result.Error
+}
+
+func (a *adapter) Iterate(ctx context.Context, query interface{}, fn func(entity core.Entitier) error) error {
+	db, err := a.reader(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := query.(*core.Specification); ok {
+		db, err = a.where(db, query, nil)
+		if err != nil {
+			return err
+		}
+	} else if query != nil {
+		db = db.Where(query)
+	}
+
+	rows, err := db.Rows()
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	modelType := reflect.TypeOf(a.model).Elem()
+
+	for rows.Next() {
+		// Check context cancellation
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		entity := reflect.New(modelType).Interface().(core.Entitier)
+
+		if err := db.ScanRows(rows, entity); err != nil {
+			return err
+		}
+
+		if err := fn(entity); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 func (a *adapter) Remove(ctx context.Context, id uuid.UUID) error {
-	result := a.client.db.WithContext(ctx).Table(a.tableName).Delete(a.model, id)
+	db, err := a.scoped(ctx)
+	if err != nil {
+		return err
+	}
+
+	result := db.Delete(a.model, id)
+
+	if result.Error == nil && result.RowsAffected == 0 {
+		return notFound(fmt.Sprintf("%s is not found", id))
+	}
 
 	return result.Error
 }
 
 func (a *adapter) RemoveRange(ctx context.Context, ids []uuid.UUID) error {
-	err := a.client.db.WithContext(ctx).Table(a.tableName).Transaction(func(tx *gorm.DB) error {
-		for _, id := range ids {
-			err := a.client.db.WithContext(ctx).Table(a.tableName).Delete(a.model, id).Error
+	db, err := a.scoped(ctx)
+	if err != nil {
+		return err
+	}
 
-			if err != nil {
-				return err
-			}
+	unique := make(map[uuid.UUID]bool, len(ids))
+
+	for _, v := range ids {
+		unique[v] = true
+	}
+
+	// every entity is removed or none of them
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(a.model, ids)
+
+		if result.Error == nil && result.RowsAffected != int64(len(unique)) {
+			return notFound(fmt.Sprintf("%d of %d entities are not found", int64(len(unique))-result.RowsAffected, len(unique)))
 		}
 
-		return nil
+		return result.Error
 	})
-
-	return err
 }
 
 func (a *adapter) Add(ctx context.Context, entity core.Entitier) error {
-	result := a.client.db.WithContext(ctx).Table(a.tableName).Create(entity)
+	db, err := a.scoped(ctx)
+	if err != nil {
+		return err
+	}
+
+	result := db.Create(entity)
 
 	return result.Error
 }
 
 func (a *adapter) AddRange(ctx context.Context, entities []core.Entitier) error {
-	err := a.client.db.WithContext(ctx).Table(a.tableName).Transaction(func(tx *gorm.DB) error {
+	db, err := a.scoped(ctx)
+	if err != nil {
+		return err
+	}
+
+	result := db.CreateInBatches(a.typedSlice(entities), a.batchSize())
+
+	return result.Error
+}
+
+func (a *adapter) Update(ctx context.Context, entity core.Entitier) error {
+	db, err := a.scoped(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := a.update(db, entity); err != nil {
+		return err
+	}
+
+	return a.restore(ctx, db, entity)
+}
+
+func (a *adapter) UpdateRange(ctx context.Context, entities []core.Entitier) error {
+	db, err := a.scoped(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
 		for _, entity := range entities {
-			err := a.client.db.WithContext(ctx).Table(a.tableName).Create(entity).Error
+			err := a.update(tx, entity)
 
 			if err != nil {
 				return err
 			}
+
+			if err := a.restore(ctx, tx, entity); err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -214,24 +599,249 @@ func (a *adapter) AddRange(ctx context.Context, entities []core.Entitier) error
 	return err
 }
 
-func (a *adapter) Update(ctx context.Context, entity core.Entitier) error {
-	result := a.client.db.WithContext(ctx).Table(a.tableName).Updates(entity)
+// Upsert inserts the entity or updates the existing row except its creation stamp
+func (a *adapter) Upsert(ctx context.Context, entity core.Entitier) (affected int64, err error) {
+	return a.UpsertRange(ctx, []core.Entitier{entity})
+}
 
-	return result.Error
+// UpsertRange inserts the entities in batches, the existing rows are updated except their creation stamp
+func (a *adapter) UpsertRange(ctx context.Context, entities []core.Entitier) (affected int64, err error) {
+	db, err := a.scoped(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := a.guardTenant(ctx, entities); err != nil {
+		return 0, err
+	}
+
+	onConflict, err := a.onConflict(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	result := db.Clauses(onConflict).CreateInBatches(a.typedSlice(entities), a.batchSize())
+
+	return result.RowsAffected, result.Error
 }
 
-func (a *adapter) UpdateRange(ctx context.Context, entities []core.Entitier) error {
-	err := a.client.db.WithContext(ctx).Table(a.tableName).Transaction(func(tx *gorm.DB) error {
-		for _, entity := range entities {
-			err := a.client.db.WithContext(ctx).Table(a.tableName).Updates(entity).Error
+func (a *adapter) Patch(ctx context.Context, id uuid.UUID, fields map[string]interface{}) (affected int64, err error) {
+	db, err := a.scoped(ctx)
+	if err != nil {
+		return 0, err
+	}
 
-			if err != nil {
-				return err
-			}
+	values, err := a.assignments(db, fields)
+	if err != nil {
+		return 0, err
+	}
+
+	result := db.Where("id = ?", id).Updates(values)
+
+	return result.RowsAffected, result.Error
+}
+
+func (a *adapter) UpdateWhere(ctx context.Context, query interface{}, fields map[string]interface{}) (affected int64, err error) {
+	db, err := a.scoped(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	values, err := a.assignments(db, fields)
+	if err != nil {
+		return 0, err
+	}
+
+	db, err = a.where(db, query, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result := db.Updates(values)
+
+	return result.RowsAffected, result.Error
+}
+
+func (a *adapter) RemoveWhere(ctx context.Context, query interface{}) (affected int64, err error) {
+	db, err := a.scoped(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	db, err = a.where(db, query, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result := db.Delete(a.model)
+
+	return result.RowsAffected, result.Error
+}
+
+func (a *adapter) batchSize() int {
+	if a.settings.BatchSize < 1 {
+		return 100
+	}
+
+	return a.settings.BatchSize
+}
+
+// typedSlice converts the entities to the slice of the model, gorm can not create the slice of interfaces
+func (a *adapter) typedSlice(entities []core.Entitier) interface{} {
+	slice := reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(a.model)), 0, len(entities))
+
+	for _, v := range entities {
+		slice = reflect.Append(slice, reflect.ValueOf(v))
+	}
+
+	return slice.Interface()
+}
+
+// onConflict updates every column but the key and the creation stamp, the version is incremented
+func (a *adapter) onConflict(ctx context.Context, db *gorm.DB) (clause.OnConflict, error) {
+	stmt := &gorm.Statement{DB: db}
+
+	if err := stmt.Parse(a.model); err != nil {
+		return clause.OnConflict{}, err
+	}
+
+	columns := make([]string, 0, len(stmt.Schema.DBNames))
+
+	for _, v := range stmt.Schema.DBNames {
+		// the oracle names are upper cased
+		switch strings.ToLower(v) {
+		case "id", "create_user", "created_at", "version":
+			continue
 		}
 
+		columns = append(columns, v)
+	}
+
+	onConflict := clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns(columns),
+	}
+
+	if _, ok := a.model.(core.Versioned); ok {
+		onConflict.DoUpdates = append(onConflict.DoUpdates, clause.Assignment{
+			Column: clause.Column{Name: "version"},
+			Value:  clause.Expr{SQL: "? + 1", Vars: []interface{}{clause.Column{Table: a.tableName, Name: "version"}}},
+		})
+	}
+
+	// the row of another tenant is not overwritten
+	if tenantId, ok := core.TenantScope(ctx, a.model); ok {
+		onConflict.Where = clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: a.tableName, Name: "tenant_id"}, Value: tenantId},
+		}}
+	}
+
+	return onConflict, nil
+}
+
+// guardTenant rejects the ids owned by another tenant before the upsert,
+// the conflict condition of onConflict is ignored by ON DUPLICATE KEY UPDATE of mysql
+func (a *adapter) guardTenant(ctx context.Context, entities []core.Entitier) error {
+	tenantId, ok := core.TenantScope(ctx, a.model)
+	if !ok {
 		return nil
-	})
+	}
 
-	return err
+	client, err := a.tenantClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]uuid.UUID, len(entities))
+
+	for i, v := range entities {
+		ids[i] = v.GetID()
+	}
+
+	var count int64
+
+	result := client.db.WithContext(ctx).Table(a.tableName).
+		Where("id IN ? AND tenant_id <> ?", ids, tenantId).
+		Count(&count)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if count > 0 {
+		return core.NewError(core.ErrConflict, core.CodeUniqueViolation, fmt.Sprintf("%d of %d entities belong to another tenant", count, len(ids)))
+	}
+
+	return nil
+}
+
+// assignments maps the fields to the columns, the version is incremented
+func (a *adapter) assignments(db *gorm.DB, fields map[string]interface{}) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(fields)+1)
+
+	for k, v := range fields {
+		column, err := a.column(db, k)
+		if err != nil {
+			return nil, err
+		}
+
+		values[column] = v
+	}
+
+	if _, ok := a.model.(core.Versioned); ok {
+		values["version"] = gorm.Expr("version + 1")
+	}
+
+	return values, nil
+}
+
+// update increments the version conditionally when the entity is versioned
+func (a *adapter) update(db *gorm.DB, entity core.Entitier) error {
+	versioned, ok := entity.(core.Versioned)
+	if !ok {
+		result := db.Updates(entity)
+
+		if result.Error == nil && result.RowsAffected == 0 {
+			return notFound(fmt.Sprintf("%s is not found", entity.GetID()))
+		}
+
+		return result.Error
+	}
+
+	expected := versioned.GetVersion()
+	versioned.SetVersion(expected + 1)
+
+	result := db.Where("version = ?", expected).Updates(entity)
+
+	if result.Error == nil && result.RowsAffected == 0 {
+		var count int64
+
+		if err := db.Where("id = ?", entity.GetID()).Count(&count).Error; err != nil {
+			result.Error = err
+		} else if count == 0 {
+			result.Error = notFound(fmt.Sprintf("%s is not found", entity.GetID()))
+		} else {
+			result.Error = fmt.Errorf("%w version %d is changed", core.ErrConflict, expected)
+		}
+	}
+
+	if result.Error != nil {
+		versioned.SetVersion(expected)
+	}
+
+	return result.Error
+}
+
+// restore clears the deleted columns which are skipped by Updates as zero values
+func (a *adapter) restore(ctx context.Context, db *gorm.DB, entity core.Entitier) error {
+	scope, ok := core.DeletedScopeOf(ctx, entity)
+
+	if !ok || scope == core.DeletedExcluded || entity.(core.SoftDeleter).IsDeleted() {
+		return nil
+	}
+
+	return db.Where("id = ?", entity.GetID()).Updates(map[string]interface{}{
+		"deleted_at":  nil,
+		"delete_user": "",
+	}).Error
 }
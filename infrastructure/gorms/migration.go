@@ -0,0 +1,398 @@
+package gorms
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// migrationHistoryTable records the applied migrations of every repository
+const migrationHistoryTable = "schema_migrations"
+
+// Migration changes the schema of a repository, tx is a transaction on the repository table.
+// Version orders the migrations lexically, so it should be zero padded e.g. 20210801120000
+type Migration struct {
+	Version string
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+type migrationHistory struct {
+	Repository string `gorm:"primaryKey;size:255"`
+	Version    string `gorm:"primaryKey;size:255"`
+	Name       string
+	AppliedAt  time.Time
+}
+
+// statementLogger collects the statements of the dry run
+type statementLogger struct {
+	logger.Interface
+	statements []string
+}
+
+func (l *statementLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return l
+}
+
+func (l *statementLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if sql, _ := fc(); sql != "" {
+		l.statements = append(l.statements, sql)
+	}
+}
+
+// SqlMigration executes the scripts statement by statement, a statement ends with a semicolon at the end of line.
+// {{table}} in the scripts is replaced with the repository table name
+func SqlMigration(version string, name string, up string, down string) Migration {
+	migration := Migration{
+		Version: version,
+		Name:    name,
+		Up:      execScript(up),
+	}
+
+	if strings.TrimSpace(down) != "" {
+		migration.Down = execScript(down)
+	}
+
+	return migration
+}
+
+// LoadSqlMigrations reads the <version>_<name>.up.sql and <version>_<name>.down.sql files of dir
+func LoadSqlMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	scripts := make(map[string][2]string)
+
+	for _, v := range entries {
+		name := v.Name()
+
+		if v.IsDir() || !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+
+		key := strings.TrimSuffix(name, ".sql")
+		direction := 0
+
+		switch {
+		case strings.HasSuffix(key, ".up"):
+			key = strings.TrimSuffix(key, ".up")
+		case strings.HasSuffix(key, ".down"):
+			key = strings.TrimSuffix(key, ".down")
+			direction = 1
+		default:
+			return nil, fmt.Errorf("migration %s is neither up nor down", name)
+		}
+
+		script, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		pair := scripts[key]
+		pair[direction] = string(script)
+		scripts[key] = pair
+	}
+
+	migrations := make([]Migration, 0, len(scripts))
+
+	for k, v := range scripts {
+		if strings.TrimSpace(v[0]) == "" {
+			return nil, fmt.Errorf("migration %s has no up script", k)
+		}
+
+		version := k
+		name := ""
+
+		if i := strings.Index(k, "_"); i > 0 {
+			version = k[:i]
+			name = k[i+1:]
+		}
+
+		migrations = append(migrations, SqlMigration(version, name, v[0], v[1]))
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func execScript(script string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		for _, v := range splitStatements(script) {
+			if err := tx.Exec(strings.ReplaceAll(v, "{{table}}", tx.Statement.Table)).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func splitStatements(script string) []string {
+	statements := make([]string, 0)
+	statement := make([]string, 0)
+
+	for _, line := range strings.Split(script, "\n") {
+		statement = append(statement, line)
+
+		if strings.HasSuffix(strings.TrimSpace(line), ";") {
+			if v := strings.TrimSpace(strings.Join(statement, "\n")); v != "" {
+				statements = append(statements, v)
+			}
+
+			statement = statement[:0]
+		}
+	}
+
+	if v := strings.TrimSpace(strings.Join(statement, "\n")); v != "" {
+		statements = append(statements, v)
+	}
+
+	return statements
+}
+
+// PendingMigrations returns the versions not applied to the database of ctx
+func (a *adapter) PendingMigrations(ctx context.Context) ([]string, error) {
+	client, err := a.tenantClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := a.pendingMigrations(client.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]string, 0, len(pending))
+
+	for _, v := range pending {
+		versions = append(versions, v.Version)
+	}
+
+	return versions, nil
+}
+
+// MigrateUp applies the pending migrations in order, each one in its own transaction
+func (a *adapter) MigrateUp(ctx context.Context) ([]string, error) {
+	client, err := a.tenantClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.migrateUp(client.db.WithContext(ctx))
+}
+
+// MigrateDown reverts the last applied migrations
+func (a *adapter) MigrateDown(ctx context.Context, steps int) ([]string, error) {
+	client, err := a.tenantClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := client.db.WithContext(ctx)
+
+	migrations, err := a.migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := a.appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	reverted := make([]string, 0, steps)
+
+	for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+		migration := migrations[i]
+
+		if !applied[migration.Version] {
+			continue
+		}
+
+		if migration.Down == nil {
+			return reverted, fmt.Errorf("migration %s can not be reverted", migration.Version)
+		}
+
+		err := db.Table(a.tableName).Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+
+			return tx.Table(migrationHistoryTable).
+				Where("repository = ? AND version = ?", a.tableName, migration.Version).
+				Delete(&migrationHistory{}).Error
+		})
+
+		if err != nil {
+			return reverted, fmt.Errorf("migration %s: %w", migration.Version, err)
+		}
+
+		reverted = append(reverted, migration.Version)
+	}
+
+	return reverted, nil
+}
+
+// MigrateUpDryRun returns the statements of the pending migrations without executing them,
+// the migrations inspecting the database are not supported
+func (a *adapter) MigrateUpDryRun(ctx context.Context) (statements []string, err error) {
+	client, err := a.tenantClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := client.db.WithContext(ctx)
+
+	pending, err := a.pendingMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	log := &statementLogger{Interface: logger.Discard}
+	dry := db.Session(&gorm.Session{DryRun: true, Logger: log})
+
+	for _, v := range pending {
+		if err := dryRun(dry.Table(a.tableName), v); err != nil {
+			return nil, err
+		}
+
+		history := migrationHistory{Repository: a.tableName, Version: v.Version, Name: v.Name, AppliedAt: time.Now()}
+
+		if err := dry.Table(migrationHistoryTable).Create(&history).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return log.statements, nil
+}
+
+func dryRun(tx *gorm.DB, migration Migration) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("migration %s does not support dry run: %v", migration.Version, r)
+		}
+	}()
+
+	return migration.Up(tx)
+}
+
+func (a *adapter) migrateUp(db *gorm.DB) ([]string, error) {
+	if err := db.Table(migrationHistoryTable).AutoMigrate(&migrationHistory{}); err != nil {
+		return nil, err
+	}
+
+	pending, err := a.pendingMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make([]string, 0, len(pending))
+
+	for _, v := range pending {
+		migration := v
+
+		err := db.Table(a.tableName).Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+
+			history := migrationHistory{
+				Repository: a.tableName,
+				Version:    migration.Version,
+				Name:       migration.Name,
+				AppliedAt:  time.Now(),
+			}
+
+			return tx.Table(migrationHistoryTable).Create(&history).Error
+		})
+
+		if err != nil {
+			return applied, fmt.Errorf("migration %s: %w", migration.Version, err)
+		}
+
+		applied = append(applied, migration.Version)
+	}
+
+	return applied, nil
+}
+
+func (a *adapter) pendingMigrations(db *gorm.DB) ([]Migration, error) {
+	migrations, err := a.migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := a.appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]Migration, 0, len(migrations))
+
+	for _, v := range migrations {
+		if !applied[v.Version] {
+			pending = append(pending, v)
+		}
+	}
+
+	return pending, nil
+}
+
+// migrations returns the registered migrations ordered by version
+func (a *adapter) migrations() ([]Migration, error) {
+	migrations := make([]Migration, len(a.settings.Migrations))
+	copy(migrations, a.settings.Migrations)
+
+	sort.SliceStable(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	for i, v := range migrations {
+		if v.Version == "" || v.Up == nil {
+			return nil, fmt.Errorf("migration %q requires version and up", v.Name)
+		}
+
+		if i > 0 && migrations[i-1].Version == v.Version {
+			return nil, fmt.Errorf("migration %s is duplicated", v.Version)
+		}
+	}
+
+	return migrations, nil
+}
+
+func (a *adapter) appliedMigrations(db *gorm.DB) (map[string]bool, error) {
+	if !db.Migrator().HasTable(migrationHistoryTable) {
+		return map[string]bool{}, nil
+	}
+
+	versions := make([]string, 0)
+
+	err := db.Table(migrationHistoryTable).
+		Where("repository = ?", a.tableName).
+		Pluck("version", &versions).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[string]bool, len(versions))
+
+	for _, v := range versions {
+		applied[v] = true
+	}
+
+	return applied, nil
+}
@@ -1,12 +1,45 @@
 package gorms
 
 import (
+	"fmt"
+
+	driver "github.com/go-sql-driver/mysql"
 	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"github.com/jybbang/go-core-architecture/core"
 )
 
 func NewMySqlAdapter(settings GormSettings) *adapter {
 	return &adapter{
-		dialector: mysql.Open(settings.ConnectionString),
-		settings:  settings,
+		open:     openMySql,
+		settings: settings,
+	}
+}
+
+func openMySql(connectionString string, tlsSettings core.TLSSettings) (gorm.Dialector, error) {
+	if !tlsSettings.Enabled {
+		return mysql.Open(connectionString), nil
+	}
+
+	tlsConfig, err := tlsSettings.ToTLSConfig()
+	if err != nil {
+		return nil, err
 	}
+
+	config, err := driver.ParseDSN(connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	// the driver looks up the tls config by the name in the connection string
+	name := fmt.Sprintf("gorms-%p", tlsConfig)
+
+	if err := driver.RegisterTLSConfig(name, tlsConfig); err != nil {
+		return nil, err
+	}
+
+	config.TLSConfig = name
+
+	return mysql.Open(config.FormatDSN()), nil
 }
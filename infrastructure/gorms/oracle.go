@@ -0,0 +1,417 @@
+package gorms
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	_ "github.com/sijms/go-ora/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+// defaultOracleDriverName is registered by github.com/sijms/go-ora
+const defaultOracleDriverName = "oracle"
+
+var oracleNumericPlaceholder = regexp.MustCompile(`:(\d+)`)
+
+// NewOracleAdapter requires Oracle 12c or later, GormSettings.DriverName replaces the bundled go-ora driver
+// e.g. godror registered by the application. The tls is configured by the wallet of the connection string.
+// The identifiers are upper cased, so the unquoted names of the raw statements resolve to the same columns
+func NewOracleAdapter(settings GormSettings) *adapter {
+	driverName := settings.DriverName
+
+	if strings.TrimSpace(driverName) == "" {
+		driverName = defaultOracleDriverName
+	}
+
+	return &adapter{
+		open: func(connectionString string, tlsSettings core.TLSSettings) (gorm.Dialector, error) {
+			if tlsSettings.Enabled {
+				return nil, fmt.Errorf("oracle tls is configured by the connection string")
+			}
+
+			return &oracleDialector{driverName: driverName, dsn: connectionString}, nil
+		},
+		settings: settings,
+	}
+}
+
+type oracleDialector struct {
+	driverName string
+	dsn        string
+}
+
+type oracleNamer struct {
+	schema.Namer
+}
+
+func (n oracleNamer) TableName(table string) string {
+	return strings.ToUpper(n.Namer.TableName(table))
+}
+
+func (n oracleNamer) ColumnName(table, column string) string {
+	return strings.ToUpper(n.Namer.ColumnName(table, column))
+}
+
+func (n oracleNamer) JoinTableName(joinTable string) string {
+	return strings.ToUpper(n.Namer.JoinTableName(joinTable))
+}
+
+func (n oracleNamer) RelationshipFKName(relationship schema.Relationship) string {
+	return strings.ToUpper(n.Namer.RelationshipFKName(relationship))
+}
+
+func (n oracleNamer) CheckerName(table, column string) string {
+	return strings.ToUpper(n.Namer.CheckerName(table, column))
+}
+
+func (n oracleNamer) IndexName(table, column string) string {
+	return strings.ToUpper(n.Namer.IndexName(table, column))
+}
+
+func (d *oracleDialector) Name() string {
+	return "oracle"
+}
+
+func (d *oracleDialector) Initialize(db *gorm.DB) (err error) {
+	db.NamingStrategy = oracleNamer{Namer: db.NamingStrategy}
+
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{})
+
+	if err := db.Callback().Create().Replace("gorm:create", oracleCreate); err != nil {
+		return err
+	}
+
+	if db.ConnPool, err = sql.Open(d.driverName, d.dsn); err != nil {
+		return err
+	}
+
+	db.ClauseBuilders[clause.Limit{}.Name()] = oracleLimit
+
+	return nil
+}
+
+func (d *oracleDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return oracleMigrator{Migrator: migrator.Migrator{Config: migrator.Config{
+		DB:                          db,
+		Dialector:                   d,
+		CreateIndexAfterCreateTable: true,
+	}}}
+}
+
+func (d *oracleDialector) DataTypeOf(field *schema.Field) string {
+	// uuid is a string by its driver value
+	if field.FieldType == reflect.TypeOf(uuid.UUID{}) {
+		return "RAW(16)"
+	}
+
+	switch field.DataType {
+	case schema.Bool:
+		return "NUMBER(1)"
+	case schema.Int, schema.Uint:
+		var number string
+
+		switch {
+		case field.Size <= 8:
+			number = "NUMBER(3)"
+		case field.Size <= 16:
+			number = "NUMBER(5)"
+		case field.Size <= 32:
+			number = "NUMBER(10)"
+		default:
+			number = "NUMBER(19)"
+		}
+
+		if field.AutoIncrement {
+			number += " GENERATED BY DEFAULT AS IDENTITY"
+		}
+
+		return number
+	case schema.Float:
+		if field.Size <= 32 {
+			return "BINARY_FLOAT"
+		}
+
+		return "BINARY_DOUBLE"
+	case schema.String:
+		size := field.Size
+
+		if size <= 0 {
+			size = 4000
+
+			if field.PrimaryKey || field.HasDefaultValue {
+				size = 255
+			}
+		}
+
+		if size > 4000 {
+			return "CLOB"
+		}
+
+		return fmt.Sprintf("VARCHAR2(%d)", size)
+	case schema.Time:
+		return "TIMESTAMP WITH TIME ZONE"
+	case schema.Bytes:
+		if field.Size > 0 && field.Size <= 2000 {
+			return fmt.Sprintf("RAW(%d)", field.Size)
+		}
+
+		return "BLOB"
+	}
+
+	return string(field.DataType)
+}
+
+func (d *oracleDialector) DefaultValueOf(field *schema.Field) clause.Expression {
+	return clause.Expr{SQL: "DEFAULT"}
+}
+
+// BindVarTo binds the uuid as RAW(16) and the bool as NUMBER(1)
+func (d *oracleDialector) BindVarTo(writer clause.Writer, stmt *gorm.Statement, v interface{}) {
+	switch value := v.(type) {
+	case uuid.UUID:
+		id := value
+		stmt.Vars[len(stmt.Vars)-1] = id[:]
+	case bool:
+		number := 0
+
+		if value {
+			number = 1
+		}
+
+		stmt.Vars[len(stmt.Vars)-1] = number
+	}
+
+	writer.WriteByte(':')
+	writer.WriteString(strconv.Itoa(len(stmt.Vars)))
+}
+
+func (d *oracleDialector) QuoteTo(writer clause.Writer, str string) {
+	for i, v := range strings.Split(str, ".") {
+		if i > 0 {
+			writer.WriteByte('.')
+		}
+
+		if v == "*" {
+			writer.WriteString(v)
+
+			continue
+		}
+
+		writer.WriteByte('"')
+		writer.WriteString(strings.ToUpper(strings.Trim(v, `"`)))
+		writer.WriteByte('"')
+	}
+}
+
+func (d *oracleDialector) Explain(sql string, vars ...interface{}) string {
+	return logger.ExplainSQL(sql, oracleNumericPlaceholder, `'`, vars...)
+}
+
+func (d *oracleDialector) SavePoint(tx *gorm.DB, name string) error {
+	return tx.Exec("SAVEPOINT " + name).Error
+}
+
+func (d *oracleDialector) RollbackTo(tx *gorm.DB, name string) error {
+	return tx.Exec("ROLLBACK TO SAVEPOINT " + name).Error
+}
+
+// oracleLimit pages by the row limiting clause of Oracle 12c
+func oracleLimit(c clause.Clause, builder clause.Builder) {
+	limit, ok := c.Expression.(clause.Limit)
+	if !ok {
+		return
+	}
+
+	if limit.Offset > 0 {
+		builder.WriteString("OFFSET ")
+		builder.WriteString(strconv.Itoa(limit.Offset))
+		builder.WriteString(" ROWS")
+	}
+
+	if limit.Limit > 0 {
+		if limit.Offset > 0 {
+			builder.WriteByte(' ')
+		}
+
+		builder.WriteString("FETCH NEXT ")
+		builder.WriteString(strconv.Itoa(limit.Limit))
+		builder.WriteString(" ROWS ONLY")
+	}
+}
+
+// oracleCreate inserts the rows by INSERT ALL, the upserts are merged as Oracle has no ON CONFLICT
+func oracleCreate(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+
+	stmt := db.Statement
+
+	if stmt.Schema != nil && !stmt.Unscoped {
+		for _, c := range stmt.Schema.CreateClauses {
+			stmt.AddClause(c)
+		}
+	}
+
+	if stmt.SQL.Len() == 0 {
+		values := callbacks.ConvertToCreateValues(stmt)
+
+		if db.Error != nil || len(values.Values) == 0 {
+			return
+		}
+
+		if onConflict, ok := stmt.Clauses["ON CONFLICT"].Expression.(clause.OnConflict); ok {
+			oracleMerge(stmt, onConflict, values)
+		} else {
+			oracleInsert(stmt, values)
+		}
+	}
+
+	if db.DryRun || db.Error != nil {
+		return
+	}
+
+	result, err := stmt.ConnPool.ExecContext(stmt.Context, stmt.SQL.String(), stmt.Vars...)
+	if err != nil {
+		db.AddError(err)
+
+		return
+	}
+
+	db.RowsAffected, _ = result.RowsAffected()
+}
+
+func oracleInsert(stmt *gorm.Statement, values clause.Values) {
+	if len(values.Values) > 1 {
+		stmt.WriteString("INSERT ALL")
+	} else {
+		stmt.WriteString("INSERT")
+	}
+
+	for _, row := range values.Values {
+		stmt.WriteString(" INTO ")
+		stmt.WriteQuoted(stmt.Table)
+		stmt.WriteString(" (")
+		writeColumns(stmt, "", values.Columns)
+		stmt.WriteString(") VALUES (")
+		stmt.AddVar(stmt, row...)
+		stmt.WriteByte(')')
+	}
+
+	if len(values.Values) > 1 {
+		stmt.WriteString(" SELECT 1 FROM DUAL")
+	}
+}
+
+// oracleMerge merges the rows selected from DUAL as excluded, the conflict columns default to the primary keys
+func oracleMerge(stmt *gorm.Statement, onConflict clause.OnConflict, values clause.Values) {
+	columns := onConflict.Columns
+
+	if len(columns) == 0 && stmt.Schema != nil {
+		for _, v := range stmt.Schema.PrimaryFieldDBNames {
+			columns = append(columns, clause.Column{Name: v})
+		}
+	}
+
+	stmt.WriteString("MERGE INTO ")
+	stmt.WriteQuoted(stmt.Table)
+	stmt.WriteString(" USING (")
+
+	for i, row := range values.Values {
+		if i > 0 {
+			stmt.WriteString(" UNION ALL ")
+		}
+
+		stmt.WriteString("SELECT ")
+
+		for j, v := range row {
+			if j > 0 {
+				stmt.WriteString(", ")
+			}
+
+			stmt.AddVar(stmt, v)
+			stmt.WriteString(" AS ")
+			stmt.WriteQuoted(values.Columns[j].Name)
+		}
+
+		stmt.WriteString(" FROM DUAL")
+	}
+
+	stmt.WriteString(") ")
+	stmt.WriteQuoted("excluded")
+	stmt.WriteString(" ON (")
+
+	for i, v := range columns {
+		if i > 0 {
+			stmt.WriteString(" AND ")
+		}
+
+		stmt.WriteQuoted(clause.Column{Table: stmt.Table, Name: v.Name})
+		stmt.WriteString(" = ")
+		stmt.WriteQuoted(clause.Column{Table: "excluded", Name: v.Name})
+	}
+
+	stmt.WriteByte(')')
+
+	updates := onConflict.DoUpdates
+
+	if onConflict.UpdateAll {
+		conflicts := make(map[string]bool, len(columns))
+
+		for _, v := range columns {
+			conflicts[v.Name] = true
+		}
+
+		names := make([]string, 0, len(values.Columns))
+
+		for _, v := range values.Columns {
+			if !conflicts[v.Name] {
+				names = append(names, v.Name)
+			}
+		}
+
+		updates = clause.AssignmentColumns(names)
+	}
+
+	if !onConflict.DoNothing && len(updates) > 0 {
+		stmt.WriteString(" WHEN MATCHED THEN UPDATE SET ")
+		updates.Build(stmt)
+
+		if len(onConflict.Where.Exprs) > 0 {
+			stmt.WriteString(" WHERE ")
+			onConflict.Where.Build(stmt)
+		}
+	}
+
+	stmt.WriteString(" WHEN NOT MATCHED THEN INSERT (")
+	writeColumns(stmt, "", values.Columns)
+	stmt.WriteString(") VALUES (")
+	writeColumns(stmt, "excluded", values.Columns)
+	stmt.WriteByte(')')
+}
+
+func writeColumns(stmt *gorm.Statement, table string, columns []clause.Column) {
+	for i, v := range columns {
+		if i > 0 {
+			stmt.WriteByte(',')
+		}
+
+		if table == "" {
+			stmt.WriteQuoted(v.Name)
+		} else {
+			stmt.WriteQuoted(clause.Column{Table: table, Name: v.Name})
+		}
+	}
+}
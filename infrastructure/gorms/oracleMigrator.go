@@ -0,0 +1,142 @@
+package gorms
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+// oracleMigrator looks up the schema of the current user, the names are upper cased by the dialector
+type oracleMigrator struct {
+	migrator.Migrator
+}
+
+func (m oracleMigrator) CurrentDatabase() (name string) {
+	m.DB.Raw("SELECT SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA') FROM DUAL").Row().Scan(&name)
+
+	return
+}
+
+func (m oracleMigrator) HasTable(value interface{}) bool {
+	var count int64
+
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.DB.Raw("SELECT COUNT(*) FROM USER_TABLES WHERE TABLE_NAME = ?", strings.ToUpper(stmt.Table)).Row().Scan(&count)
+	})
+
+	return count > 0
+}
+
+func (m oracleMigrator) DropTable(values ...interface{}) error {
+	values = m.ReorderModels(values, false)
+
+	for i := len(values) - 1; i >= 0; i-- {
+		if !m.HasTable(values[i]) {
+			continue
+		}
+
+		err := m.RunWithValue(values[i], func(stmt *gorm.Statement) error {
+			return m.DB.Exec("DROP TABLE ? CASCADE CONSTRAINTS", m.CurrentTable(stmt)).Error
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m oracleMigrator) HasColumn(value interface{}, field string) bool {
+	var count int64
+
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		name := field
+
+		if field := stmt.Schema.LookUpField(field); field != nil {
+			name = field.DBName
+		}
+
+		return m.DB.Raw(
+			"SELECT COUNT(*) FROM USER_TAB_COLUMNS WHERE TABLE_NAME = ? AND COLUMN_NAME = ?",
+			strings.ToUpper(stmt.Table), strings.ToUpper(name),
+		).Row().Scan(&count)
+	})
+
+	return count > 0
+}
+
+func (m oracleMigrator) AlterColumn(value interface{}, field string) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if field := stmt.Schema.LookUpField(field); field != nil {
+			return m.DB.Exec(
+				"ALTER TABLE ? MODIFY ? ?",
+				m.CurrentTable(stmt), clause.Column{Name: field.DBName}, clause.Expr{SQL: m.DataTypeOf(field)},
+			).Error
+		}
+
+		return fmt.Errorf("failed to look up field with name: %s", field)
+	})
+}
+
+// MigrateColumn keeps the existing columns, the type names reported by the oracle drivers differ from the declared types.
+// The columns are changed by the versioned migrations
+func (m oracleMigrator) MigrateColumn(value interface{}, field *schema.Field, columnType gorm.ColumnType) error {
+	return nil
+}
+
+func (m oracleMigrator) HasIndex(value interface{}, name string) bool {
+	var count int64
+
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if idx := stmt.Schema.LookIndex(name); idx != nil {
+			name = idx.Name
+		}
+
+		return m.DB.Raw(
+			"SELECT COUNT(*) FROM USER_INDEXES WHERE TABLE_NAME = ? AND INDEX_NAME = ?",
+			strings.ToUpper(stmt.Table), strings.ToUpper(name),
+		).Row().Scan(&count)
+	})
+
+	return count > 0
+}
+
+func (m oracleMigrator) DropIndex(value interface{}, name string) error {
+	return m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		if idx := stmt.Schema.LookIndex(name); idx != nil {
+			name = idx.Name
+		}
+
+		return m.DB.Exec("DROP INDEX ?", clause.Column{Name: name}).Error
+	})
+}
+
+func (m oracleMigrator) RenameIndex(value interface{}, oldName, newName string) error {
+	return m.DB.Exec("ALTER INDEX ? RENAME TO ?", clause.Column{Name: oldName}, clause.Column{Name: newName}).Error
+}
+
+func (m oracleMigrator) HasConstraint(value interface{}, name string) bool {
+	var count int64
+
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		constraint, chk, table := m.GuessConstraintAndTable(stmt, name)
+
+		if constraint != nil {
+			name = constraint.Name
+		} else if chk != nil {
+			name = chk.Name
+		}
+
+		return m.DB.Raw(
+			"SELECT COUNT(*) FROM USER_CONSTRAINTS WHERE TABLE_NAME = ? AND CONSTRAINT_NAME = ?",
+			strings.ToUpper(table), strings.ToUpper(name),
+		).Row().Scan(&count)
+	})
+
+	return count > 0
+}
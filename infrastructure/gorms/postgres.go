@@ -1,15 +1,44 @@
 package gorms
 
 import (
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/stdlib"
 	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/jybbang/go-core-architecture/core"
 )
 
 func NewPostgresAdapter(settings GormSettings) *adapter {
 	return &adapter{
-		dialector: postgres.New(postgres.Config{
-			DSN:                  settings.ConnectionString,
-			PreferSimpleProtocol: true, // disables implicit prepared statement usage
-		}),
+		open:     openPostgres,
 		settings: settings,
 	}
 }
+
+func openPostgres(connectionString string, tlsSettings core.TLSSettings) (gorm.Dialector, error) {
+	if !tlsSettings.Enabled {
+		return postgres.New(postgres.Config{
+			DSN:                  connectionString,
+			PreferSimpleProtocol: true, // disables implicit prepared statement usage
+		}), nil
+	}
+
+	tlsConfig, err := tlsSettings.ToTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := pgx.ParseConfig(connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	config.TLSConfig = tlsConfig
+	config.Fallbacks = nil
+	config.PreferSimpleProtocol = true
+
+	return postgres.New(postgres.Config{
+		Conn: stdlib.OpenDB(*config),
+	}), nil
+}
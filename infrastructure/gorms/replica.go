@@ -0,0 +1,232 @@
+package gorms
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+type ReplicaPolicy int
+
+const (
+	// ReplicaRoundRobin reads from the healthy replicas in turn
+	ReplicaRoundRobin ReplicaPolicy = iota
+	// ReplicaLeastLatency reads from the healthy replica answering the health check fastest
+	ReplicaLeastLatency
+)
+
+const defaultReplicaHealthCheckInterval = 10 * time.Second
+
+type replica struct {
+	connectionString string
+	// client holds the *clientProxy, it is replaced by the health check reconnecting the replica
+	client  atomic.Value
+	healthy int32
+	// latency is the moving average of the health checks in nanoseconds
+	latency int64
+}
+
+type replicaSet struct {
+	replicas []*replica
+	policy   ReplicaPolicy
+	next     uint32
+	stop     chan struct{}
+	// closed is guarded by the lock of clientsInstance
+	closed bool
+}
+
+// connectReplicas opens the replicas, a replica failing to connect is ejected until it answers the health check
+func (a *adapter) connectReplicas(ctx context.Context) (*replicaSet, error) {
+	if len(a.settings.Replicas) == 0 {
+		return nil, nil
+	}
+
+	set := &replicaSet{
+		replicas: make([]*replica, 0, len(a.settings.Replicas)),
+		policy:   a.settings.ReplicaPolicy,
+		stop:     make(chan struct{}),
+	}
+
+	for _, v := range a.settings.Replicas {
+		set.replicas = append(set.replicas, &replica{connectionString: v})
+	}
+
+	a.checkReplicas(ctx, set)
+
+	interval := a.settings.ReplicaHealthCheckInterval
+
+	if interval <= 0 {
+		interval = defaultReplicaHealthCheckInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-set.stop:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), interval)
+				a.checkReplicas(ctx, set)
+				cancel()
+			}
+		}
+	}()
+
+	return set, nil
+}
+
+func (a *adapter) checkReplicas(ctx context.Context, set *replicaSet) {
+	for _, v := range set.replicas {
+		start := time.Now()
+
+		if err := a.pingReplica(ctx, set, v); err != nil {
+			atomic.StoreInt32(&v.healthy, 0)
+
+			continue
+		}
+
+		elapsed := int64(time.Since(start))
+
+		if latency := atomic.LoadInt64(&v.latency); latency > 0 {
+			elapsed = (latency*3 + elapsed) / 4
+		}
+
+		atomic.StoreInt64(&v.latency, elapsed)
+		atomic.StoreInt32(&v.healthy, 1)
+	}
+}
+
+func (a *adapter) pingReplica(ctx context.Context, set *replicaSet, replica *replica) error {
+	client, err := a.replicaClient(set, replica)
+	if err != nil {
+		return err
+	}
+
+	db, err := client.db.DB()
+	if err != nil {
+		return err
+	}
+
+	return db.PingContext(ctx)
+}
+
+// replicaClient reopens the disconnected client of the replica, the client is shared by the same configuration
+// and the replica is never migrated. The closed set never opens the clients again
+func (a *adapter) replicaClient(set *replicaSet, replica *replica) (*clientProxy, error) {
+	clientsInstance.Lock()
+	defer clientsInstance.Unlock()
+
+	if set.closed {
+		return nil, fmt.Errorf("%w replicas are closed", core.ErrUnavailable)
+	}
+
+	client, _ := replica.client.Load().(*clientProxy)
+
+	if client != nil && client.isConnected {
+		return client, nil
+	}
+
+	opened, err := a.openClient(replica.connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	if client != nil {
+		releaseClient(client)
+	}
+
+	replica.client.Store(opened)
+
+	return opened, nil
+}
+
+// replica picks the healthy replica by the policy, nil reads from the primary
+func (a *adapter) replica(ctx context.Context) *clientProxy {
+	a.RLock()
+	set := a.replicas
+	a.RUnlock()
+
+	if set == nil || core.RequiresPrimary(ctx) {
+		return nil
+	}
+
+	// the tenant database has no replica
+	if tenantId, ok := core.TenantFromContext(ctx); ok {
+		if _, ok := a.settings.TenantConnectionStrings[tenantId]; ok {
+			return nil
+		}
+	}
+
+	var picked *replica
+
+	switch set.policy {
+	case ReplicaLeastLatency:
+		for _, v := range set.replicas {
+			if atomic.LoadInt32(&v.healthy) == 0 {
+				continue
+			}
+
+			if picked == nil || atomic.LoadInt64(&v.latency) < atomic.LoadInt64(&picked.latency) {
+				picked = v
+			}
+		}
+	default:
+		next := atomic.AddUint32(&set.next, 1)
+
+		for i := range set.replicas {
+			v := set.replicas[(int(next)+i)%len(set.replicas)]
+
+			if atomic.LoadInt32(&v.healthy) == 1 {
+				picked = v
+
+				break
+			}
+		}
+	}
+
+	if picked == nil {
+		return nil
+	}
+
+	client, _ := picked.client.Load().(*clientProxy)
+
+	return client
+}
+
+// reader returns the table of the replica or of the primary when no replica is healthy
+func (a *adapter) reader(ctx context.Context) (*gorm.DB, error) {
+	if client := a.replica(ctx); client != nil {
+		return a.scope(ctx, client.db), nil
+	}
+
+	return a.scoped(ctx)
+}
+
+// close stops the health check and releases the clients of the replicas, the caller holds the lock of clientsInstance
+func (s *replicaSet) close() {
+	if s.closed {
+		return
+	}
+
+	s.closed = true
+
+	close(s.stop)
+
+	for _, v := range s.replicas {
+		atomic.StoreInt32(&v.healthy, 0)
+
+		if client, _ := v.client.Load().(*clientProxy); client != nil {
+			releaseClient(client)
+
+			v.client.Store((*clientProxy)(nil))
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package gorms
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// group wraps the expression in parentheses to keep its precedence
+type group struct {
+	expr clause.Expression
+}
+
+func (g group) Build(builder clause.Builder) {
+	builder.WriteByte('(')
+	g.expr.Build(builder)
+	builder.WriteByte(')')
+}
+
+// where applies the query, the specification is translated and the others are passed through
+func (a *adapter) where(db *gorm.DB, query interface{}, args interface{}) (*gorm.DB, error) {
+	spec, ok := query.(*core.Specification)
+	if !ok {
+		return db.Where(query, args), nil
+	}
+
+	expr, err := a.toExpression(db, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.Where(group{expr}), nil
+}
+
+func (a *adapter) toExpression(db *gorm.DB, spec *core.Specification) (clause.Expression, error) {
+	switch spec.Operator {
+	case core.OpAnd, core.OpOr:
+		if len(spec.Specs) == 0 {
+			return nil, fmt.Errorf("%w %s specification is empty", core.ErrBadRequest, spec.Operator)
+		}
+
+		exprs := make([]clause.Expression, 0, len(spec.Specs))
+
+		for _, v := range spec.Specs {
+			expr, err := a.toExpression(db, v)
+			if err != nil {
+				return nil, err
+			}
+
+			exprs = append(exprs, group{expr})
+		}
+
+		if len(exprs) == 1 {
+			return exprs[0], nil
+		}
+
+		if spec.Operator == core.OpAnd {
+			return clause.AndConditions{Exprs: exprs}, nil
+		}
+
+		return clause.OrConditions{Exprs: exprs}, nil
+	case core.OpNot:
+		if len(spec.Specs) != 1 {
+			return nil, fmt.Errorf("%w not specification should have one", core.ErrBadRequest)
+		}
+
+		expr, err := a.toExpression(db, spec.Specs[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return clause.Not(group{expr}), nil
+	}
+
+	column, err := a.column(db, spec.Field)
+	if err != nil {
+		return nil, err
+	}
+
+	col := clause.Column{Name: column}
+
+	switch spec.Operator {
+	case core.OpEq:
+		return clause.Eq{Column: col, Value: spec.Value}, nil
+	case core.OpNe:
+		return clause.Neq{Column: col, Value: spec.Value}, nil
+	case core.OpIn:
+		values := reflect.ValueOf(spec.Value)
+
+		if values.Kind() != reflect.Slice && values.Kind() != reflect.Array {
+			return nil, fmt.Errorf("%w in specification should have slice", core.ErrBadRequest)
+		}
+
+		in := clause.IN{Column: col, Values: make([]interface{}, values.Len())}
+
+		for i := 0; i < values.Len(); i++ {
+			in.Values[i] = values.Index(i).Interface()
+		}
+
+		return in, nil
+	case core.OpGt:
+		return clause.Gt{Column: col, Value: spec.Value}, nil
+	case core.OpLt:
+		return clause.Lt{Column: col, Value: spec.Value}, nil
+	case core.OpLike:
+		return clause.Like{Column: col, Value: spec.Value}, nil
+	default:
+		return nil, fmt.Errorf("%w unknown operator %s", core.ErrBadRequest, spec.Operator)
+	}
+}
+
+// column resolves the Go field name to its column name
+func (a *adapter) column(db *gorm.DB, field string) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+
+	if err := stmt.Parse(a.model); err != nil {
+		return "", err
+	}
+
+	// embedded fields are flattened in the schema
+	names := strings.Split(field, ".")
+
+	if f := stmt.Schema.LookUpField(names[len(names)-1]); f != nil && f.DBName != "" {
+		return f.DBName, nil
+	}
+
+	return "", fmt.Errorf("%w unknown field %s", core.ErrBadRequest, field)
+}
@@ -1,12 +1,25 @@
 package gorms
 
 import (
+	"fmt"
+
 	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/jybbang/go-core-architecture/core"
 )
 
 func NewSqliteAdapter(settings GormSettings) *adapter {
 	return &adapter{
-		dialector: sqlite.Open(settings.ConnectionString),
-		settings:  settings,
+		open:     openSqlite,
+		settings: settings,
+	}
+}
+
+func openSqlite(connectionString string, tlsSettings core.TLSSettings) (gorm.Dialector, error) {
+	if tlsSettings.Enabled {
+		return nil, fmt.Errorf("sqlite does not support tls")
 	}
+
+	return sqlite.Open(connectionString), nil
 }
@@ -1,12 +1,70 @@
 package gorms
 
 import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
 	"gorm.io/driver/sqlserver"
+	"gorm.io/gorm"
+
+	"github.com/jybbang/go-core-architecture/core"
 )
 
 func NewSqlServerAdapter(settings GormSettings) *adapter {
 	return &adapter{
-		dialector: sqlserver.Open(settings.ConnectionString),
-		settings:  settings,
+		open:     openSqlServer,
+		settings: settings,
+	}
+}
+
+// openSqlServer passes the tls settings as the connection string parameters,
+// the driver does not support the client certificates
+func openSqlServer(connectionString string, tlsSettings core.TLSSettings) (gorm.Dialector, error) {
+	if !tlsSettings.Enabled {
+		return sqlserver.Open(connectionString), nil
+	}
+
+	if tlsSettings.CertFile != "" || tlsSettings.KeyFile != "" {
+		return nil, fmt.Errorf("sqlserver does not support the client certificates")
+	}
+
+	params := [][2]string{
+		{"encrypt", "true"},
+		{"TrustServerCertificate", strconv.FormatBool(tlsSettings.InsecureSkipVerify)},
 	}
+
+	if tlsSettings.CAFile != "" {
+		params = append(params, [2]string{"certificate", tlsSettings.CAFile})
+	}
+
+	if tlsSettings.ServerName != "" {
+		params = append(params, [2]string{"hostNameInCertificate", tlsSettings.ServerName})
+	}
+
+	if strings.HasPrefix(connectionString, "sqlserver://") {
+		u, err := url.Parse(connectionString)
+		if err != nil {
+			return nil, err
+		}
+
+		query := u.Query()
+
+		for _, v := range params {
+			query.Set(v[0], v[1])
+		}
+
+		u.RawQuery = query.Encode()
+
+		return sqlserver.Open(u.String()), nil
+	}
+
+	dsn := strings.TrimSuffix(connectionString, ";")
+
+	for _, v := range params {
+		dsn += ";" + v[0] + "=" + v[1]
+	}
+
+	return sqlserver.Open(dsn), nil
 }
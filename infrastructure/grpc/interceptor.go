@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type incomingKey struct{}
+
+// UnaryServerInterceptor propagates the incoming metadata into ctx
+// and converts the core errors to gRPC status errors
+func UnaryServerInterceptor(settings ServiceSettings) grpc.UnaryServerInterceptor {
+	userIdKey := settings.UserIdKey
+
+	if userIdKey == "" {
+		userIdKey = newServiceSettings().UserIdKey
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := incomingContext(ctx, userIdKey, info.FullMethod)
+		defer span.Finish()
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+
+		return resp, nil
+	}
+}
+
+func incomingContext(ctx context.Context, userIdKey string, operationName string) (context.Context, opentracing.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	key := http.CanonicalHeaderKey(userIdKey)
+
+	// the user id metadata is supplied by the client, it is not trusted when a verifier is configured
+	if _, ok := core.UserIdFromContext(ctx); !ok && !core.HasVerifier(ctx) {
+		if values := md.Get(key); len(values) > 0 && values[0] != "" {
+			ctx = core.WithUserId(ctx, values[0])
+		}
+	}
+
+	tracer := opentracing.GlobalTracer()
+	opts := make([]opentracing.StartSpanOption, 0)
+
+	if parent := opentracing.SpanFromContext(ctx); parent != nil {
+		opts = append(opts, opentracing.ChildOf(parent.Context()))
+	} else {
+		carrier := make(opentracing.TextMapCarrier)
+
+		for k, v := range md {
+			if len(v) > 0 {
+				carrier[k] = v[0]
+			}
+		}
+
+		if spanContext, err := tracer.Extract(opentracing.TextMap, carrier); err == nil {
+			opts = append(opts, opentracing.ChildOf(spanContext))
+		}
+	}
+
+	span := tracer.StartSpan(operationName, opts...)
+
+	ctx = context.WithValue(ctx, incomingKey{}, true)
+
+	return opentracing.ContextWithSpan(ctx, span), span
+}
+
+func isIncoming(ctx context.Context) bool {
+	incoming, _ := ctx.Value(incomingKey{}).(bool)
+
+	return incoming
+}
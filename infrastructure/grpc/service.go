@@ -0,0 +1,200 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/jeevatkm/go-model.v1"
+)
+
+// RequestMapper maps the incoming protobuf message to the mediator request
+type RequestMapper func(ctx context.Context, in proto.Message) (core.Request, error)
+
+type service struct {
+	name     string
+	methods  map[string]*method
+	settings ServiceSettings
+	sync.RWMutex
+}
+
+type method struct {
+	in     proto.Message
+	out    proto.Message
+	mapper RequestMapper
+}
+
+type ServiceSettings struct {
+	UserIdKey string `model:",omitempty"`
+}
+
+func newServiceSettings() ServiceSettings {
+	return ServiceSettings{
+		UserIdKey: "Userid",
+	}
+}
+
+// NewService returns gRPC service which dispatches unary calls through the mediator
+func NewService(serviceName string) *service {
+	if strings.TrimSpace(serviceName) == "" {
+		panic("service name is required")
+	}
+
+	return &service{
+		name:     serviceName,
+		methods:  make(map[string]*method),
+		settings: newServiceSettings(),
+	}
+}
+
+func NewServiceWithSettings(serviceName string, settings ServiceSettings) *service {
+	s := NewService(serviceName)
+
+	err := model.Copy(&s.settings, settings)
+
+	if err != nil {
+		panic(fmt.Errorf("settings mapping errors occurred: %v", err))
+	}
+
+	return s
+}
+
+// Handle sends the incoming message itself as the mediator request, out is the declared response message
+func (s *service) Handle(methodName string, in proto.Message, out proto.Message) *service {
+	return s.HandleMapped(methodName, in, out, func(ctx context.Context, in proto.Message) (core.Request, error) {
+		return in, nil
+	})
+}
+
+// HandleMapped sends the request which is mapped from the incoming message,
+// the nil result is answered by the empty out message
+func (s *service) HandleMapped(methodName string, in proto.Message, out proto.Message, mapper RequestMapper) *service {
+	if strings.TrimSpace(methodName) == "" {
+		panic("method name is required")
+	}
+
+	if in == nil {
+		panic("in message is required")
+	}
+
+	if out == nil {
+		panic("out message is required")
+	}
+
+	if mapper == nil {
+		panic("mapper is required")
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.methods[methodName] = &method{
+		in:     in,
+		out:    out,
+		mapper: mapper,
+	}
+
+	return s
+}
+
+// Register registers the service to the grpc server
+func (s *service) Register(server *grpc.Server) {
+	s.RLock()
+	defer s.RUnlock()
+
+	desc := &grpc.ServiceDesc{
+		ServiceName: s.name,
+		HandlerType: (*interface{})(nil),
+		Methods:     make([]grpc.MethodDesc, 0, len(s.methods)),
+		Streams:     []grpc.StreamDesc{},
+	}
+
+	for k, v := range s.methods {
+		desc.Methods = append(desc.Methods, grpc.MethodDesc{
+			MethodName: k,
+			Handler:    s.handler(k, v),
+		})
+	}
+
+	server.RegisterService(desc, s)
+}
+
+func (s *service) handler(methodName string, m *method) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := m.in.ProtoReflect().New().Interface()
+
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+
+		info := &grpc.UnaryServerInfo{
+			Server:     srv,
+			FullMethod: fmt.Sprintf("/%s/%s", s.name, methodName),
+		}
+
+		handle := func(ctx context.Context, req interface{}) (interface{}, error) {
+			// UnaryServerInterceptor has already propagated the metadata and started the span
+			if isIncoming(ctx) {
+				return s.send(ctx, m, req.(proto.Message))
+			}
+
+			ctx, span := incomingContext(ctx, s.settings.UserIdKey, info.FullMethod)
+			defer span.Finish()
+
+			return s.send(ctx, m, req.(proto.Message))
+		}
+
+		if interceptor == nil {
+			return handle(ctx, in)
+		}
+
+		return interceptor(ctx, in, info, handle)
+	}
+}
+
+func (s *service) send(ctx context.Context, m *method, in proto.Message) (interface{}, error) {
+	request, err := m.mapper(ctx, in)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	mediator := core.GetMediator()
+
+	// grpc does not recover the panic of Send when the handler is not added
+	if !mediator.HasRequestHandler(request) {
+		return nil, status.Errorf(codes.Unimplemented, "handler of %T is not added", request)
+	}
+
+	result := mediator.Send(ctx, request)
+
+	if result.E != nil {
+		return nil, result.ToGrpcStatus().Err()
+	}
+
+	if result.V == nil {
+		return m.out.ProtoReflect().New().Interface(), nil
+	}
+
+	out, ok := result.V.(proto.Message)
+	if !ok || out.ProtoReflect().Descriptor().FullName() != m.out.ProtoReflect().Descriptor().FullName() {
+		return nil, status.Errorf(codes.Internal, "result should be %T, got %T", m.out, result.V)
+	}
+
+	return out, nil
+}
+
+func toStatusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	result := core.Result{E: err}
+
+	return result.ToGrpcStatus().Err()
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+// bind decodes the json body first, then path and query values take precedence
+func (r *router) bind(w http.ResponseWriter, req *http.Request, params map[string]string, request interface{}) error {
+	if req.Body != nil && req.Body != http.NoBody {
+		body := http.MaxBytesReader(w, req.Body, r.settings.MaxBodySize)
+
+		if err := json.NewDecoder(body).Decode(request); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("%w invalid body: %v", core.ErrBadRequest, err)
+		}
+	}
+
+	query := req.URL.Query()
+
+	value := reflect.ValueOf(request).Elem()
+	typeOf := value.Type()
+
+	for i := 0; i < typeOf.NumField(); i++ {
+		field := typeOf.Field(i)
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name, ok := field.Tag.Lookup("path"); ok {
+			if v, ok := params[name]; ok {
+				if err := setField(value.Field(i), v); err != nil {
+					return fmt.Errorf("%w invalid path param %s: %v", core.ErrBadRequest, name, err)
+				}
+			}
+		}
+
+		if name, ok := field.Tag.Lookup("query"); ok {
+			if v, ok := query[name]; ok && len(v) > 0 {
+				if err := setField(value.Field(i), v...); err != nil {
+					return fmt.Errorf("%w invalid query param %s: %v", core.ErrBadRequest, name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func setField(field reflect.Value, values ...string) error {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+
+		return setField(field.Elem(), values...)
+	}
+
+	if unmarshaler, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(values[0]))
+	}
+
+	if field.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(field.Type(), len(values), len(values))
+
+		for i, v := range values {
+			if err := setField(slice.Index(i), v); err != nil {
+				return err
+			}
+		}
+
+		field.Set(slice)
+
+		return nil
+	}
+
+	value := values[0]
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(v)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"gopkg.in/jeevatkm/go-model.v1"
+)
+
+type router struct {
+	routes   []*route
+	settings RouterSettings
+	sync.RWMutex
+}
+
+type route struct {
+	method      string
+	segments    []string
+	requestType reflect.Type
+}
+
+type RouterSettings struct {
+	UserIdHeader string `model:",omitempty"`
+	MaxBodySize  int64  `model:",omitempty"`
+}
+
+func newRouterSettings() RouterSettings {
+	return RouterSettings{
+		UserIdHeader: "Userid",
+		MaxBodySize:  1 << 20,
+	}
+}
+
+// NewRouter returns http.Handler which dispatches the bound requests through the mediator
+func NewRouter() *router {
+	return &router{
+		routes:   make([]*route, 0),
+		settings: newRouterSettings(),
+	}
+}
+
+func NewRouterWithSettings(settings RouterSettings) *router {
+	r := NewRouter()
+
+	err := model.Copy(&r.settings, settings)
+
+	if err != nil {
+		panic(fmt.Errorf("settings mapping errors occurred: %v", err))
+	}
+
+	return r
+}
+
+// Handle binds the method and the pattern to the request type,
+// pattern segments like {id} are bound to the fields tagged with `path:"id"`
+func (r *router) Handle(method string, pattern string, request core.Request) *router {
+	if strings.TrimSpace(method) == "" {
+		panic("method is required")
+	}
+
+	if request == nil {
+		panic("request is required")
+	}
+
+	typeOf := reflect.TypeOf(request)
+
+	if typeOf.Kind() != reflect.Ptr || typeOf.Elem().Kind() != reflect.Struct {
+		panic("request should be a pointer to struct")
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	r.routes = append(r.routes, &route{
+		method:      strings.ToUpper(method),
+		segments:    splitPath(pattern),
+		requestType: typeOf.Elem(),
+	})
+
+	return r
+}
+
+func (r *router) Get(pattern string, request core.Request) *router {
+	return r.Handle(http.MethodGet, pattern, request)
+}
+
+func (r *router) Post(pattern string, request core.Request) *router {
+	return r.Handle(http.MethodPost, pattern, request)
+}
+
+func (r *router) Put(pattern string, request core.Request) *router {
+	return r.Handle(http.MethodPut, pattern, request)
+}
+
+func (r *router) Patch(pattern string, request core.Request) *router {
+	return r.Handle(http.MethodPatch, pattern, request)
+}
+
+func (r *router) Delete(pattern string, request core.Request) *router {
+	return r.Handle(http.MethodDelete, pattern, request)
+}
+
+func (r *router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	matched, params, allowed := r.match(req.Method, req.URL.Path)
+
+	if matched == nil {
+		if len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			writeProblem(w, req, http.StatusMethodNotAllowed)
+			return
+		}
+
+		result := core.Result{E: fmt.Errorf("%w route %s", core.ErrNotFound, req.URL.Path)}
+		result.WriteProblem(w, req.URL.Path)
+		return
+	}
+
+	request := reflect.New(matched.requestType).Interface()
+
+	mediator := core.GetMediator()
+
+	// the route is bound before its handler is added
+	if !mediator.HasRequestHandler(request) {
+		writeProblem(w, req, http.StatusNotImplemented)
+		return
+	}
+
+	if err := r.bind(w, req, params, request); err != nil {
+		result := core.Result{E: err}
+		result.WriteProblem(w, req.URL.Path)
+		return
+	}
+
+	result := mediator.Send(r.withUserId(req), request)
+
+	writeResult(w, req, result)
+}
+
+func (r *router) match(method string, path string) (*route, map[string]string, []string) {
+	r.RLock()
+	defer r.RUnlock()
+
+	segments := splitPath(path)
+	allowed := make([]string, 0)
+
+	for _, v := range r.routes {
+		params, ok := v.match(segments)
+		if !ok {
+			continue
+		}
+
+		if v.method == method {
+			return v, params, nil
+		}
+
+		allowed = append(allowed, v.method)
+	}
+
+	return nil, nil, allowed
+}
+
+func (r *route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+
+	for i, v := range r.segments {
+		if strings.HasPrefix(v, "{") && strings.HasSuffix(v, "}") {
+			params[v[1:len(v)-1]] = segments[i]
+			continue
+		}
+
+		if v != segments[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+// withUserId trusts the user id header only when no verifier is configured
+func (r *router) withUserId(req *http.Request) context.Context {
+	ctx := req.Context()
+
+	if core.HasVerifier(ctx) {
+		return ctx
+	}
+
+	if userId := req.Header.Get(r.settings.UserIdHeader); userId != "" {
+		ctx = core.WithUserId(ctx, userId)
+	}
+
+	return ctx
+}
+
+func splitPath(path string) []string {
+	segments := make([]string, 0)
+
+	for _, v := range strings.Split(path, "/") {
+		if v != "" {
+			segments = append(segments, v)
+		}
+	}
+
+	return segments
+}
+
+func writeResult(w http.ResponseWriter, req *http.Request, result core.Result) {
+	if result.E != nil {
+		result.WriteProblem(w, req.URL.Path)
+		return
+	}
+
+	statusCode := result.ToHttpStatus()
+
+	if statusCode == http.StatusNoContent {
+		w.WriteHeader(statusCode)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	json.NewEncoder(w).Encode(result.V)
+}
+
+func writeProblem(w http.ResponseWriter, req *http.Request, statusCode int) {
+	problem := &core.ProblemDetails{
+		Type:     "about:blank",
+		Title:    http.StatusText(statusCode),
+		Status:   statusCode,
+		Instance: req.URL.Path,
+	}
+
+	w.Header().Set("Content-Type", core.ProblemContentType)
+	w.WriteHeader(statusCode)
+
+	json.NewEncoder(w).Encode(problem)
+}
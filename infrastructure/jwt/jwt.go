@@ -0,0 +1,231 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"gopkg.in/jeevatkm/go-model.v1"
+)
+
+type verifier struct {
+	settings JwtSettings
+}
+
+type JwtSettings struct {
+	// Secret verifies HS256, HS384 and HS512 tokens
+	Secret []byte
+	// PublicKey verifies RS256, RS384 and RS512 tokens
+	PublicKey   *rsa.PublicKey
+	Issuer      string        `model:",omitempty"`
+	Audience    string        `model:",omitempty"`
+	Leeway      time.Duration `model:",omitempty"`
+	UserIdClaim string        `model:",omitempty"`
+	RolesClaim  string        `model:",omitempty"`
+	// checks exp and nbf without the leeway, Leeway zero is taken as the default
+	NoLeeway bool
+	// accepts the tokens without exp which never expire
+	AllowMissingExpiration bool
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+var hashes = map[string]crypto.Hash{
+	"256": crypto.SHA256,
+	"384": crypto.SHA384,
+	"512": crypto.SHA512,
+}
+
+func newJwtSettings() JwtSettings {
+	return JwtSettings{
+		Leeway:      time.Duration(1 * time.Minute),
+		UserIdClaim: "sub",
+		RolesClaim:  "roles",
+	}
+}
+
+func NewVerifier(settings JwtSettings) *verifier {
+	if len(settings.Secret) == 0 && settings.PublicKey == nil {
+		panic("secret or public key is required")
+	}
+
+	v := &verifier{
+		settings: newJwtSettings(),
+	}
+
+	err := model.Copy(&v.settings, settings)
+
+	if err != nil {
+		panic(fmt.Errorf("settings mapping errors occurred: %v", err))
+	}
+
+	v.settings.Secret = settings.Secret
+	v.settings.PublicKey = settings.PublicKey
+
+	if v.settings.NoLeeway {
+		v.settings.Leeway = 0
+	}
+
+	return v
+}
+
+// Verify checks the signature and the registered claims of the token then returns its principal
+func (v *verifier) Verify(token string) (*core.Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w malformed token", core.ErrUnauthorized)
+	}
+
+	h := new(header)
+	if err := decodeSegment(parts[0], h); err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w malformed signature", core.ErrUnauthorized)
+	}
+
+	if err := v.verifySignature(h.Alg, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	claims := make(map[string]interface{})
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+
+	if err := v.verifyClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return v.toPrincipal(claims), nil
+}
+
+func (v *verifier) verifySignature(alg string, signed string, signature []byte) error {
+	if len(alg) != 5 {
+		return fmt.Errorf("%w unsupported algorithm %s", core.ErrUnauthorized, alg)
+	}
+
+	hash, ok := hashes[alg[2:]]
+	if !ok {
+		return fmt.Errorf("%w unsupported algorithm %s", core.ErrUnauthorized, alg)
+	}
+
+	hasher := hash.New()
+
+	switch alg[:2] {
+	case "HS":
+		if len(v.settings.Secret) == 0 {
+			return fmt.Errorf("%w unsupported algorithm %s", core.ErrUnauthorized, alg)
+		}
+
+		mac := hmac.New(hash.New, v.settings.Secret)
+		mac.Write([]byte(signed))
+
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return fmt.Errorf("%w invalid signature", core.ErrUnauthorized)
+		}
+	case "RS":
+		if v.settings.PublicKey == nil {
+			return fmt.Errorf("%w unsupported algorithm %s", core.ErrUnauthorized, alg)
+		}
+
+		hasher.Write([]byte(signed))
+
+		if err := rsa.VerifyPKCS1v15(v.settings.PublicKey, hash, hasher.Sum(nil), signature); err != nil {
+			return fmt.Errorf("%w invalid signature", core.ErrUnauthorized)
+		}
+	default:
+		return fmt.Errorf("%w unsupported algorithm %s", core.ErrUnauthorized, alg)
+	}
+
+	return nil
+}
+
+func (v *verifier) verifyClaims(claims map[string]interface{}) error {
+	now := time.Now()
+
+	if exp, ok := claims["exp"].(float64); ok {
+		if now.Add(-v.settings.Leeway).After(time.Unix(int64(exp), 0)) {
+			return fmt.Errorf("%w token is expired", core.ErrUnauthorized)
+		}
+	} else if !v.settings.AllowMissingExpiration {
+		return fmt.Errorf("%w token has no expiration", core.ErrUnauthorized)
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok {
+		if now.Add(v.settings.Leeway).Before(time.Unix(int64(nbf), 0)) {
+			return fmt.Errorf("%w token is not valid yet", core.ErrUnauthorized)
+		}
+	}
+
+	if v.settings.Issuer != "" && claims["iss"] != v.settings.Issuer {
+		return fmt.Errorf("%w invalid issuer", core.ErrUnauthorized)
+	}
+
+	if v.settings.Audience != "" && !containsClaim(claims["aud"], v.settings.Audience) {
+		return fmt.Errorf("%w invalid audience", core.ErrUnauthorized)
+	}
+
+	return nil
+}
+
+func (v *verifier) toPrincipal(claims map[string]interface{}) *core.Principal {
+	principal := &core.Principal{
+		Roles:  make([]string, 0),
+		Claims: claims,
+	}
+
+	principal.UserId, _ = claims[v.settings.UserIdClaim].(string)
+
+	switch roles := claims[v.settings.RolesClaim].(type) {
+	case string:
+		principal.Roles = append(principal.Roles, strings.Fields(roles)...)
+	case []interface{}:
+		for _, role := range roles {
+			if value, ok := role.(string); ok {
+				principal.Roles = append(principal.Roles, value)
+			}
+		}
+	}
+
+	return principal
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	bytes, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w malformed token", core.ErrUnauthorized)
+	}
+
+	if err := json.Unmarshal(bytes, v); err != nil {
+		return fmt.Errorf("%w malformed token", core.ErrUnauthorized)
+	}
+
+	return nil
+}
+
+func containsClaim(claim interface{}, expect string) bool {
+	switch value := claim.(type) {
+	case string:
+		return value == expect
+	case []interface{}:
+		for _, v := range value {
+			if v == expect {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,74 @@
+package jwt
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// HttpMiddleware puts the principal of the bearer token into ctx,
+// requests without token pass through as anonymous, the client supplied user id is never trusted
+func (v *verifier) HttpMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := core.WithVerifier(req.Context())
+
+		token, ok := bearerToken(req.Header.Get("Authorization"))
+		if !ok {
+			next.ServeHTTP(w, req.WithContext(ctx))
+			return
+		}
+
+		principal, err := v.Verify(token)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+
+			result := core.Result{E: err}
+			result.WriteProblem(w, req.URL.Path)
+			return
+		}
+
+		next.ServeHTTP(w, req.WithContext(core.WithPrincipal(ctx, principal)))
+	})
+}
+
+// UnaryServerInterceptor puts the principal of the bearer token in metadata into ctx,
+// calls without token pass through as anonymous, the client supplied user id is never trusted
+func (v *verifier) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = core.WithVerifier(ctx)
+
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return handler(ctx, req)
+		}
+
+		token, ok := bearerToken(values[0])
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		principal, err := v.Verify(token)
+		if err != nil {
+			result := core.Result{E: err}
+			return nil, result.ToGrpcStatus().Err()
+		}
+
+		return handler(core.WithPrincipal(ctx, principal), req)
+	}
+}
+
+func bearerToken(authorization string) (string, bool) {
+	const prefix = "bearer "
+
+	if len(authorization) <= len(prefix) || !strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return "", false
+	}
+
+	return strings.TrimSpace(authorization[len(prefix):]), true
+}
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/jybbang/go-core-architecture/core"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -21,6 +23,8 @@ type adapter struct {
 type clientProxy struct {
 	leveldb     *leveldb.DB
 	isConnected bool
+	// writes serializes SetIfAbsent with the other writes, the database is opened by a single process
+	writes sync.Mutex
 }
 
 type clients struct {
@@ -33,6 +37,9 @@ type LevelDbSettings struct {
 	ReadOnly bool
 }
 
+// ttlPrefix keeps the expiry of the keys set with ttl, leveldb does not expire the keys by itself
+const ttlPrefix = "\x00ttl:"
+
 var clientsInstance *clients
 
 func init() {
@@ -100,11 +107,17 @@ func (a *adapter) Disconnect() {
 func (a *adapter) Has(ctx context.Context, key string) bool {
 	ok, err := a.client.leveldb.Has([]byte(key), nil)
 
-	if err != nil {
+	if err != nil || !ok {
+		return false
+	}
+
+	if a.expired(key) {
+		a.purge(key)
+
 		return false
 	}
 
-	return ok
+	return true
 }
 
 func (a *adapter) Get(ctx context.Context, key string, dest interface{}) error {
@@ -118,17 +131,64 @@ func (a *adapter) Get(ctx context.Context, key string, dest interface{}) error {
 		return err
 	}
 
+	if a.expired(key) {
+		a.purge(key)
+
+		return core.ErrNotFound
+	}
+
 	return json.Unmarshal(value, dest)
 }
 
 func (a *adapter) Set(ctx context.Context, key string, value interface{}) error {
-	bytes, err := json.Marshal(value)
+	return a.SetWithTTL(ctx, key, value, 0)
+}
+
+func (a *adapter) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	a.client.writes.Lock()
+	defer a.client.writes.Unlock()
+
+	return a.put(key, value, ttl)
+}
+
+func (a *adapter) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	a.client.writes.Lock()
+	defer a.client.writes.Unlock()
+
+	// the expired key is overwritten without purging it
+	if ok, err := a.client.leveldb.Has([]byte(key), nil); err != nil {
+		return false, err
+	} else if ok && !a.expired(key) {
+		return false, nil
+	}
+
+	return true, a.put(key, value, ttl)
+}
+
+// Increment keeps the expiry of the existing counter, the expired counter starts again with ttl
+func (a *adapter) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	a.client.writes.Lock()
+	defer a.client.writes.Unlock()
+
+	value, err := a.client.leveldb.Get([]byte(key), nil)
+
+	if errors.Is(err, leveldb.ErrNotFound) || (err == nil && a.expired(key)) {
+		return delta, a.put(key, delta, ttl)
+	}
 
 	if err != nil {
-		return err
+		return 0, err
+	}
+
+	var count int64
+
+	if err := json.Unmarshal(value, &count); err != nil {
+		return 0, err
 	}
 
-	return a.client.leveldb.Put([]byte(key), bytes, nil)
+	count += delta
+
+	return count, a.client.leveldb.Put([]byte(key), []byte(strconv.FormatInt(count, 10)), nil)
 }
 
 func (a *adapter) BatchSet(ctx context.Context, kvs []core.KV) error {
@@ -142,13 +202,75 @@ func (a *adapter) BatchSet(ctx context.Context, kvs []core.KV) error {
 		}
 
 		batch.Put([]byte(v.K), bytes)
+		batch.Delete([]byte(ttlPrefix + v.K))
 	}
 
+	a.client.writes.Lock()
+	defer a.client.writes.Unlock()
+
 	err := a.client.leveldb.Write(batch, nil)
 
 	return err
 }
 
 func (a *adapter) Delete(ctx context.Context, key string) error {
-	return a.client.leveldb.Delete([]byte(key), nil)
+	batch := new(leveldb.Batch)
+
+	batch.Delete([]byte(key))
+	batch.Delete([]byte(ttlPrefix + key))
+
+	a.client.writes.Lock()
+	defer a.client.writes.Unlock()
+
+	return a.client.leveldb.Write(batch, nil)
+}
+
+// put writes the value and its expiry in a batch, the caller holds the writes lock
+func (a *adapter) put(key string, value interface{}, ttl time.Duration) error {
+	bytes, err := json.Marshal(value)
+
+	if err != nil {
+		return err
+	}
+
+	batch := new(leveldb.Batch)
+	batch.Put([]byte(key), bytes)
+
+	if ttl > 0 {
+		batch.Put([]byte(ttlPrefix+key), []byte(strconv.FormatInt(time.Now().Add(ttl).UnixNano(), 10)))
+	} else {
+		batch.Delete([]byte(ttlPrefix + key))
+	}
+
+	return a.client.leveldb.Write(batch, nil)
+}
+
+// expired reads the expiry of the key, the expired keys are purged lazily when they are read
+func (a *adapter) expired(key string) bool {
+	value, err := a.client.leveldb.Get([]byte(ttlPrefix+key), nil)
+
+	if err != nil {
+		return false
+	}
+
+	expiresAt, err := strconv.ParseInt(string(value), 10, 64)
+
+	return err == nil && time.Now().UnixNano() >= expiresAt
+}
+
+// purge deletes the key unless it is set again after it is expired
+func (a *adapter) purge(key string) {
+	a.client.writes.Lock()
+	defer a.client.writes.Unlock()
+
+	if !a.expired(key) {
+		return
+	}
+
+	batch := new(leveldb.Batch)
+
+	batch.Delete([]byte(key))
+	batch.Delete([]byte(ttlPrefix + key))
+
+	a.client.leveldb.Write(batch, nil)
 }
@@ -0,0 +1,261 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/google/uuid"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+func (a *adapter) Remove(ctx context.Context, id uuid.UUID) error {
+	return a.RemoveRange(ctx, []uuid.UUID{id})
+}
+
+// RemoveRange removes none of them when any entity is not found
+func (a *adapter) RemoveRange(ctx context.Context, ids []uuid.UUID) error {
+	if err := a.ready(ctx, "RemoveRange"); err != nil {
+		return err
+	}
+
+	return a.write(ctx, func(b *batch) error {
+		for _, id := range ids {
+			if _, ok := a.stored(ctx, id); !ok {
+				return notFound(id)
+			}
+
+			b.remove(id)
+		}
+
+		return nil
+	})
+}
+
+func (a *adapter) Add(ctx context.Context, entity core.Entitier) error {
+	return a.AddRange(ctx, []core.Entitier{entity})
+}
+
+// AddRange adds none of them when any entity conflicts
+func (a *adapter) AddRange(ctx context.Context, entities []core.Entitier) error {
+	if err := a.ready(ctx, "AddRange"); err != nil {
+		return err
+	}
+
+	return a.write(ctx, func(b *batch) error {
+		for _, v := range entities {
+			if err := a.typed(v); err != nil {
+				return err
+			}
+
+			if _, ok := a.rows[v.GetID()]; ok {
+				return core.NewError(core.ErrConflict, core.CodeUniqueViolation, fmt.Sprintf("%s already exists", v.GetID()))
+			}
+
+			if err := b.put(v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (a *adapter) Update(ctx context.Context, entity core.Entitier) error {
+	return a.UpdateRange(ctx, []core.Entitier{entity})
+}
+
+// UpdateRange increments the versions of the entities only when all of them are updated
+func (a *adapter) UpdateRange(ctx context.Context, entities []core.Entitier) error {
+	if err := a.ready(ctx, "UpdateRange"); err != nil {
+		return err
+	}
+
+	return a.write(ctx, func(b *batch) error {
+		for _, v := range entities {
+			if err := a.typed(v); err != nil {
+				return err
+			}
+
+			stored, ok := a.stored(ctx, v.GetID())
+			if !ok {
+				return notFound(v.GetID())
+			}
+
+			updated := v
+
+			if versioned, ok := v.(core.Versioned); ok {
+				expected := versioned.GetVersion()
+
+				if stored.(core.Versioned).GetVersion() != expected {
+					return fmt.Errorf("%w version %d is changed", core.ErrConflict, expected)
+				}
+
+				updated = clone(v).(core.Entitier)
+				updated.(core.Versioned).SetVersion(expected + 1)
+			}
+
+			if err := b.put(updated); err != nil {
+				return err
+			}
+		}
+
+		for _, v := range entities {
+			if versioned, ok := v.(core.Versioned); ok {
+				versioned.SetVersion(versioned.GetVersion() + 1)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (a *adapter) Upsert(ctx context.Context, entity core.Entitier) (affected int64, err error) {
+	return a.UpsertRange(ctx, []core.Entitier{entity})
+}
+
+// UpsertRange updates the existing rows except their creation stamp, the version is incremented
+func (a *adapter) UpsertRange(ctx context.Context, entities []core.Entitier) (affected int64, err error) {
+	if err := a.ready(ctx, "UpsertRange"); err != nil {
+		return 0, err
+	}
+
+	err = a.write(ctx, func(b *batch) error {
+		for _, v := range entities {
+			if err := a.typed(v); err != nil {
+				return err
+			}
+
+			upserted := clone(v).(core.Entitier)
+
+			if previous, ok := a.rows[v.GetID()]; ok {
+				stored := reflect.ValueOf(previous.entity).Elem()
+				entityVal := reflect.ValueOf(upserted).Elem()
+
+				for _, name := range []string{"CreateUser", "CreatedAt"} {
+					entityVal.FieldByName(name).Set(stored.FieldByName(name))
+				}
+
+				if versioned, ok := upserted.(core.Versioned); ok {
+					versioned.SetVersion(previous.entity.(core.Versioned).GetVersion() + 1)
+				}
+			}
+
+			if err := b.put(upserted); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(entities)), nil
+}
+
+func (a *adapter) Patch(ctx context.Context, id uuid.UUID, fields map[string]interface{}) (affected int64, err error) {
+	if err := a.ready(ctx, "Patch"); err != nil {
+		return 0, err
+	}
+
+	err = a.write(ctx, func(b *batch) error {
+		stored, ok := a.stored(ctx, id)
+		if !ok {
+			return nil
+		}
+
+		affected++
+
+		return b.patch(stored, fields)
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+func (a *adapter) UpdateWhere(ctx context.Context, query interface{}, fields map[string]interface{}) (affected int64, err error) {
+	if err := a.ready(ctx, "UpdateWhere"); err != nil {
+		return 0, err
+	}
+
+	spec, err := core.SpecificationOf(query)
+	if err != nil {
+		return 0, err
+	}
+
+	err = a.write(ctx, func(b *batch) error {
+		for _, v := range a.rows {
+			if !core.InScope(ctx, v.entity) || (spec != nil && !spec.IsSatisfiedBy(v.entity)) {
+				continue
+			}
+
+			affected++
+
+			if err := b.patch(v.entity, fields); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+func (a *adapter) RemoveWhere(ctx context.Context, query interface{}) (affected int64, err error) {
+	if err := a.ready(ctx, "RemoveWhere"); err != nil {
+		return 0, err
+	}
+
+	spec, err := core.SpecificationOf(query)
+	if err != nil {
+		return 0, err
+	}
+
+	err = a.write(ctx, func(b *batch) error {
+		for id, v := range a.rows {
+			if !core.InScope(ctx, v.entity) || (spec != nil && !spec.IsSatisfiedBy(v.entity)) {
+				continue
+			}
+
+			affected++
+
+			b.remove(id)
+		}
+
+		return nil
+	})
+
+	return affected, err
+}
+
+// typed rejects the entities of the other models, the stored rows are copied into the dest of the model type
+func (a *adapter) typed(entity core.Entitier) error {
+	if reflect.TypeOf(entity) != reflect.TypeOf(a.model) {
+		return fmt.Errorf("%w entity %T is not %T", core.ErrInternalServerError, entity, a.model)
+	}
+
+	return nil
+}
+
+// patch stores the patched copy of the stored entity
+func (b *batch) patch(stored interface{}, fields map[string]interface{}) error {
+	patched := clone(stored)
+
+	if err := patch(patched, fields); err != nil {
+		return err
+	}
+
+	return b.put(patched.(core.Entitier))
+}
@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+// deepCopy copies the exported fields recursively, the unexported fields like the wall clock of time.Time are copied by value
+func deepCopy(src reflect.Value) reflect.Value {
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return reflect.Zero(src.Type())
+		}
+
+		dst := reflect.New(src.Type().Elem())
+		dst.Elem().Set(deepCopy(src.Elem()))
+
+		return dst
+	case reflect.Interface:
+		if src.IsNil() {
+			return reflect.Zero(src.Type())
+		}
+
+		dst := reflect.New(src.Type()).Elem()
+		dst.Set(deepCopy(src.Elem()))
+
+		return dst
+	case reflect.Struct:
+		dst := reflect.New(src.Type()).Elem()
+		dst.Set(src)
+
+		for i := 0; i < src.NumField(); i++ {
+			if field := dst.Field(i); field.CanSet() {
+				field.Set(deepCopy(src.Field(i)))
+			}
+		}
+
+		return dst
+	case reflect.Slice:
+		if src.IsNil() {
+			return reflect.Zero(src.Type())
+		}
+
+		dst := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+
+		for i := 0; i < src.Len(); i++ {
+			dst.Index(i).Set(deepCopy(src.Index(i)))
+		}
+
+		return dst
+	case reflect.Array:
+		dst := reflect.New(src.Type()).Elem()
+
+		for i := 0; i < src.Len(); i++ {
+			dst.Index(i).Set(deepCopy(src.Index(i)))
+		}
+
+		return dst
+	case reflect.Map:
+		if src.IsNil() {
+			return reflect.Zero(src.Type())
+		}
+
+		dst := reflect.MakeMapWithSize(src.Type(), src.Len())
+
+		for iter := src.MapRange(); iter.Next(); {
+			dst.SetMapIndex(deepCopy(iter.Key()), deepCopy(iter.Value()))
+		}
+
+		return dst
+	default:
+		return src
+	}
+}
+
+func clone(entity interface{}) interface{} {
+	return deepCopy(reflect.ValueOf(entity)).Interface()
+}
+
+// copyTo copies the stored entity into dest, dest must be a pointer of the model type
+func copyTo(dest interface{}, entity interface{}) error {
+	destVal := reflect.ValueOf(dest)
+
+	if destVal.Kind() != reflect.Ptr || destVal.IsNil() || destVal.Type() != reflect.TypeOf(entity) {
+		return fmt.Errorf("%w dest %T is not %T", core.ErrInternalServerError, dest, entity)
+	}
+
+	destVal.Elem().Set(deepCopy(reflect.ValueOf(entity).Elem()))
+
+	return nil
+}
+
+// appendTo appends the copies to the slice of dest, the elements may be pointers or values of the model
+func appendTo(dest interface{}, entities []interface{}, fields []string) error {
+	resultsVal := reflect.ValueOf(dest)
+
+	if resultsVal.Kind() != reflect.Ptr || resultsVal.IsNil() {
+		return fmt.Errorf("%w dest %T is not a pointer of slice", core.ErrInternalServerError, dest)
+	}
+
+	sliceVal := resultsVal.Elem()
+
+	if sliceVal.Kind() == reflect.Interface {
+		sliceVal = sliceVal.Elem()
+	}
+
+	if sliceVal.Kind() != reflect.Slice {
+		return fmt.Errorf("%w dest %T is not a pointer of slice", core.ErrInternalServerError, dest)
+	}
+
+	elemType := sliceVal.Type().Elem()
+
+	for _, v := range entities {
+		entityVal := project(v, fields)
+
+		switch {
+		case entityVal.Type().AssignableTo(elemType):
+			sliceVal = reflect.Append(sliceVal, entityVal)
+		case entityVal.Elem().Type().AssignableTo(elemType):
+			sliceVal = reflect.Append(sliceVal, entityVal.Elem())
+		default:
+			return fmt.Errorf("%w dest %T can not hold %T", core.ErrInternalServerError, dest, v)
+		}
+	}
+
+	resultsVal.Elem().Set(sliceVal)
+
+	return nil
+}
+
+// project copies the entity, only the fields are copied when they are given
+func project(entity interface{}, fields []string) reflect.Value {
+	entityVal := reflect.ValueOf(entity)
+
+	if len(fields) == 0 {
+		return deepCopy(entityVal)
+	}
+
+	projected := reflect.New(entityVal.Elem().Type())
+
+	for _, v := range fields {
+		src := entityVal.Elem()
+		dst := projected.Elem()
+
+		for _, name := range strings.Split(v, ".") {
+			src = src.FieldByName(name)
+			dst = dst.FieldByName(name)
+		}
+
+		if src.IsValid() && dst.CanSet() {
+			dst.Set(deepCopy(src))
+		}
+	}
+
+	return projected
+}
+
+// patch sets the fields of the copied entity, a versioned entity is incremented
+func patch(entity interface{}, fields map[string]interface{}) error {
+	entityVal := reflect.ValueOf(entity).Elem()
+
+	for k, v := range fields {
+		field := entityVal.FieldByName(k)
+
+		if !field.CanSet() {
+			return fmt.Errorf("%w unknown field %s", core.ErrBadRequest, k)
+		}
+
+		if v == nil {
+			field.Set(reflect.Zero(field.Type()))
+
+			continue
+		}
+
+		value := reflect.ValueOf(v)
+
+		if !value.Type().ConvertibleTo(field.Type()) {
+			return fmt.Errorf("%w field %s is not %s", core.ErrBadRequest, k, field.Type())
+		}
+
+		field.Set(deepCopy(value.Convert(field.Type())))
+	}
+
+	if versioned, ok := entity.(core.Versioned); ok {
+		versioned.SetVersion(versioned.GetVersion() + 1)
+	}
+
+	return nil
+}
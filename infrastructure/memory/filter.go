@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+// violates returns the unique index violated by the entity against the other rows,
+// a nil field is not unique like NULL of the databases. The caller must hold the lock
+func (a *adapter) violates(entity core.Entitier) (core.Index, bool) {
+	for _, index := range a.indexes {
+		if !index.Unique {
+			continue
+		}
+
+		values, ok := indexValues(entity, index)
+		if !ok {
+			continue
+		}
+
+		for id, v := range a.rows {
+			if id == entity.GetID() {
+				continue
+			}
+
+			if other, ok := indexValues(v.entity, index); ok && reflect.DeepEqual(values, other) {
+				return index, true
+			}
+		}
+	}
+
+	return core.Index{}, false
+}
+
+func indexValues(entity interface{}, index core.Index) ([]interface{}, bool) {
+	values := make([]interface{}, 0, len(index.Fields))
+
+	for _, v := range index.Fields {
+		value, ok := core.FieldValue(entity, v.Field)
+		if !ok {
+			return nil, false
+		}
+
+		if valueOf := reflect.ValueOf(value); !valueOf.IsValid() || (valueOf.Kind() == reflect.Ptr && valueOf.IsNil()) {
+			return nil, false
+		}
+
+		values = append(values, value)
+	}
+
+	return values, true
+}
+
+func uniqueViolation(index core.Index) error {
+	return core.NewError(core.ErrConflict, core.CodeUniqueViolation, fmt.Sprintf("%s is violated", index.Name)).
+		WithDetail("index", index.Name)
+}
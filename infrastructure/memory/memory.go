@@ -0,0 +1,206 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+type adapter struct {
+	tableName string
+	model     core.Entitier
+	settings  MemorySettings
+	connected bool
+	indexes   []core.Index
+	rows      map[uuid.UUID]*row
+	seq       uint64
+	mutex     sync.RWMutex
+	faults    sync.Mutex
+	failures  []error
+	txMutex   sync.Mutex
+}
+
+// MemorySettings injects the latency and the failures of a real database,
+// Failure is asked by the operation name e.g. "Find" or "AddRange" before every operation
+type MemorySettings struct {
+	Latency time.Duration
+	Failure func(operation string) error
+}
+
+// row is never changed once stored, the writes replace the rows by copies
+type row struct {
+	seq    uint64
+	entity interface{}
+}
+
+// NewMemoryAdapter keeps the entities in memory for the tests, the entities are copied in and out
+// and the queries are evaluated like a database. The rows survive Disconnect like a database server
+func NewMemoryAdapter(settings MemorySettings) *adapter {
+	return &adapter{
+		settings: settings,
+		rows:     make(map[uuid.UUID]*row),
+	}
+}
+
+func (a *adapter) IsConnected() bool {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	return a.connected
+}
+
+func (a *adapter) Connect(ctx context.Context) error {
+	if err := a.before(ctx, "Connect"); err != nil {
+		return err
+	}
+
+	indexes, err := core.IndexesOf(a.model)
+	if err != nil {
+		return err
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.indexes = indexes
+	a.connected = true
+
+	return nil
+}
+
+func (a *adapter) Disconnect() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.connected = false
+}
+
+func (a *adapter) SetModel(model core.Entitier, tableName string) {
+	a.model = model
+	a.tableName = tableName
+}
+
+// InjectLatency delays the next operations, the context deadline still applies
+func (a *adapter) InjectLatency(latency time.Duration) {
+	a.faults.Lock()
+	defer a.faults.Unlock()
+
+	a.settings.Latency = latency
+}
+
+// InjectFailure replaces MemorySettings.Failure, nil removes it
+func (a *adapter) InjectFailure(failure func(operation string) error) {
+	a.faults.Lock()
+	defer a.faults.Unlock()
+
+	a.settings.Failure = failure
+}
+
+// FailNext fails the next count operations by err, before the injected failure is asked
+func (a *adapter) FailNext(count int, err error) {
+	a.faults.Lock()
+	defer a.faults.Unlock()
+
+	for i := 0; i < count; i++ {
+		a.failures = append(a.failures, err)
+	}
+}
+
+// before waits for the injected latency and returns the injected failure of the operation
+func (a *adapter) before(ctx context.Context, operation string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	a.faults.Lock()
+
+	latency, failure := a.settings.Latency, a.settings.Failure
+
+	var err error
+
+	if len(a.failures) > 0 {
+		err, a.failures = a.failures[0], a.failures[1:]
+	}
+
+	a.faults.Unlock()
+
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if err == nil && failure != nil {
+		err = failure(operation)
+	}
+
+	return err
+}
+
+// ready is checked after the injections, a disconnected adapter is unavailable
+func (a *adapter) ready(ctx context.Context, operation string) error {
+	if err := a.before(ctx, operation); err != nil {
+		return err
+	}
+
+	if !a.IsConnected() {
+		return fmt.Errorf("%w memory %s is disconnected", core.ErrUnavailable, a.tableName)
+	}
+
+	return nil
+}
+
+// scan returns the stored entities in scope which satisfy the spec in the insertion order,
+// the caller must copy them before handing them out
+func (a *adapter) scan(ctx context.Context, spec *core.Specification) []interface{} {
+	a.mutex.RLock()
+
+	rows := make([]*row, 0, len(a.rows))
+
+	for _, v := range a.rows {
+		rows = append(rows, v)
+	}
+
+	a.mutex.RUnlock()
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].seq < rows[j].seq
+	})
+
+	entities := make([]interface{}, 0, len(rows))
+
+	for _, v := range rows {
+		if core.InScope(ctx, v.entity) && (spec == nil || spec.IsSatisfiedBy(v.entity)) {
+			entities = append(entities, v.entity)
+		}
+	}
+
+	return entities
+}
+
+// stored returns the stored entity in scope, the caller must hold the lock
+func (a *adapter) stored(ctx context.Context, id uuid.UUID) (interface{}, bool) {
+	v, ok := a.rows[id]
+
+	if !ok || !core.InScope(ctx, v.entity) {
+		return nil, false
+	}
+
+	return v.entity, true
+}
+
+func notFound(id uuid.UUID) error {
+	return core.NewError(core.ErrNotFound, core.CodeNotFound, fmt.Sprintf("%s is not found", id))
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+func (a *adapter) Find(ctx context.Context, id uuid.UUID, dest core.Entitier) error {
+	if err := a.ready(ctx, "Find"); err != nil {
+		return err
+	}
+
+	a.mutex.RLock()
+	entity, ok := a.stored(ctx, id)
+	a.mutex.RUnlock()
+
+	if !ok {
+		return notFound(id)
+	}
+
+	return copyTo(dest, entity)
+}
+
+func (a *adapter) Any(ctx context.Context) (ok bool, err error) {
+	count, err := a.count(ctx, "Any", nil)
+
+	return count > 0, err
+}
+
+func (a *adapter) AnyWithFilter(ctx context.Context, query interface{}, args interface{}) (ok bool, err error) {
+	count, err := a.count(ctx, "AnyWithFilter", query)
+
+	return count > 0, err
+}
+
+func (a *adapter) Count(ctx context.Context) (count int64, err error) {
+	return a.count(ctx, "Count", nil)
+}
+
+func (a *adapter) CountWithFilter(ctx context.Context, query interface{}, args interface{}) (count int64, err error) {
+	return a.count(ctx, "CountWithFilter", query)
+}
+
+func (a *adapter) List(ctx context.Context, dest interface{}) error {
+	return a.list(ctx, "List", nil, core.QueryOptions{}, dest)
+}
+
+func (a *adapter) ListWithFilter(ctx context.Context, query interface{}, args interface{}, dest interface{}) error {
+	return a.list(ctx, "ListWithFilter", query, core.QueryOptions{}, dest)
+}
+
+func (a *adapter) ListWithOptions(ctx context.Context, query interface{}, options core.QueryOptions, dest interface{}) error {
+	return a.list(ctx, "ListWithOptions", query, options, dest)
+}
+
+func (a *adapter) list(ctx context.Context, operation string, query interface{}, options core.QueryOptions, dest interface{}) error {
+	if err := a.ready(ctx, operation); err != nil {
+		return err
+	}
+
+	spec, err := core.SpecificationOf(query)
+	if err != nil {
+		return err
+	}
+
+	items := a.scan(ctx, spec)
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return core.CompareByOrder(items[i], items[j], options.OrderBy) < 0
+	})
+
+	if options.Offset >= len(items) {
+		items = items[:0]
+	} else if options.Offset > 0 {
+		items = items[options.Offset:]
+	}
+
+	if options.Limit > 0 && options.Limit < len(items) {
+		items = items[:options.Limit]
+	}
+
+	return appendTo(dest, items, options.Fields)
+}
+
+// Iterate passes the copies, fn may write to the adapter
+func (a *adapter) Iterate(ctx context.Context, query interface{}, fn func(entity core.Entitier) error) error {
+	if err := a.ready(ctx, "Iterate"); err != nil {
+		return err
+	}
+
+	spec, err := core.SpecificationOf(query)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range a.scan(ctx, spec) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := fn(clone(v).(core.Entitier)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *adapter) Aggregate(ctx context.Context, query interface{}, aggregation core.Aggregation) ([]core.AggregateRow, error) {
+	if err := a.ready(ctx, "Aggregate"); err != nil {
+		return nil, err
+	}
+
+	spec, err := core.SpecificationOf(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return core.AggregateEntities(a.scan(ctx, spec), aggregation)
+}
+
+func (a *adapter) count(ctx context.Context, operation string, query interface{}) (int64, error) {
+	if err := a.ready(ctx, operation); err != nil {
+		return 0, err
+	}
+
+	spec, err := core.SpecificationOf(query)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(a.scan(ctx, spec))), nil
+}
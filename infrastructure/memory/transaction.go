@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+type transactionKey struct{}
+
+type transaction struct {
+	adapter *adapter
+	changes []change
+}
+
+// change is the row before the write, nil when it was absent
+type change struct {
+	id       uuid.UUID
+	previous *row
+}
+
+// batch writes the rows of a single operation, the operation is undone as a whole when it fails
+type batch struct {
+	adapter *adapter
+	changes []change
+}
+
+// Transaction rolls back the writes of fn by ctx when fn returns an error or panics.
+// The transactions are serialized, the writes outside of them are read uncommitted.
+// A nested transaction joins the outer one
+func (a *adapter) Transaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if tx, ok := ctx.Value(transactionKey{}).(*transaction); ok && tx.adapter == a {
+		return fn(ctx)
+	}
+
+	a.txMutex.Lock()
+	defer a.txMutex.Unlock()
+
+	tx := &transaction{adapter: a}
+
+	defer func() {
+		if r := recover(); r != nil {
+			a.rollback(tx)
+
+			panic(r)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, transactionKey{}, tx)); err != nil {
+		a.rollback(tx)
+	}
+
+	return err
+}
+
+func (a *adapter) rollback(tx *transaction) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.restore(tx.changes)
+
+	tx.changes = nil
+}
+
+// restore undoes the changes in the reverse order, the caller must hold the lock
+func (a *adapter) restore(changes []change) {
+	for i := len(changes) - 1; i >= 0; i-- {
+		if changes[i].previous == nil {
+			delete(a.rows, changes[i].id)
+		} else {
+			a.rows[changes[i].id] = changes[i].previous
+		}
+	}
+}
+
+// write runs fn under the lock, the changes are recorded by the transaction of ctx
+func (a *adapter) write(ctx context.Context, fn func(b *batch) error) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	b := &batch{adapter: a}
+
+	if err := fn(b); err != nil {
+		a.restore(b.changes)
+
+		return err
+	}
+
+	if tx, ok := ctx.Value(transactionKey{}).(*transaction); ok && tx.adapter == a {
+		tx.changes = append(tx.changes, b.changes...)
+	}
+
+	return nil
+}
+
+// put stores the copy of the entity, the unique indexes are checked against the other rows
+func (b *batch) put(entity core.Entitier) error {
+	a := b.adapter
+	id := entity.GetID()
+	previous := a.rows[id]
+
+	stored := &row{entity: clone(entity)}
+
+	if previous != nil {
+		stored.seq = previous.seq
+	} else {
+		a.seq++
+		stored.seq = a.seq
+	}
+
+	a.rows[id] = stored
+
+	b.changes = append(b.changes, change{id: id, previous: previous})
+
+	if index, ok := a.violates(entity); ok {
+		return uniqueViolation(index)
+	}
+
+	return nil
+}
+
+func (b *batch) remove(id uuid.UUID) {
+	a := b.adapter
+
+	if previous, ok := a.rows[id]; ok {
+		delete(a.rows, id)
+
+		b.changes = append(b.changes, change{id: id, previous: previous})
+	}
+}
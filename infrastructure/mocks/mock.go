@@ -2,8 +2,12 @@ package mocks
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 	cmap "github.com/orcaman/concurrent-map"
@@ -23,6 +27,16 @@ type adapter struct {
 	publishedCount uint32
 }
 
+// stateEntry expires at expiresAt unless it is zero
+type stateEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+func (e stateEntry) expired() bool {
+	return !e.expiresAt.IsZero() && time.Now().After(e.expiresAt)
+}
+
 type MockSettings struct {
 	Log *zap.SugaredLogger
 }
@@ -93,7 +107,9 @@ func (a *adapter) Has(ctx context.Context, key string) bool {
 
 	defer a.setting.Log.Debugw("mock has", "key", key)
 
-	return a.states.Has(key)
+	entry, ok := a.states.Get(key)
+
+	return ok && !entry.(stateEntry).expired()
 }
 
 func (a *adapter) Get(ctx context.Context, key string, dest interface{}) error {
@@ -102,8 +118,8 @@ func (a *adapter) Get(ctx context.Context, key string, dest interface{}) error {
 		return err
 	}
 
-	if resp, ok := a.states.Get(key); ok {
-		model.Copy(dest, resp)
+	if resp, ok := a.states.Get(key); ok && !resp.(stateEntry).expired() {
+		model.Copy(dest, resp.(stateEntry).value)
 
 		defer a.setting.Log.Debugw("mock get", "key", key, "result", dest)
 
@@ -123,11 +139,72 @@ func (a *adapter) Set(ctx context.Context, key string, value interface{}) error
 
 	defer a.setting.Log.Debugw("mock set", "key", key, "value", value)
 
-	a.states.Set(key, value)
+	a.states.Set(key, stateEntry{value: value})
 
 	return nil
 }
 
+func (a *adapter) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	// Check context cancellation
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer a.setting.Log.Debugw("mock set with ttl", "key", key, "value", value, "ttl", ttl)
+
+	a.states.Set(key, newStateEntry(value, ttl))
+
+	return nil
+}
+
+func (a *adapter) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	// Check context cancellation
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	ok := false
+
+	a.states.Upsert(key, nil, func(exist bool, valueInMap interface{}, newValue interface{}) interface{} {
+		if exist && !valueInMap.(stateEntry).expired() {
+			return valueInMap
+		}
+
+		ok = true
+
+		return newStateEntry(value, ttl)
+	})
+
+	defer a.setting.Log.Debugw("mock set if absent", "key", key, "value", value, "ttl", ttl, "ok", ok)
+
+	return ok, nil
+}
+
+func (a *adapter) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	// Check context cancellation
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	count := delta
+
+	a.states.Upsert(key, nil, func(exist bool, valueInMap interface{}, newValue interface{}) interface{} {
+		if !exist || valueInMap.(stateEntry).expired() {
+			return newStateEntry(count, ttl)
+		}
+
+		entry := valueInMap.(stateEntry)
+		count = entry.value.(int64) + delta
+		entry.value = count
+
+		return entry
+	})
+
+	defer a.setting.Log.Debugw("mock increment", "key", key, "delta", delta, "ttl", ttl, "count", count)
+
+	return count, nil
+}
+
 func (a *adapter) BatchSet(ctx context.Context, kvs []core.KV) error {
 	for _, v := range kvs {
 		err := a.Set(ctx, v.K, v.V)
@@ -213,8 +290,12 @@ func (a *adapter) Find(ctx context.Context, id uuid.UUID, dest core.Entitier) (e
 		return err
 	}
 
-	if resp, ok := a.db.Get(id.String()); ok {
-		model.Copy(dest, resp)
+	if resp, ok := a.db.Get(id.String()); ok && core.InScope(ctx, resp) {
+		if reflect.TypeOf(dest) != reflect.TypeOf(resp) {
+			return fmt.Errorf("%w dest %T is not %T", core.ErrInternalServerError, dest, resp)
+		}
+
+		reflect.ValueOf(dest).Elem().Set(reflect.ValueOf(resp).Elem())
 
 		defer a.setting.Log.Debugw("mock find", "id", id, "dest", dest)
 		return nil
@@ -261,7 +342,7 @@ func (a *adapter) Count(ctx context.Context) (count int64, err error) {
 		return 0, err
 	}
 
-	resp := a.db.Count()
+	resp := a.scopedCount(ctx, nil)
 
 	defer a.setting.Log.Debugw("mock count", "count", resp)
 
@@ -274,7 +355,12 @@ func (a *adapter) CountWithFilter(ctx context.Context, query interface{}, args i
 		return 0, err
 	}
 
-	resp := a.db.Count()
+	spec, err := filter(query)
+	if err != nil {
+		return 0, err
+	}
+
+	resp := a.scopedCount(ctx, spec)
 
 	defer a.setting.Log.Debugw("mock count with filter", "count", resp, "query", query, "args", args)
 
@@ -296,7 +382,11 @@ func (a *adapter) List(ctx context.Context, dest interface{}) (err error) {
 	}
 
 	for _, v := range a.db.Items() {
-		entityVal := reflect.ValueOf(v)
+		if !core.InScope(ctx, v) {
+			continue
+		}
+
+		entityVal := reflect.ValueOf(clone(v))
 
 		sliceVal = reflect.Append(sliceVal, entityVal)
 	}
@@ -314,6 +404,11 @@ func (a *adapter) ListWithFilter(ctx context.Context, query interface{}, args in
 		return err
 	}
 
+	spec, err := filter(query)
+	if err != nil {
+		return err
+	}
+
 	resultsVal := reflect.ValueOf(dest)
 
 	sliceVal := resultsVal.Elem()
@@ -323,7 +418,11 @@ func (a *adapter) ListWithFilter(ctx context.Context, query interface{}, args in
 	}
 
 	for _, v := range a.db.Items() {
-		entityVal := reflect.ValueOf(v)
+		if !core.InScope(ctx, v) || !matches(spec, v) {
+			continue
+		}
+
+		entityVal := reflect.ValueOf(clone(v))
 
 		sliceVal = reflect.Append(sliceVal, entityVal)
 	}
@@ -335,6 +434,108 @@ func (a *adapter) ListWithFilter(ctx context.Context, query interface{}, args in
 	return nil
 }
 
+func (a *adapter) ListWithOptions(ctx context.Context, query interface{}, options core.QueryOptions, dest interface{}) (err error) {
+	// Check context cancellation
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	spec, err := filter(query)
+	if err != nil {
+		return err
+	}
+
+	items := make([]interface{}, 0)
+
+	for _, v := range a.db.Items() {
+		if core.InScope(ctx, v) && matches(spec, v) {
+			items = append(items, v)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return core.CompareByOrder(items[i], items[j], options.OrderBy) < 0
+	})
+
+	if options.Offset >= len(items) {
+		items = items[:0]
+	} else if options.Offset > 0 {
+		items = items[options.Offset:]
+	}
+
+	if options.Limit > 0 && options.Limit < len(items) {
+		items = items[:options.Limit]
+	}
+
+	resultsVal := reflect.ValueOf(dest)
+
+	sliceVal := resultsVal.Elem()
+
+	if sliceVal.Kind() == reflect.Interface {
+		sliceVal = sliceVal.Elem()
+	}
+
+	for _, v := range items {
+		sliceVal = reflect.Append(sliceVal, project(v, options.Fields))
+	}
+
+	resultsVal.Elem().Set(sliceVal)
+
+	defer a.setting.Log.Debugw("mock list with options", "dest", dest, "query", query, "options", options)
+
+	return nil
+}
+
+func (a *adapter) Iterate(ctx context.Context, query interface{}, fn func(entity core.Entitier) error) error {
+	defer a.setting.Log.Debugw("mock iterate", "query", query)
+
+	spec, err := filter(query)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range a.db.Items() {
+		// Check context cancellation
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if !core.InScope(ctx, v) || !matches(spec, v) {
+			continue
+		}
+
+		if err := fn(clone(v).(core.Entitier)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *adapter) Aggregate(ctx context.Context, query interface{}, aggregation core.Aggregation) ([]core.AggregateRow, error) {
+	// Check context cancellation
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer a.setting.Log.Debugw("mock aggregate", "query", query, "aggregation", aggregation)
+
+	spec, err := filter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]interface{}, 0)
+
+	for _, v := range a.db.Items() {
+		if core.InScope(ctx, v) && matches(spec, v) {
+			entities = append(entities, v)
+		}
+	}
+
+	return core.AggregateEntities(entities, aggregation)
+}
+
 func (a *adapter) Remove(ctx context.Context, id uuid.UUID) error {
 	// Check context cancellation
 	if err := ctx.Err(); err != nil {
@@ -343,7 +544,13 @@ func (a *adapter) Remove(ctx context.Context, id uuid.UUID) error {
 
 	defer a.setting.Log.Debugw("mock remove", "id", id)
 
-	a.db.Remove(id.String())
+	removed := a.db.RemoveCb(id.String(), func(key string, v interface{}, exists bool) bool {
+		return exists && core.InScope(ctx, v)
+	})
+
+	if !removed {
+		return core.NewError(core.ErrNotFound, core.CodeNotFound, fmt.Sprintf("%s is not found", id))
+	}
 
 	return nil
 }
@@ -356,8 +563,15 @@ func (a *adapter) RemoveRange(ctx context.Context, ids []uuid.UUID) error {
 
 	defer a.setting.Log.Debugw("mock remove range")
 
+	// none of them is removed when any entity is not found
+	for _, id := range ids {
+		if v, ok := a.db.Get(id.String()); !ok || !core.InScope(ctx, v) {
+			return core.NewError(core.ErrNotFound, core.CodeNotFound, fmt.Sprintf("%s is not found", id))
+		}
+	}
+
 	for _, id := range ids {
-		a.Remove(ctx, id)
+		a.db.Remove(id.String())
 	}
 
 	return nil
@@ -371,7 +585,9 @@ func (a *adapter) Add(ctx context.Context, entity core.Entitier) error {
 
 	defer a.setting.Log.Debugw("mock add", "entity", entity)
 
-	a.db.Set(entity.GetID().String(), entity)
+	if !a.db.SetIfAbsent(entity.GetID().String(), clone(entity)) {
+		return core.NewError(core.ErrConflict, core.CodeUniqueViolation, fmt.Sprintf("%s already exists", entity.GetID()))
+	}
 
 	return nil
 }
@@ -385,7 +601,9 @@ func (a *adapter) AddRange(ctx context.Context, entities []core.Entitier) error
 	defer a.setting.Log.Debugw("mock add range")
 
 	for _, v := range entities {
-		a.Add(ctx, v)
+		if err := a.Add(ctx, v); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -399,7 +617,35 @@ func (a *adapter) Update(ctx context.Context, entity core.Entitier) error {
 
 	defer a.setting.Log.Debugw("mock update", "entity", entity)
 
-	a.db.Set(entity.GetID().String(), entity)
+	if stored, exist := a.db.Get(entity.GetID().String()); !exist || !core.InScope(ctx, stored) {
+		return core.NewError(core.ErrNotFound, core.CodeNotFound, fmt.Sprintf("%s is not found", entity.GetID()))
+	}
+
+	versioned, ok := entity.(core.Versioned)
+	if !ok {
+		a.db.Set(entity.GetID().String(), clone(entity))
+
+		return nil
+	}
+
+	expected := versioned.GetVersion()
+	conflicted := false
+
+	a.db.Upsert(entity.GetID().String(), nil, func(exist bool, valueInMap interface{}, newValue interface{}) interface{} {
+		if exist && valueInMap.(core.Versioned).GetVersion() != expected {
+			conflicted = true
+
+			return valueInMap
+		}
+
+		versioned.SetVersion(expected + 1)
+
+		return clone(entity)
+	})
+
+	if conflicted {
+		return fmt.Errorf("%w version %d is changed", core.ErrConflict, expected)
+	}
 
 	return nil
 }
@@ -413,7 +659,309 @@ func (a *adapter) UpdateRange(ctx context.Context, entities []core.Entitier) err
 	defer a.setting.Log.Debugw("mock update range")
 
 	for _, v := range entities {
-		a.Update(ctx, v)
+		if err := a.Update(ctx, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *adapter) Upsert(ctx context.Context, entity core.Entitier) (affected int64, err error) {
+	// Check context cancellation
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	defer a.setting.Log.Debugw("mock upsert", "entity", entity)
+
+	if err := a.guardTenant(ctx, []core.Entitier{entity}); err != nil {
+		return 0, err
+	}
+
+	return a.upsert(ctx, entity)
+}
+
+func (a *adapter) UpsertRange(ctx context.Context, entities []core.Entitier) (affected int64, err error) {
+	// Check context cancellation
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	defer a.setting.Log.Debugw("mock upsert range")
+
+	// none of them is upserted when any entity belongs to another tenant
+	if err := a.guardTenant(ctx, entities); err != nil {
+		return 0, err
+	}
+
+	for _, v := range entities {
+		count, err := a.upsert(ctx, v)
+
+		if err != nil {
+			return affected, err
+		}
+
+		affected += count
+	}
+
+	return affected, nil
+}
+
+// upsert keeps the creation stamp of the existing entity, the entity of another tenant is never overwritten
+func (a *adapter) upsert(ctx context.Context, entity core.Entitier) (affected int64, err error) {
+	tenantId, scoped := core.TenantScope(ctx, a.model)
+
+	a.db.Upsert(entity.GetID().String(), clone(entity), func(exist bool, valueInMap interface{}, newValue interface{}) interface{} {
+		if !exist {
+			affected = 1
+
+			return newValue
+		}
+
+		if scoped && valueInMap.(core.TenantEntitier).GetTenantId() != tenantId {
+			err = tenantViolation(1, 1)
+
+			return valueInMap
+		}
+
+		stored := reflect.ValueOf(valueInMap).Elem()
+		upserted := reflect.ValueOf(newValue).Elem()
+
+		for _, name := range []string{"CreateUser", "CreatedAt"} {
+			upserted.FieldByName(name).Set(stored.FieldByName(name))
+		}
+
+		if versioned, ok := newValue.(core.Versioned); ok {
+			versioned.SetVersion(valueInMap.(core.Versioned).GetVersion() + 1)
+		}
+
+		affected = 1
+
+		return newValue
+	})
+
+	return affected, err
+}
+
+// guardTenant rejects the ids owned by another tenant before the upsert like the gorm adapter
+func (a *adapter) guardTenant(ctx context.Context, entities []core.Entitier) error {
+	tenantId, ok := core.TenantScope(ctx, a.model)
+	if !ok {
+		return nil
+	}
+
+	count := 0
+
+	for _, v := range entities {
+		if stored, ok := a.db.Get(v.GetID().String()); ok && stored.(core.TenantEntitier).GetTenantId() != tenantId {
+			count++
+		}
+	}
+
+	if count > 0 {
+		return tenantViolation(count, len(entities))
+	}
+
+	return nil
+}
+
+func tenantViolation(count int, total int) error {
+	return core.NewError(core.ErrConflict, core.CodeUniqueViolation, fmt.Sprintf("%d of %d entities belong to another tenant", count, total))
+}
+
+func (a *adapter) Patch(ctx context.Context, id uuid.UUID, fields map[string]interface{}) (affected int64, err error) {
+	// Check context cancellation
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	defer a.setting.Log.Debugw("mock patch", "id", id, "fields", fields)
+
+	a.db.Upsert(id.String(), nil, func(exist bool, valueInMap interface{}, newValue interface{}) interface{} {
+		if !exist || !core.InScope(ctx, valueInMap) {
+			return valueInMap
+		}
+
+		patched := clone(valueInMap)
+
+		if err = patch(patched, fields); err != nil {
+			return valueInMap
+		}
+
+		affected = 1
+
+		return patched
+	})
+
+	return affected, err
+}
+
+func (a *adapter) UpdateWhere(ctx context.Context, query interface{}, fields map[string]interface{}) (affected int64, err error) {
+	defer a.setting.Log.Debugw("mock update where", "query", query, "fields", fields)
+
+	spec, err := filter(query)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, v := range a.db.Items() {
+		// Check context cancellation
+		if err := ctx.Err(); err != nil {
+			return affected, err
+		}
+
+		if !core.InScope(ctx, v) || !matches(spec, v) {
+			continue
+		}
+
+		count, err := a.Patch(ctx, v.(core.Entitier).GetID(), fields)
+
+		if err != nil {
+			return affected, err
+		}
+
+		affected += count
+	}
+
+	return affected, nil
+}
+
+func (a *adapter) RemoveWhere(ctx context.Context, query interface{}) (affected int64, err error) {
+	defer a.setting.Log.Debugw("mock remove where", "query", query)
+
+	spec, err := filter(query)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, v := range a.db.Items() {
+		// Check context cancellation
+		if err := ctx.Err(); err != nil {
+			return affected, err
+		}
+
+		if !core.InScope(ctx, v) || !matches(spec, v) {
+			continue
+		}
+
+		if a.db.RemoveCb(v.(core.Entitier).GetID().String(), func(key string, v interface{}, exists bool) bool {
+			return exists && core.InScope(ctx, v) && matches(spec, v)
+		}) {
+			affected++
+		}
+	}
+
+	return affected, nil
+}
+
+func newStateEntry(value interface{}, ttl time.Duration) stateEntry {
+	entry := stateEntry{value: value}
+
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	return entry
+}
+
+func (a *adapter) scopedCount(ctx context.Context, spec *core.Specification) int {
+	_, tenantScoped := core.TenantScope(ctx, a.model)
+	_, deletedScoped := core.DeletedScopeOf(ctx, a.model)
+
+	if !tenantScoped && !deletedScoped && spec == nil {
+		return a.db.Count()
+	}
+
+	count := 0
+
+	for _, v := range a.db.Items() {
+		if core.InScope(ctx, v) && matches(spec, v) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// filter accepts the empty raw query as no filter, the other raw queries are rejected like the memory adapter
+func filter(query interface{}) (*core.Specification, error) {
+	if query == "" {
+		return nil, nil
+	}
+
+	return core.SpecificationOf(query)
+}
+
+func matches(spec *core.Specification, entity interface{}) bool {
+	return spec == nil || spec.IsSatisfiedBy(entity)
+}
+
+// clone copies the entity by value, the stored entities are never shared with the callers
+func clone(entity interface{}) interface{} {
+	entityVal := reflect.ValueOf(entity)
+
+	cloned := reflect.New(entityVal.Elem().Type())
+	cloned.Elem().Set(entityVal.Elem())
+
+	return cloned.Interface()
+}
+
+// project copies only the fields into the new entity
+func project(entity interface{}, fields []string) reflect.Value {
+	entityVal := reflect.ValueOf(entity)
+
+	if len(fields) == 0 {
+		return reflect.ValueOf(clone(entity))
+	}
+
+	projected := reflect.New(entityVal.Elem().Type())
+
+	for _, v := range fields {
+		src := entityVal.Elem()
+		dst := projected.Elem()
+
+		for _, name := range strings.Split(v, ".") {
+			src = src.FieldByName(name)
+			dst = dst.FieldByName(name)
+		}
+
+		if src.IsValid() && dst.CanSet() {
+			dst.Set(src)
+		}
+	}
+
+	return projected
+}
+
+// patch sets the fields of the stored entity, a versioned entity is incremented
+func patch(entity interface{}, fields map[string]interface{}) error {
+	entityVal := reflect.ValueOf(entity).Elem()
+
+	for k, v := range fields {
+		field := entityVal.FieldByName(k)
+
+		if !field.CanSet() {
+			return fmt.Errorf("%w unknown field %s", core.ErrBadRequest, k)
+		}
+
+		if v == nil {
+			field.Set(reflect.Zero(field.Type()))
+
+			continue
+		}
+
+		value := reflect.ValueOf(v)
+
+		if !value.Type().ConvertibleTo(field.Type()) {
+			return fmt.Errorf("%w field %s is not %s", core.ErrBadRequest, k, field.Type())
+		}
+
+		field.Set(value.Convert(field.Type()))
+	}
+
+	if versioned, ok := entity.(core.Versioned); ok {
+		versioned.SetVersion(versioned.GetVersion() + 1)
 	}
 
 	return nil
@@ -0,0 +1,120 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+// Aggregate translates the aggregation to the $match, $group and $sort pipeline
+func (a *adapter) Aggregate(ctx context.Context, query interface{}, aggregation core.Aggregation) ([]core.AggregateRow, error) {
+	collection, err := a.readCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if query == nil {
+		query = bson.M{}
+	}
+
+	filter, err := a.query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var id interface{}
+
+	keys := bson.D{}
+	sort := bson.D{}
+
+	for i, v := range aggregation.GroupBy {
+		path, err := bsonPath(reflect.TypeOf(a.model), v)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, bson.E{Key: fmt.Sprintf("k%d", i), Value: "$" + path})
+		sort = append(sort, bson.E{Key: fmt.Sprintf("_id.k%d", i), Value: 1})
+	}
+
+	if len(keys) > 0 {
+		id = keys
+	}
+
+	group := bson.D{{Key: "_id", Value: id}}
+
+	for i, v := range aggregation.Aggregates {
+		var accumulator bson.M
+
+		if v.Function == core.AggregateCount {
+			accumulator = bson.M{"$sum": 1}
+		} else {
+			path, err := bsonPath(reflect.TypeOf(a.model), v.Field)
+			if err != nil {
+				return nil, err
+			}
+
+			accumulator = bson.M{"$" + string(v.Function): "$" + path}
+		}
+
+		group = append(group, bson.E{Key: fmt.Sprintf("a%d", i), Value: accumulator})
+	}
+
+	pipeline := bson.A{
+		bson.M{"$match": a.filter(ctx, filter)},
+		bson.M{"$group": group},
+	}
+
+	if len(sort) > 0 {
+		pipeline = append(pipeline, bson.M{"$sort": sort})
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, translate(err)
+	}
+
+	defer cursor.Close(ctx)
+
+	result := make([]core.AggregateRow, 0)
+
+	for cursor.Next(ctx) {
+		var doc bson.M
+
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+
+		row := core.AggregateRow{
+			Keys:   make(map[string]interface{}, len(aggregation.GroupBy)),
+			Values: make(map[string]float64, len(aggregation.Aggregates)),
+		}
+
+		groupKeys, _ := doc["_id"].(bson.M)
+
+		for i, v := range aggregation.GroupBy {
+			row.Keys[v] = groupKeys[fmt.Sprintf("k%d", i)]
+		}
+
+		for i, v := range aggregation.Aggregates {
+			switch value := doc[fmt.Sprintf("a%d", i)].(type) {
+			case int32:
+				row.Values[v.Alias] = float64(value)
+			case int64:
+				row.Values[v.Alias] = float64(value)
+			case float64:
+				row.Values[v.Alias] = value
+			default:
+				row.Values[v.Alias] = 0
+			}
+		}
+
+		result = append(result, row)
+	}
+
+	return result, translate(cursor.Err())
+}
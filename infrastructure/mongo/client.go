@@ -0,0 +1,74 @@
+package mongo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+// clientKey identifies the client by the full configuration, the adapters of the same configuration share the client
+func (a *adapter) clientKey(uri string, databaseName string) string {
+	config, _ := json.Marshal(struct {
+		Uri          string
+		DatabaseName string
+		Credential   MongoCredentialSettings
+		Pool         MongoPoolSettings
+		TLS          core.TLSSettings
+	}{
+		Uri:          uri,
+		DatabaseName: databaseName,
+		Credential:   a.settings.Credential,
+		Pool:         a.settings.Pool,
+		TLS:          a.settings.TLS,
+	})
+
+	sum := sha256.Sum256(config)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func (a *adapter) clientOptions(uri string) (*options.ClientOptions, error) {
+	opts := options.Client().ApplyURI(uri)
+
+	if credential := a.settings.Credential; credential.Username != "" {
+		opts.SetAuth(options.Credential{
+			Username:      credential.Username,
+			Password:      credential.Password,
+			AuthSource:    credential.AuthSource,
+			AuthMechanism: credential.AuthMechanism,
+		})
+	}
+
+	pool := a.settings.Pool
+
+	if pool.MaxPoolSize > 0 {
+		opts.SetMaxPoolSize(pool.MaxPoolSize)
+	}
+
+	if pool.MinPoolSize > 0 {
+		opts.SetMinPoolSize(pool.MinPoolSize)
+	}
+
+	if pool.MaxConnIdleTime > 0 {
+		opts.SetMaxConnIdleTime(pool.MaxConnIdleTime)
+	}
+
+	if pool.ConnectTimeout > 0 {
+		opts.SetConnectTimeout(pool.ConnectTimeout)
+	}
+
+	tlsConfig, err := a.settings.TLS.ToTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig != nil {
+		opts.SetTLSConfig(tlsConfig)
+	}
+
+	return opts, opts.Validate()
+}
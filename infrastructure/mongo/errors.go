@@ -0,0 +1,33 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+// translate maps the duplicate keys and the timeouts to the core errors of the safe messages,
+// the driver error is kept as the cause which is never rendered to the clients
+func translate(err error) error {
+	var coreErr *core.Error
+
+	if err == nil || errors.As(err, &coreErr) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	switch {
+	case mongo.IsDuplicateKeyError(err):
+		return core.NewError(core.ErrConflict, core.CodeUniqueViolation, "unique constraint violated").WithCause(err)
+	case mongo.IsTimeout(err):
+		return core.NewError(context.DeadlineExceeded, core.CodeTimeout, "operation timed out").WithCause(err)
+	}
+
+	return err
+}
+
+func notFound(message string) error {
+	return core.NewError(core.ErrNotFound, core.CodeNotFound, message)
+}
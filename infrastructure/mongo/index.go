@@ -0,0 +1,125 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+// migration creates the collection with its options and the indexes declared by the model,
+// the existing indexes of the same specification are kept so it is idempotent
+func (a *adapter) migration(ctx context.Context, client *clientProxy) error {
+	names, err := client.database.ListCollectionNames(ctx, bson.M{"name": a.tableName})
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 && a.settings.CanCreateCollection {
+		if err := client.database.CreateCollection(ctx, a.tableName, a.collectionOptions()); err != nil {
+			return err
+		}
+	} else if len(names) > 0 && a.settings.Collection.Validator != nil {
+		command := bson.D{
+			{Key: "collMod", Value: a.tableName},
+			{Key: "validator", Value: a.settings.Collection.Validator},
+		}
+
+		if err := client.database.RunCommand(ctx, command).Err(); err != nil {
+			return err
+		}
+	}
+
+	models, err := a.indexModels()
+	if err != nil {
+		return err
+	}
+
+	if len(models) == 0 {
+		return nil
+	}
+
+	_, err = client.database.Collection(a.tableName).Indexes().CreateMany(ctx, models)
+
+	return err
+}
+
+func (a *adapter) collectionOptions() *options.CreateCollectionOptions {
+	settings := a.settings.Collection
+	opts := options.CreateCollection()
+
+	if settings.Validator != nil {
+		opts.SetValidator(settings.Validator)
+	}
+
+	if settings.Capped {
+		opts.SetCapped(true).SetSizeInBytes(settings.SizeInBytes)
+
+		if settings.MaxDocuments > 0 {
+			opts.SetMaxDocuments(settings.MaxDocuments)
+		}
+	}
+
+	if settings.Collation != nil {
+		opts.SetCollation(settings.Collation)
+	}
+
+	return opts
+}
+
+// indexModels translates the indexes of the model to the bson paths, the entity id is always indexed
+func (a *adapter) indexModels() ([]mongo.IndexModel, error) {
+	indexes, err := core.IndexesOf(a.model)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]mongo.IndexModel, 0, len(indexes)+1)
+
+	// the documents have their own _id, the entity id is kept unique across the tenants
+	models = append(models, mongo.IndexModel{
+		Keys:    bson.D{{Key: "entity._id", Value: 1}},
+		Options: options.Index().SetName("entity_id").SetUnique(true),
+	})
+
+	for _, v := range indexes {
+		keys := bson.D{}
+
+		for _, field := range v.Fields {
+			path, err := bsonPath(reflect.TypeOf(a.model), field.Field)
+			if err != nil {
+				return nil, fmt.Errorf("index %s: %w", v.Name, err)
+			}
+
+			var order interface{} = 1
+
+			switch {
+			case v.Text:
+				order = "text"
+			case field.Descending:
+				order = -1
+			}
+
+			keys = append(keys, bson.E{Key: path, Value: order})
+		}
+
+		opts := options.Index().SetName(v.Name)
+
+		if v.Unique {
+			opts.SetUnique(true)
+		}
+
+		if v.TTL > 0 {
+			opts.SetExpireAfterSeconds(int32(v.TTL.Seconds()))
+		}
+
+		models = append(models, mongo.IndexModel{Keys: keys, Options: opts})
+	}
+
+	return models, nil
+}
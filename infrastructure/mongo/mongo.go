@@ -4,28 +4,33 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 
 	"github.com/google/uuid"
 	"github.com/jybbang/go-core-architecture/core"
 )
 
 type adapter struct {
-	tableName string
-	model     core.Entitier
-	client    *clientProxy
-	settings  MongoSettings
+	tableName     string
+	model         core.Entitier
+	client        *clientProxy
+	tenantClients map[string]*clientProxy
+	readPref      *readpref.ReadPref
+	settings      MongoSettings
+	sync.RWMutex
 }
 
 type clientProxy struct {
 	conn        *mongo.Client
 	database    *mongo.Database
-	collection  *mongo.Collection
 	isConnected bool
 }
 
@@ -38,46 +43,68 @@ type MongoSettings struct {
 	ConnectionUri       string
 	DatabaseName        string
 	CanCreateCollection bool
+	// Tenants maps tenant id to its own database
+	Tenants map[string]MongoTenantSettings
+	// Collection is applied when the collection is created
+	Collection MongoCollectionSettings
+	// ReadPreference of the queries, primary, primaryPreferred, secondary, secondaryPreferred or nearest.
+	// The queries read from the primary when the context requires it, see core.WithReadYourWrites
+	ReadPreference string
+	// MaxStaleness ejects the secondaries lagging behind the primary, at least 90s
+	MaxStaleness time.Duration
+	// Credential overrides the credential of the uri, applied when Username is set
+	Credential MongoCredentialSettings
+	Pool       MongoPoolSettings
+	TLS        core.TLSSettings
 }
 
-var clientsInstance *clients
-
-func init() {
-	clientsInstance = &clients{
-		clients: make(map[string]*clientProxy),
-	}
+type MongoCredentialSettings struct {
+	Username      string
+	Password      string
+	AuthSource    string
+	AuthMechanism string
 }
 
-func (a *adapter) migration() {
-	ctx := context.Background()
-
-	names, err := a.client.database.ListCollectionNames(
-		ctx,
-		bson.D{})
-
-	if err != nil {
-		panic(err)
-	}
+// MongoPoolSettings of the client, zero keeps the driver defaults
+type MongoPoolSettings struct {
+	MaxPoolSize     uint64
+	MinPoolSize     uint64
+	MaxConnIdleTime time.Duration
+	ConnectTimeout  time.Duration
+}
 
-	hasCollection := false
+type MongoCollectionSettings struct {
+	// Validator is the $jsonSchema document validating the inserts and updates
+	Validator interface{}
+	// Capped collection of SizeInBytes, MaxDocuments is optional
+	Capped       bool
+	SizeInBytes  int64
+	MaxDocuments int64
+	Collation    *options.Collation
+}
 
-	for _, name := range names {
-		if name == a.tableName {
-			hasCollection = true
+type MongoTenantSettings struct {
+	ConnectionUri string
+	DatabaseName  string
+}
 
-			break
-		}
-	}
+const (
+	// tenantField is the path of the embedded core.TenantEntity
+	tenantField = "tenantentity.tenantId"
+	// deletedField is the path of the embedded core.SoftDeleteEntity
+	deletedField = "softdeleteentity.deletedAt"
+	// versionField is the path of the embedded core.VersionEntity
+	versionField = "versionentity.version"
+	// iterateBatchSize bounds the documents in memory while iterating
+	iterateBatchSize = 1000
+)
 
-	if !hasCollection && a.settings.CanCreateCollection {
-		err := a.client.database.CreateCollection(ctx, a.tableName)
+var clientsInstance *clients
 
-		if err != nil {
-			panic(err)
-		}
+func init() {
+	clientsInstance = &clients{
+		clients: make(map[string]*clientProxy),
 	}
-
-	a.client.collection = a.client.database.Collection(a.tableName)
 }
 
 func NewMongoAdapter(settings MongoSettings) *adapter {
@@ -91,52 +118,188 @@ func (a *adapter) IsConnected() bool {
 }
 
 func (a *adapter) Connect(ctx context.Context) error {
+	readPref, err := a.readPreference()
+	if err != nil {
+		return err
+	}
+
+	client, err := a.connect(ctx, a.settings.ConnectionUri, a.settings.DatabaseName)
+	if err != nil {
+		return err
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	a.client = client
+	a.tenantClients = make(map[string]*clientProxy)
+	a.readPref = readPref
+
+	return nil
+}
+
+func (a *adapter) connect(ctx context.Context, uri string, databaseName string) (*clientProxy, error) {
 	clientsInstance.Lock()
 	defer clientsInstance.Unlock()
 
-	uri := a.settings.ConnectionUri
-
 	if strings.TrimSpace(uri) == "" {
-		return fmt.Errorf("uri is required")
+		return nil, fmt.Errorf("uri is required")
 	}
 
-	cli, ok := clientsInstance.clients[uri]
+	key := a.clientKey(uri, databaseName)
+
+	cli, ok := clientsInstance.clients[key]
 
 	if !ok || !cli.isConnected {
-		mongoClient, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+		opts, err := a.clientOptions(uri)
+		if err != nil {
+			return nil, err
+		}
+
+		mongoClient, err := mongo.Connect(ctx, opts)
 
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		// Check context cancellation
 		if err := ctx.Err(); err != nil {
-			return err
+			return nil, err
 		}
 
-		clientsInstance.clients[uri] = &clientProxy{
+		clientsInstance.clients[key] = &clientProxy{
 			conn:        mongoClient,
-			database:    mongoClient.Database(a.settings.DatabaseName),
+			database:    mongoClient.Database(databaseName),
 			isConnected: true,
 		}
 	}
 
-	a.client = clientsInstance.clients[uri]
+	client := clientsInstance.clients[key]
 
 	if a.tableName != "" {
-		a.migration()
+		if err := a.migration(ctx, client); err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	return client, nil
 }
 
 func (a *adapter) Disconnect() {
 	clientsInstance.Lock()
 	defer clientsInstance.Unlock()
 
+	a.RLock()
+	defer a.RUnlock()
+
 	a.client.conn.Disconnect(context.Background())
 
 	a.client.isConnected = false
+
+	for _, v := range a.tenantClients {
+		v.conn.Disconnect(context.Background())
+
+		v.isConnected = false
+	}
+}
+
+// collection returns the collection of the tenant database, connects lazily on first use
+func (a *adapter) collection(ctx context.Context) (*mongo.Collection, error) {
+	tenantId, ok := core.TenantFromContext(ctx)
+	if !ok {
+		return a.client.database.Collection(a.tableName), nil
+	}
+
+	tenant, ok := a.settings.Tenants[tenantId]
+	if !ok {
+		return a.client.database.Collection(a.tableName), nil
+	}
+
+	a.RLock()
+	client, ok := a.tenantClients[tenantId]
+	a.RUnlock()
+
+	if ok && client.isConnected {
+		return client.database.Collection(a.tableName), nil
+	}
+
+	uri := tenant.ConnectionUri
+
+	if uri == "" {
+		uri = a.settings.ConnectionUri
+	}
+
+	client, err := a.connect(ctx, uri, tenant.DatabaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	a.tenantClients[tenantId] = client
+
+	return client.database.Collection(a.tableName), nil
+}
+
+func (a *adapter) readPreference() (*readpref.ReadPref, error) {
+	if a.settings.ReadPreference == "" {
+		return nil, nil
+	}
+
+	mode, err := readpref.ModeFromString(a.settings.ReadPreference)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.settings.MaxStaleness > 0 {
+		return readpref.New(mode, readpref.WithMaxStaleness(a.settings.MaxStaleness))
+	}
+
+	return readpref.New(mode)
+}
+
+// readCollection returns the collection of the queries, which reads by the read preference
+// unless the context requires the primary
+func (a *adapter) readCollection(ctx context.Context) (*mongo.Collection, error) {
+	collection, err := a.collection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	a.RLock()
+	readPref := a.readPref
+	a.RUnlock()
+
+	if readPref == nil || core.RequiresPrimary(ctx) {
+		return collection, nil
+	}
+
+	return collection.Clone(options.Collection().SetReadPreference(readPref))
+}
+
+// filter scopes the filter by the tenant and the deleted scope of ctx
+func (a *adapter) filter(ctx context.Context, filter interface{}) interface{} {
+	filters := bson.A{filter}
+
+	if tenantId, ok := core.TenantScope(ctx, a.model); ok {
+		filters = append(filters, bson.M{tenantField: tenantId})
+	}
+
+	if scope, ok := core.DeletedScopeOf(ctx, a.model); ok {
+		switch scope {
+		case core.DeletedExcluded:
+			filters = append(filters, bson.M{deletedField: nil})
+		case core.DeletedOnly:
+			filters = append(filters, bson.M{deletedField: bson.M{"$ne": nil}})
+		}
+	}
+
+	if len(filters) == 1 {
+		return filter
+	}
+
+	return bson.M{"$and": filters}
 }
 
 func (a *adapter) SetModel(model core.Entitier, tableName string) {
@@ -145,14 +308,19 @@ func (a *adapter) SetModel(model core.Entitier, tableName string) {
 }
 
 func (a *adapter) Find(ctx context.Context, id uuid.UUID, dest core.Entitier) error {
-	err := a.client.collection.FindOne(ctx, bson.M{"entity._id": id}).Decode(dest)
+	collection, err := a.readCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = collection.FindOne(ctx, a.filter(ctx, bson.M{"entity._id": id})).Decode(dest)
 
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return core.ErrNotFound
 		}
 
-		return err
+		return translate(err)
 	}
 
 	return nil
@@ -171,106 +339,284 @@ func (a *adapter) AnyWithFilter(ctx context.Context, query interface{}, args int
 }
 
 func (a *adapter) Count(ctx context.Context) (count int64, err error) {
-	count, err = a.client.collection.CountDocuments(ctx, bson.M{})
-
+	collection, err := a.readCollection(ctx)
 	if err != nil {
 		return 0, err
 	}
 
+	count, err = collection.CountDocuments(ctx, a.filter(ctx, bson.M{}))
+
+	if err != nil {
+		return 0, translate(err)
+	}
+
 	return count, nil
 }
 
 func (a *adapter) CountWithFilter(ctx context.Context, query interface{}, args interface{}) (count int64, err error) {
-	count, err = a.client.collection.CountDocuments(ctx, query)
+	collection, err := a.readCollection(ctx)
+	if err != nil {
+		return 0, err
+	}
 
+	filter, err := a.query(query)
 	if err != nil {
 		return 0, err
 	}
 
+	count, err = collection.CountDocuments(ctx, a.filter(ctx, filter))
+
+	if err != nil {
+		return 0, translate(err)
+	}
+
 	return count, nil
 }
 
 func (a *adapter) List(ctx context.Context, dest interface{}) error {
-	cursor, err := a.client.collection.Find(ctx, bson.M{})
+	collection, err := a.readCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := collection.Find(ctx, a.filter(ctx, bson.M{}))
 	defer cursor.Close(ctx)
 
 	if err != nil {
-		return err
+		return translate(err)
 	}
 
 	err = cursor.All(ctx, dest)
 
-	return err
+	return translate(err)
 }
 
 func (a *adapter) ListWithFilter(ctx context.Context, query interface{}, args interface{}, dest interface{}) error {
-	cursor, err := a.client.collection.Find(ctx, query)
-	defer cursor.Close(ctx)
+	collection, err := a.readCollection(ctx)
+	if err != nil {
+		return err
+	}
 
+	filter, err := a.query(query)
 	if err != nil {
 		return err
 	}
 
+	cursor, err := collection.Find(ctx, a.filter(ctx, filter))
+	defer cursor.Close(ctx)
+
+	if err != nil {
+		return translate(err)
+	}
+
 	err = cursor.All(ctx, dest)
 
-	return err
+	return translate(err)
 }
 
-func (a *adapter) Remove(ctx context.Context, id uuid.UUID) error {
-	_, err := a.client.collection.DeleteOne(ctx, bson.M{"entity._id": id})
+func (a *adapter) ListWithOptions(ctx context.Context, query interface{}, queryOptions core.QueryOptions, dest interface{}) error {
+	collection, err := a.readCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	if query == nil {
+		query = bson.M{}
+	}
 
+	filter, err := a.query(query)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	opts := options.Find()
+
+	if len(queryOptions.OrderBy) > 0 {
+		sort := bson.D{}
+
+		for _, v := range queryOptions.OrderBy {
+			path, err := bsonPath(reflect.TypeOf(a.model), v.Field)
+			if err != nil {
+				return err
+			}
+
+			direction := 1
+
+			if v.Descending {
+				direction = -1
+			}
+
+			sort = append(sort, bson.E{Key: path, Value: direction})
+		}
+
+		opts.SetSort(sort)
+	}
+
+	if len(queryOptions.Fields) > 0 {
+		projection := bson.M{}
+
+		for _, v := range queryOptions.Fields {
+			path, err := bsonPath(reflect.TypeOf(a.model), v)
+			if err != nil {
+				return err
+			}
+
+			projection[path] = 1
+		}
+
+		opts.SetProjection(projection)
+	}
+
+	if queryOptions.Offset > 0 {
+		opts.SetSkip(int64(queryOptions.Offset))
+	}
+
+	if queryOptions.Limit > 0 {
+		opts.SetLimit(int64(queryOptions.Limit))
+	}
+
+	cursor, err := collection.Find(ctx, a.filter(ctx, filter), opts)
+	if err != nil {
+		return translate(err)
+	}
+
+	defer cursor.Close(ctx)
+
+	return translate(cursor.All(ctx, dest))
 }
 
-func (a *adapter) RemoveRange(ctx context.Context, ids []uuid.UUID) error {
-	for _, id := range ids {
-		_, err := a.client.collection.DeleteOne(ctx, bson.M{"entity._id": id})
+func (a *adapter) Iterate(ctx context.Context, query interface{}, fn func(entity core.Entitier) error) error {
+	collection, err := a.readCollection(ctx)
+	if err != nil {
+		return err
+	}
 
-		if err != nil {
+	if query == nil {
+		query = bson.M{}
+	}
+
+	filter, err := a.query(query)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := collection.Find(ctx, a.filter(ctx, filter), options.Find().SetBatchSize(iterateBatchSize))
+	if err != nil {
+		return translate(err)
+	}
+
+	defer cursor.Close(ctx)
+
+	modelType := reflect.TypeOf(a.model).Elem()
+
+	for cursor.Next(ctx) {
+		entity := reflect.New(modelType).Interface().(core.Entitier)
+
+		if err := cursor.Decode(entity); err != nil {
+			return err
+		}
+
+		if err := fn(entity); err != nil {
 			return err
 		}
 	}
 
+	return translate(cursor.Err())
+}
+
+func (a *adapter) Remove(ctx context.Context, id uuid.UUID) error {
+	collection, err := a.collection(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := collection.DeleteOne(ctx, a.filter(ctx, bson.M{"entity._id": id}))
+
+	if err != nil {
+		return translate(err)
+	}
+
+	if result.DeletedCount == 0 {
+		return notFound(fmt.Sprintf("%s is not found", id))
+	}
+
 	return nil
 }
 
-func (a *adapter) Add(ctx context.Context, entity core.Entitier) error {
-	_, err := a.client.collection.InsertOne(ctx, entity)
+func (a *adapter) RemoveRange(ctx context.Context, ids []uuid.UUID) error {
+	collection, err := a.collection(ctx)
+	if err != nil {
+		return err
+	}
+
+	unique := make(map[uuid.UUID]bool, len(ids))
+
+	for _, v := range ids {
+		unique[v] = true
+	}
+
+	filter := a.filter(ctx, bson.M{"entity._id": bson.M{"$in": ids}})
+
+	// none of them is removed when any entity is not found
+	count, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return translate(err)
+	}
+
+	if count != int64(len(unique)) {
+		return notFound(fmt.Sprintf("%d of %d entities are not found", int64(len(unique))-count, len(unique)))
+	}
 
+	_, err = collection.DeleteMany(ctx, filter)
+
+	return translate(err)
+}
+
+func (a *adapter) Add(ctx context.Context, entity core.Entitier) error {
+	collection, err := a.collection(ctx)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	_, err = collection.InsertOne(ctx, entity)
+
+	return translate(err)
 }
 
 func (a *adapter) AddRange(ctx context.Context, entities []core.Entitier) error {
+	collection, err := a.collection(ctx)
+	if err != nil {
+		return err
+	}
+
 	vals := make([]interface{}, len(entities))
 
 	for i, entity := range entities {
 		vals[i] = entity
 	}
 
-	_, err := a.client.collection.InsertMany(ctx, vals)
+	_, err = collection.InsertMany(ctx, vals)
+
+	return translate(err)
+}
 
+func (a *adapter) Update(ctx context.Context, entity core.Entitier) error {
+	collection, err := a.collection(ctx)
 	if err != nil {
 		return err
 	}
 
-	return nil
-}
-
-func (a *adapter) Update(ctx context.Context, entity core.Entitier) error {
-	return a.client.collection.FindOneAndReplace(ctx, bson.M{"entity._id": entity.GetID()}, entity).Err()
+	return a.replace(ctx, collection, entity)
 }
 
 func (a *adapter) UpdateRange(ctx context.Context, entities []core.Entitier) error {
+	collection, err := a.collection(ctx)
+	if err != nil {
+		return err
+	}
+
 	for _, entity := range entities {
-		err := a.client.collection.FindOneAndReplace(ctx, bson.M{"entity._id": entity.GetID()}, entity).Err()
+		err := a.replace(ctx, collection, entity)
 
 		if err != nil {
 			return err
@@ -279,3 +625,255 @@ func (a *adapter) UpdateRange(ctx context.Context, entities []core.Entitier) err
 
 	return nil
 }
+
+// Upsert inserts the entity or updates the existing document except its creation stamp
+func (a *adapter) Upsert(ctx context.Context, entity core.Entitier) (affected int64, err error) {
+	return a.UpsertRange(ctx, []core.Entitier{entity})
+}
+
+// UpsertRange writes the entities in one bulk, the existing documents are updated except their creation stamp
+func (a *adapter) UpsertRange(ctx context.Context, entities []core.Entitier) (affected int64, err error) {
+	collection, err := a.collection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := a.guardTenant(ctx, collection, entities); err != nil {
+		return 0, err
+	}
+
+	models := make([]mongo.WriteModel, 0, len(entities))
+
+	for _, entity := range entities {
+		update, err := a.upsertUpdate(entity)
+		if err != nil {
+			return 0, err
+		}
+
+		filter := bson.M{"entity._id": entity.GetID()}
+
+		// the deleted scope is ignored, the removed document is replaced
+		if tenantId, ok := core.TenantScope(ctx, a.model); ok {
+			filter[tenantField] = tenantId
+		}
+
+		models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true))
+	}
+
+	result, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, translate(err)
+	}
+
+	return result.MatchedCount + result.UpsertedCount, nil
+}
+
+// guardTenant rejects the ids owned by another tenant, the tenant filter of the upsert would not match them
+// and a second document of the same id would be inserted
+func (a *adapter) guardTenant(ctx context.Context, collection *mongo.Collection, entities []core.Entitier) error {
+	tenantId, ok := core.TenantScope(ctx, a.model)
+	if !ok {
+		return nil
+	}
+
+	ids := make(bson.A, len(entities))
+
+	for i, v := range entities {
+		ids[i] = v.GetID()
+	}
+
+	count, err := collection.CountDocuments(ctx, bson.M{
+		"entity._id": bson.M{"$in": ids},
+		tenantField:  bson.M{"$ne": tenantId},
+	})
+	if err != nil {
+		return translate(err)
+	}
+
+	if count > 0 {
+		return core.NewError(core.ErrConflict, core.CodeUniqueViolation, fmt.Sprintf("%d of %d entities belong to another tenant", count, len(ids)))
+	}
+
+	return nil
+}
+
+func (a *adapter) Patch(ctx context.Context, id uuid.UUID, fields map[string]interface{}) (affected int64, err error) {
+	collection, err := a.collection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	update, err := a.patchUpdate(fields)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := collection.UpdateOne(ctx, a.filter(ctx, bson.M{"entity._id": id}), update)
+	if err != nil {
+		return 0, translate(err)
+	}
+
+	return result.MatchedCount, nil
+}
+
+func (a *adapter) UpdateWhere(ctx context.Context, query interface{}, fields map[string]interface{}) (affected int64, err error) {
+	collection, err := a.collection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	update, err := a.patchUpdate(fields)
+	if err != nil {
+		return 0, err
+	}
+
+	filter, err := a.query(query)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := collection.UpdateMany(ctx, a.filter(ctx, filter), update)
+	if err != nil {
+		return 0, translate(err)
+	}
+
+	return result.MatchedCount, nil
+}
+
+func (a *adapter) RemoveWhere(ctx context.Context, query interface{}) (affected int64, err error) {
+	collection, err := a.collection(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	filter, err := a.query(query)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := collection.DeleteMany(ctx, a.filter(ctx, filter))
+	if err != nil {
+		return 0, translate(err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+// upsertUpdate sets every path of the entity, the creation stamp is set only on insert
+func (a *adapter) upsertUpdate(entity core.Entitier) (bson.M, error) {
+	raw, err := bson.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc bson.D
+
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	set := bson.M{}
+	flatten("", doc, set)
+
+	setOnInsert := bson.M{}
+
+	for _, v := range []string{"CreateUser", "CreatedAt"} {
+		path, err := bsonPath(reflect.TypeOf(a.model), v)
+		if err != nil {
+			return nil, err
+		}
+
+		setOnInsert[path] = set[path]
+		delete(set, path)
+	}
+
+	delete(set, "_id")
+	delete(set, "entity._id")
+
+	update := bson.M{"$set": set, "$setOnInsert": setOnInsert}
+
+	if _, ok := a.model.(core.Versioned); ok {
+		delete(set, versionField)
+		update["$inc"] = bson.M{versionField: 1}
+	}
+
+	return update, nil
+}
+
+// patchUpdate maps the fields to the paths, the version is incremented
+func (a *adapter) patchUpdate(fields map[string]interface{}) (bson.M, error) {
+	set := bson.M{}
+
+	for k, v := range fields {
+		path, err := bsonPath(reflect.TypeOf(a.model), k)
+		if err != nil {
+			return nil, err
+		}
+
+		set[path] = v
+	}
+
+	update := bson.M{"$set": set}
+
+	if _, ok := a.model.(core.Versioned); ok {
+		update["$inc"] = bson.M{versionField: 1}
+	}
+
+	return update, nil
+}
+
+// flatten converts the embedded documents to dotted paths, so the update keeps the other fields
+func flatten(prefix string, doc bson.D, set bson.M) {
+	for _, v := range doc {
+		path := prefix + v.Key
+
+		if embedded, ok := v.Value.(bson.D); ok && len(embedded) > 0 {
+			flatten(path+".", embedded, set)
+
+			continue
+		}
+
+		set[path] = v.Value
+	}
+}
+
+// replace increments the version conditionally when the entity is versioned
+func (a *adapter) replace(ctx context.Context, collection *mongo.Collection, entity core.Entitier) error {
+	filter := bson.M{"entity._id": entity.GetID()}
+
+	versioned, ok := entity.(core.Versioned)
+	if !ok {
+		err := collection.FindOneAndReplace(ctx, a.filter(ctx, filter), entity).Err()
+
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return notFound(fmt.Sprintf("%s is not found", entity.GetID()))
+		}
+
+		return translate(err)
+	}
+
+	expected := versioned.GetVersion()
+	versioned.SetVersion(expected + 1)
+
+	filter[versionField] = expected
+
+	err := collection.FindOneAndReplace(ctx, a.filter(ctx, filter), entity).Err()
+
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		err = fmt.Errorf("%w version %d is changed", core.ErrConflict, expected)
+
+		if count, countErr := collection.CountDocuments(ctx, a.filter(ctx, bson.M{"entity._id": entity.GetID()})); countErr != nil {
+			err = translate(countErr)
+		} else if count == 0 {
+			err = notFound(fmt.Sprintf("%s is not found", entity.GetID()))
+		}
+	} else {
+		err = translate(err)
+	}
+
+	if err != nil {
+		versioned.SetVersion(expected)
+	}
+
+	return err
+}
@@ -0,0 +1,185 @@
+package mongo
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// query translates the specification, the other queries are passed through
+func (a *adapter) query(query interface{}) (interface{}, error) {
+	spec, ok := query.(*core.Specification)
+	if !ok {
+		return query, nil
+	}
+
+	return a.toFilter(spec)
+}
+
+func (a *adapter) toFilter(spec *core.Specification) (bson.M, error) {
+	switch spec.Operator {
+	case core.OpAnd, core.OpOr:
+		if len(spec.Specs) == 0 {
+			return nil, fmt.Errorf("%w %s specification is empty", core.ErrBadRequest, spec.Operator)
+		}
+
+		filters := bson.A{}
+
+		for _, v := range spec.Specs {
+			filter, err := a.toFilter(v)
+			if err != nil {
+				return nil, err
+			}
+
+			filters = append(filters, filter)
+		}
+
+		return bson.M{"$" + string(spec.Operator): filters}, nil
+	case core.OpNot:
+		if len(spec.Specs) != 1 {
+			return nil, fmt.Errorf("%w not specification should have one", core.ErrBadRequest)
+		}
+
+		filter, err := a.toFilter(spec.Specs[0])
+		if err != nil {
+			return nil, err
+		}
+
+		// $not is only allowed on fields, $nor negates the whole filter
+		return bson.M{"$nor": bson.A{filter}}, nil
+	}
+
+	path, err := bsonPath(reflect.TypeOf(a.model), spec.Field)
+	if err != nil {
+		return nil, err
+	}
+
+	switch spec.Operator {
+	case core.OpEq:
+		return bson.M{path: spec.Value}, nil
+	case core.OpNe:
+		return bson.M{path: bson.M{"$ne": spec.Value}}, nil
+	case core.OpIn:
+		values := reflect.ValueOf(spec.Value)
+
+		if values.Kind() != reflect.Slice && values.Kind() != reflect.Array {
+			return nil, fmt.Errorf("%w in specification should have slice", core.ErrBadRequest)
+		}
+
+		in := bson.A{}
+
+		for i := 0; i < values.Len(); i++ {
+			in = append(in, values.Index(i).Interface())
+		}
+
+		return bson.M{path: bson.M{"$in": in}}, nil
+	case core.OpGt:
+		return bson.M{path: bson.M{"$gt": spec.Value}}, nil
+	case core.OpLt:
+		return bson.M{path: bson.M{"$lt": spec.Value}}, nil
+	case core.OpLike:
+		pattern, _ := spec.Value.(string)
+
+		return bson.M{path: primitive.Regex{Pattern: core.LikeToRegexp(pattern)}}, nil
+	default:
+		return nil, fmt.Errorf("%w unknown operator %s", core.ErrBadRequest, spec.Operator)
+	}
+}
+
+// bsonPath resolves the Go field name to its document path,
+// embedded structs are nested documents unless they are inline
+func bsonPath(typeOf reflect.Type, field string) (string, error) {
+	for typeOf.Kind() == reflect.Ptr {
+		typeOf = typeOf.Elem()
+	}
+
+	names := strings.Split(field, ".")
+
+	if path, ok := findPath(typeOf, names); ok {
+		return path, nil
+	}
+
+	return "", fmt.Errorf("%w unknown field %s", core.ErrBadRequest, field)
+}
+
+func findPath(typeOf reflect.Type, names []string) (string, bool) {
+	if typeOf.Kind() != reflect.Struct {
+		return "", false
+	}
+
+	if f, ok := typeOf.FieldByName(names[0]); ok && len(f.Index) == 1 {
+		key, inline := bsonKey(f)
+
+		if len(names) == 1 {
+			return key, true
+		}
+
+		path, ok := findPath(indirectType(f.Type), names[1:])
+		if !ok {
+			return "", false
+		}
+
+		if inline {
+			return path, true
+		}
+
+		return key + "." + path, true
+	}
+
+	// promoted fields of embedded structs
+	for i := 0; i < typeOf.NumField(); i++ {
+		f := typeOf.Field(i)
+
+		if !f.Anonymous {
+			continue
+		}
+
+		path, ok := findPath(indirectType(f.Type), names)
+		if !ok {
+			continue
+		}
+
+		key, inline := bsonKey(f)
+
+		if inline {
+			return path, true
+		}
+
+		return key + "." + path, true
+	}
+
+	return "", false
+}
+
+func bsonKey(f reflect.StructField) (string, bool) {
+	key := strings.ToLower(f.Name)
+	inline := false
+
+	if tag, ok := f.Tag.Lookup("bson"); ok {
+		parts := strings.Split(tag, ",")
+
+		if parts[0] != "" && parts[0] != "-" {
+			key = parts[0]
+		}
+
+		for _, v := range parts[1:] {
+			if v == "inline" {
+				inline = true
+			}
+		}
+	}
+
+	return key, inline
+}
+
+func indirectType(typeOf reflect.Type) reflect.Type {
+	for typeOf.Kind() == reflect.Ptr {
+		typeOf = typeOf.Elem()
+	}
+
+	return typeOf
+}
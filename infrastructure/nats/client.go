@@ -0,0 +1,80 @@
+package nats
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/nats-io/nats.go"
+)
+
+// clientKey identifies the client by the full configuration, the adapters of the same configuration share the client
+func (a *adapter) clientKey() (string, error) {
+	config, err := json.Marshal(a.settings)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(config)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (a *adapter) options() ([]nats.Option, error) {
+	settings := a.settings
+	opts := make([]nats.Option, 0)
+
+	if settings.Name != "" {
+		opts = append(opts, nats.Name(settings.Name))
+	}
+
+	if settings.Username != "" {
+		opts = append(opts, nats.UserInfo(settings.Username, settings.Password))
+	}
+
+	if settings.Token != "" {
+		opts = append(opts, nats.Token(settings.Token))
+	}
+
+	if settings.CredentialsFile != "" {
+		opts = append(opts, nats.UserCredentials(settings.CredentialsFile))
+	}
+
+	if settings.NKeySeedFile != "" {
+		opt, err := nats.NkeyOptionFromSeed(settings.NKeySeedFile)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, opt)
+	}
+
+	connection := settings.Connection
+
+	if connection.Timeout > 0 {
+		opts = append(opts, nats.Timeout(connection.Timeout))
+	}
+
+	if connection.ReconnectWait > 0 {
+		opts = append(opts, nats.ReconnectWait(connection.ReconnectWait))
+	}
+
+	if connection.MaxReconnects != 0 {
+		opts = append(opts, nats.MaxReconnects(connection.MaxReconnects))
+	}
+
+	if connection.PingInterval > 0 {
+		opts = append(opts, nats.PingInterval(connection.PingInterval))
+	}
+
+	tlsConfig, err := settings.TLS.ToTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig != nil {
+		opts = append(opts, nats.Secure(tlsConfig))
+	}
+
+	return opts, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/nats-io/nats.go"
 	cmap "github.com/orcaman/concurrent-map"
@@ -32,6 +33,25 @@ type clients struct {
 
 type NatsSettings struct {
 	Url string
+	// Name identifies the connection on the server
+	Name string
+	// Username and Password, Token, CredentialsFile of the user JWT or NKeySeedFile authenticate the connection
+	Username        string
+	Password        string
+	Token           string
+	CredentialsFile string
+	NKeySeedFile    string
+	Connection      NatsConnectionSettings
+	TLS             core.TLSSettings
+}
+
+// NatsConnectionSettings of the connection, zero keeps the driver defaults
+type NatsConnectionSettings struct {
+	Timeout       time.Duration
+	ReconnectWait time.Duration
+	// MaxReconnects is unlimited when it is negative
+	MaxReconnects int
+	PingInterval  time.Duration
 }
 
 var clientsInstance *clients
@@ -62,10 +82,20 @@ func (a *adapter) Connect(ctx context.Context) error {
 		return fmt.Errorf("url is required")
 	}
 
-	cli, ok := clientsInstance.clients[url]
+	key, err := a.clientKey()
+	if err != nil {
+		return err
+	}
+
+	cli, ok := clientsInstance.clients[key]
 
 	if !ok || !cli.isConnected {
-		natsClient, err := nats.Connect(url)
+		opts, err := a.options()
+		if err != nil {
+			return err
+		}
+
+		natsClient, err := nats.Connect(url, opts...)
 
 		if err != nil {
 			return err
@@ -76,13 +106,14 @@ func (a *adapter) Connect(ctx context.Context) error {
 			return err
 		}
 
-		handlers := cli.handlers
-
-		if handlers == nil {
+		var handlers cmap.ConcurrentMap
+		if cli == nil {
 			handlers = cmap.New()
+		} else {
+			handlers = cli.handlers
 		}
 
-		clientsInstance.clients[url] = &clientProxy{
+		clientsInstance.clients[key] = &clientProxy{
 			nats:        natsClient,
 			pubsubs:     cmap.New(),
 			handlers:    handlers,
@@ -90,7 +121,7 @@ func (a *adapter) Connect(ctx context.Context) error {
 		}
 	}
 
-	a.client = clientsInstance.clients[url]
+	a.client = clientsInstance.clients[key]
 
 	for _, k := range a.client.handlers.Keys() {
 		v, _ := a.client.handlers.Get(k)
@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// clientKey identifies the client by the full configuration, the adapters of the same configuration share the client
+func (a *adapter) clientKey() (string, error) {
+	if strings.TrimSpace(a.settings.Host) == "" && len(a.settings.SentinelAddrs) == 0 && len(a.settings.ClusterAddrs) == 0 {
+		return "", fmt.Errorf("host is required")
+	}
+
+	config, err := json.Marshal(a.settings)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(config)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (a *adapter) newClient() (redis.UniversalClient, error) {
+	settings := a.settings
+
+	tlsConfig, err := settings.TLS.ToTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(settings.ClusterAddrs) > 0:
+		if settings.DB != 0 {
+			return nil, fmt.Errorf("cluster does not support db %d", settings.DB)
+		}
+
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        settings.ClusterAddrs,
+			Username:     settings.Username,
+			Password:     settings.Password,
+			PoolSize:     settings.Pool.PoolSize,
+			MinIdleConns: settings.Pool.MinIdleConns,
+			MaxConnAge:   settings.Pool.MaxConnAge,
+			IdleTimeout:  settings.Pool.IdleTimeout,
+			PoolTimeout:  settings.Pool.PoolTimeout,
+			DialTimeout:  settings.Pool.DialTimeout,
+			TLSConfig:    tlsConfig,
+		}), nil
+	case len(settings.SentinelAddrs) > 0:
+		if strings.TrimSpace(settings.MasterName) == "" {
+			return nil, fmt.Errorf("masterName is required")
+		}
+
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       settings.MasterName,
+			SentinelAddrs:    settings.SentinelAddrs,
+			SentinelPassword: settings.SentinelPassword,
+			Username:         settings.Username,
+			Password:         settings.Password,
+			DB:               settings.DB,
+			PoolSize:         settings.Pool.PoolSize,
+			MinIdleConns:     settings.Pool.MinIdleConns,
+			MaxConnAge:       settings.Pool.MaxConnAge,
+			IdleTimeout:      settings.Pool.IdleTimeout,
+			PoolTimeout:      settings.Pool.PoolTimeout,
+			DialTimeout:      settings.Pool.DialTimeout,
+			TLSConfig:        tlsConfig,
+		}), nil
+	default:
+		return redis.NewClient(&redis.Options{
+			Addr:         settings.Host,
+			Username:     settings.Username,
+			Password:     settings.Password,
+			DB:           settings.DB,
+			PoolSize:     settings.Pool.PoolSize,
+			MinIdleConns: settings.Pool.MinIdleConns,
+			MaxConnAge:   settings.Pool.MaxConnAge,
+			IdleTimeout:  settings.Pool.IdleTimeout,
+			PoolTimeout:  settings.Pool.PoolTimeout,
+			DialTimeout:  settings.Pool.DialTimeout,
+			TLSConfig:    tlsConfig,
+		}), nil
+	}
+}
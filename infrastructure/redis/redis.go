@@ -4,9 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
-	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	cmap "github.com/orcaman/concurrent-map"
@@ -20,20 +19,51 @@ type adapter struct {
 }
 
 type clientProxy struct {
-	redis       *redis.Client
+	redis       redis.UniversalClient
 	pubsubs     cmap.ConcurrentMap
 	handlers    cmap.ConcurrentMap
 	isConnected bool
 }
 
+// incrementScript expires the counter only when it is created by the increment
+var incrementScript = redis.NewScript(`
+local v = redis.call('INCRBY', KEYS[1], ARGV[1])
+if v == tonumber(ARGV[1]) and tonumber(ARGV[2]) > 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return v
+`)
+
 type clients struct {
 	clients map[string]*clientProxy
 	sync.Mutex
 }
 
+// RedisSettings connects to the single node of Host, the Sentinel of MasterName or the Cluster of ClusterAddrs
 type RedisSettings struct {
 	Host     string
+	Username string
 	Password string
+	// DB is not supported by the Cluster
+	DB int
+	// MasterName and SentinelAddrs connect to the master through the Sentinel
+	MasterName       string
+	SentinelAddrs    []string
+	SentinelPassword string
+	// ClusterAddrs are the seed nodes of the Cluster
+	ClusterAddrs []string
+	Pool         RedisPoolSettings
+	TLS          core.TLSSettings
+}
+
+// RedisPoolSettings of the client, zero keeps the driver defaults
+type RedisPoolSettings struct {
+	PoolSize     int
+	MinIdleConns int
+	MaxConnAge   time.Duration
+	IdleTimeout  time.Duration
+	PoolTimeout  time.Duration
+	DialTimeout  time.Duration
 }
 
 var clientsInstance *clients
@@ -58,24 +88,18 @@ func (a *adapter) Connect(ctx context.Context) error {
 	clientsInstance.Lock()
 	defer clientsInstance.Unlock()
 
-	host := a.settings.Host
-
-	if strings.TrimSpace(host) == "" {
-		return fmt.Errorf("host is required")
+	key, err := a.clientKey()
+	if err != nil {
+		return err
 	}
 
-	password := a.settings.Password
-
-	cli, ok := clientsInstance.clients[host]
+	cli, ok := clientsInstance.clients[key]
 
 	if !ok || !cli.isConnected {
-		redisClient := redis.NewClient(&redis.Options{
-			Addr:     host,
-			Password: password,
-			DB:       0,
-		})
-
-		redisClient.Conn(ctx)
+		redisClient, err := a.newClient()
+		if err != nil {
+			return err
+		}
 
 		// Check context cancellation
 		if err := ctx.Err(); err != nil {
@@ -89,7 +113,7 @@ func (a *adapter) Connect(ctx context.Context) error {
 			handlers = cli.handlers
 		}
 
-		clientsInstance.clients[host] = &clientProxy{
+		clientsInstance.clients[key] = &clientProxy{
 			redis:       redisClient,
 			pubsubs:     cmap.New(),
 			handlers:    handlers,
@@ -97,7 +121,7 @@ func (a *adapter) Connect(ctx context.Context) error {
 		}
 	}
 
-	client := clientsInstance.clients[host]
+	client := clientsInstance.clients[key]
 
 	a.client = client
 
@@ -143,17 +167,35 @@ func (a *adapter) Get(ctx context.Context, key string, dest interface{}) error {
 }
 
 func (a *adapter) Set(ctx context.Context, key string, value interface{}) error {
+	return a.SetWithTTL(ctx, key, value, 0)
+}
+
+func (a *adapter) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
 	bytes, err := json.Marshal(value)
 
 	if err != nil {
 		return err
 	}
 
-	result := a.client.redis.Set(ctx, key, bytes, 0)
+	result := a.client.redis.Set(ctx, key, bytes, ttl)
 
 	return result.Err()
 }
 
+func (a *adapter) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	bytes, err := json.Marshal(value)
+
+	if err != nil {
+		return false, err
+	}
+
+	return a.client.redis.SetNX(ctx, key, bytes, ttl).Result()
+}
+
+func (a *adapter) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	return incrementScript.Run(ctx, a.client.redis, []string{key}, delta, ttl.Milliseconds()).Int64()
+}
+
 func (a *adapter) BatchSet(ctx context.Context, kvs []core.KV) error {
 	for _, v := range kvs {
 		err := a.Set(ctx, v.K, v.V)
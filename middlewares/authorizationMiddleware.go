@@ -0,0 +1,139 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jybbang/go-core-architecture/core"
+	cmap "github.com/orcaman/concurrent-map"
+)
+
+// RoleRequirer is implemented by the requests which need any of the roles
+type RoleRequirer interface {
+	RequiredRoles() []string
+}
+
+// PolicyRequirer is implemented by the requests which need all of the policies
+type PolicyRequirer interface {
+	RequiredPolicies() []string
+}
+
+// AuthorizationPolicy returns true when the principal is allowed to send the request
+type AuthorizationPolicy func(ctx context.Context, principal *core.Principal, request core.Request) bool
+
+type authorizationMiddleware struct {
+	core.Middleware
+	policies cmap.ConcurrentMap
+	requests cmap.ConcurrentMap
+}
+
+type authorizationRequirement struct {
+	roles    []string
+	policies []string
+}
+
+func NewAuthorizationMiddleware() *authorizationMiddleware {
+	return &authorizationMiddleware{
+		policies: cmap.New(),
+		requests: cmap.New(),
+	}
+}
+
+func (m *authorizationMiddleware) AddPolicy(name string, policy AuthorizationPolicy) *authorizationMiddleware {
+	if strings.TrimSpace(name) == "" {
+		panic("policy name is required")
+	}
+
+	if policy == nil {
+		panic("policy is required")
+	}
+
+	m.policies.Set(name, policy)
+
+	return m
+}
+
+// RequireRoles registers the roles for the request type, any of them is required
+func (m *authorizationMiddleware) RequireRoles(request core.Request, roles ...string) *authorizationMiddleware {
+	requirement := m.getRequirement(request)
+	requirement.roles = append(requirement.roles, roles...)
+
+	return m
+}
+
+// RequirePolicies registers the policies for the request type, all of them are required
+func (m *authorizationMiddleware) RequirePolicies(request core.Request, policies ...string) *authorizationMiddleware {
+	requirement := m.getRequirement(request)
+	requirement.policies = append(requirement.policies, policies...)
+
+	return m
+}
+
+func (m *authorizationMiddleware) Run(ctx context.Context, request core.Request) core.Result {
+	roles, policies := m.requirementsOf(request)
+
+	if len(roles) == 0 && len(policies) == 0 {
+		return m.NextWith(ctx, request)
+	}
+
+	principal, ok := core.PrincipalFromContext(ctx)
+	if !ok {
+		return core.Result{E: fmt.Errorf("%w principal is required", core.ErrUnauthorized)}
+	}
+
+	if len(roles) > 0 && !principal.IsInRole(roles...) {
+		return core.Result{E: fmt.Errorf("%w one of roles %v is required", core.ErrForbiddenAcccess, roles)}
+	}
+
+	for _, name := range policies {
+		value, ok := m.policies.Get(name)
+		if !ok {
+			return core.Result{E: fmt.Errorf("%w policy %s is not registered", core.ErrInternalServerError, name)}
+		}
+
+		if !value.(AuthorizationPolicy)(ctx, principal, request) {
+			return core.Result{E: fmt.Errorf("%w policy %s is not satisfied", core.ErrForbiddenAcccess, name)}
+		}
+	}
+
+	return m.NextWith(ctx, request)
+}
+
+func (m *authorizationMiddleware) getRequirement(request core.Request) *authorizationRequirement {
+	if request == nil {
+		panic("request is required")
+	}
+
+	typeName := reflect.TypeOf(request).Elem().Name()
+
+	m.requests.SetIfAbsent(typeName, new(authorizationRequirement))
+
+	value, _ := m.requests.Get(typeName)
+
+	return value.(*authorizationRequirement)
+}
+
+func (m *authorizationMiddleware) requirementsOf(request core.Request) ([]string, []string) {
+	roles := make([]string, 0)
+	policies := make([]string, 0)
+
+	typeName := reflect.TypeOf(request).Elem().Name()
+
+	if value, ok := m.requests.Get(typeName); ok {
+		requirement := value.(*authorizationRequirement)
+		roles = append(roles, requirement.roles...)
+		policies = append(policies, requirement.policies...)
+	}
+
+	if requirer, ok := request.(RoleRequirer); ok {
+		roles = append(roles, requirer.RequiredRoles()...)
+	}
+
+	if requirer, ok := request.(PolicyRequirer); ok {
+		policies = append(policies, requirer.RequiredPolicies()...)
+	}
+
+	return roles, policies
+}
@@ -0,0 +1,133 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/jybbang/go-core-architecture/core"
+	cmap "github.com/orcaman/concurrent-map"
+	"github.com/sony/gobreaker"
+)
+
+var ErrBulkheadFull = fmt.Errorf("%w: bulkhead is full", gobreaker.ErrTooManyRequests)
+
+type bulkheadMiddleware struct {
+	core.Middleware
+	settings  BulkheadSettings
+	requests  cmap.ConcurrentMap
+	bulkheads cmap.ConcurrentMap
+}
+
+type BulkheadSettings struct {
+	MaxConcurrentRequests int
+	MaxQueueLength        int
+	QueueTimeout          time.Duration
+}
+
+type bulkhead struct {
+	slots    chan struct{}
+	admitted chan struct{}
+	timeout  time.Duration
+}
+
+func NewBulkheadMiddleware(settings BulkheadSettings) *bulkheadMiddleware {
+	if settings.MaxConcurrentRequests < 1 {
+		panic("maxConcurrentRequests is required")
+	}
+
+	return &bulkheadMiddleware{
+		settings:  settings,
+		requests:  cmap.New(),
+		bulkheads: cmap.New(),
+	}
+}
+
+// ForRequest overrides the default settings for the given request type
+func (m *bulkheadMiddleware) ForRequest(request core.Request, settings BulkheadSettings) *bulkheadMiddleware {
+	if request == nil {
+		panic("request is required")
+	}
+
+	if settings.MaxConcurrentRequests < 1 {
+		panic("maxConcurrentRequests is required")
+	}
+
+	typeName := reflect.TypeOf(request).Elem().Name()
+
+	m.requests.Set(typeName, settings)
+
+	return m
+}
+
+func (m *bulkheadMiddleware) Run(ctx context.Context, request core.Request) core.Result {
+	typeName := reflect.TypeOf(request).Elem().Name()
+
+	b := m.getBulkhead(typeName)
+
+	// admission, requests beyond the running and the queued are rejected immediately
+	select {
+	case b.admitted <- struct{}{}:
+	default:
+		return core.Result{E: ErrBulkheadFull}
+	}
+
+	defer func() { <-b.admitted }()
+
+	if err := b.acquire(ctx); err != nil {
+		return core.Result{E: err}
+	}
+
+	defer func() { <-b.slots }()
+
+	return m.NextWith(ctx, request)
+}
+
+func (m *bulkheadMiddleware) getBulkhead(typeName string) *bulkhead {
+	if value, ok := m.bulkheads.Get(typeName); ok {
+		return value.(*bulkhead)
+	}
+
+	settings := m.settings
+
+	if value, ok := m.requests.Get(typeName); ok {
+		settings = value.(BulkheadSettings)
+	}
+
+	m.bulkheads.SetIfAbsent(typeName, &bulkhead{
+		slots:    make(chan struct{}, settings.MaxConcurrentRequests),
+		admitted: make(chan struct{}, settings.MaxConcurrentRequests+settings.MaxQueueLength),
+		timeout:  settings.QueueTimeout,
+	})
+
+	value, _ := m.bulkheads.Get(typeName)
+
+	return value.(*bulkhead)
+}
+
+func (b *bulkhead) acquire(ctx context.Context) error {
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	var timeout <-chan time.Time
+
+	if b.timeout > 0 {
+		timer := time.NewTimer(b.timeout)
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	case <-timeout:
+		return ErrBulkheadFull
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
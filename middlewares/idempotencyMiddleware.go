@@ -0,0 +1,350 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jybbang/go-core-architecture/core"
+	cmap "github.com/orcaman/concurrent-map"
+	"gopkg.in/jeevatkm/go-model.v1"
+)
+
+// IdempotencyKeyer can be implemented by requests which carry their own idempotency key
+type IdempotencyKeyer interface {
+	GetIdempotencyKey() string
+}
+
+type idempotencyMiddleware struct {
+	core.Middleware
+	settings IdempotencySettings
+	inflight cmap.ConcurrentMap
+	codec    *resultCodec
+}
+
+type IdempotencySettings struct {
+	KeyInContext string        `model:",omitempty"`
+	Expiration   time.Duration `model:",omitempty"`
+	// the pending record expires after the lease so that a crashed process does not block the key,
+	// it should exceed the longest handler
+	PendingLease    time.Duration `model:",omitempty"`
+	WaitTimeout     time.Duration `model:",omitempty"`
+	PollingInterval time.Duration `model:",omitempty"`
+	// duplicates wait for the in flight request instead of failing with ErrConflict
+	WaitInFlight bool
+}
+
+const (
+	idempotencyPending   = "pending"
+	idempotencyCompleted = "completed"
+)
+
+type idempotencyRecord struct {
+	Status    string
+	Type      string
+	Value     []byte
+	ExpiresAt time.Time
+}
+
+func NewIdempotencyMiddleware() *idempotencyMiddleware {
+	return newIdempotencyMiddleware(newIdempotencySettings())
+}
+
+func NewIdempotencyMiddlewareWithSettings(settings IdempotencySettings) *idempotencyMiddleware {
+	s := newIdempotencySettings()
+
+	err := model.Copy(&s, settings)
+
+	if err != nil {
+		panic(fmt.Errorf("settings mapping errors occurred: %v", err))
+	}
+
+	return newIdempotencyMiddleware(s)
+}
+
+func newIdempotencySettings() IdempotencySettings {
+	return IdempotencySettings{
+		KeyInContext:    "Idempotency-Key",
+		Expiration:      time.Duration(24 * time.Hour),
+		PendingLease:    time.Duration(1 * time.Minute),
+		WaitTimeout:     time.Duration(10 * time.Second),
+		PollingInterval: time.Duration(100 * time.Millisecond),
+	}
+}
+
+func newIdempotencyMiddleware(settings IdempotencySettings) *idempotencyMiddleware {
+	settings.KeyInContext = http.CanonicalHeaderKey(settings.KeyInContext)
+
+	return &idempotencyMiddleware{
+		settings: settings,
+		inflight: cmap.New(),
+		codec:    newResultCodec(),
+	}
+}
+
+// RegisterResult registers the result type of the request,
+// the stored results of the request types which are not handled by this process yet would fail closed otherwise
+func (m *idempotencyMiddleware) RegisterResult(request core.Request, result interface{}) *idempotencyMiddleware {
+	if request == nil {
+		panic("request is required")
+	}
+
+	if result == nil {
+		panic("result is required")
+	}
+
+	typeName := reflect.TypeOf(request).Elem().Name()
+
+	m.codec.register(typeName, result)
+
+	return m
+}
+
+func (m *idempotencyMiddleware) Run(ctx context.Context, request core.Request) core.Result {
+	key := m.getIdempotencyKey(ctx, request)
+
+	if key == "" {
+		return m.NextWith(ctx, request)
+	}
+
+	typeName := reflect.TypeOf(request).Elem().Name()
+	principal := ""
+
+	if p, ok := core.PrincipalFromContext(ctx); ok {
+		principal = p.UserId
+	}
+
+	// the state service prefixes the tenant, the key is scoped by the principal
+	// so that another user never receives the result of the same key
+	stateKey := "idempotency:" + typeName + ":" + principal + ":" + key
+
+	tenantId, _ := core.TenantFromContext(ctx)
+	inflightKey := tenantId + ":" + stateKey
+
+	done := make(chan struct{})
+
+	// in process duplicates are detected without a round trip to the state service
+	if !m.inflight.SetIfAbsent(inflightKey, done) {
+		return m.waitInFlight(ctx, typeName, inflightKey, stateKey)
+	}
+
+	defer func() {
+		m.inflight.Remove(inflightKey)
+		close(done)
+	}()
+
+	acquired, err := m.acquire(ctx, stateKey)
+
+	if err != nil {
+		return core.Result{E: err}
+	}
+
+	if !acquired {
+		record, err := m.getRecord(ctx, stateKey)
+
+		if err != nil {
+			return core.Result{E: err}
+		}
+
+		if record != nil && record.Status == idempotencyCompleted {
+			return m.toResult(typeName, record)
+		}
+
+		return m.pollInFlight(ctx, typeName, stateKey)
+	}
+
+	result := m.NextWith(ctx, request)
+
+	// the record is written even when the request is canceled meanwhile,
+	// the values of ctx are kept because the state service scopes the keys by the tenant
+	detachedCtx := detached{ctx}
+
+	// failures are not remembered so that the client can retry
+	if result.E != nil {
+		core.GetStateService().Delete(detachedCtx, stateKey)
+
+		return result
+	}
+
+	encoded, err := m.codec.encode(typeName, result)
+
+	if err != nil {
+		core.GetStateService().Delete(detachedCtx, stateKey)
+
+		return result
+	}
+
+	completed := &idempotencyRecord{
+		Status:    idempotencyCompleted,
+		Type:      encoded.Type,
+		Value:     encoded.Value,
+		ExpiresAt: time.Now().Add(m.settings.Expiration),
+	}
+
+	core.GetStateService().SetWithTTL(detachedCtx, stateKey, completed, m.settings.Expiration)
+
+	return result
+}
+
+// acquire sets the pending record atomically across the processes, false means a duplicate owns the key
+func (m *idempotencyMiddleware) acquire(ctx context.Context, stateKey string) (bool, error) {
+	pending := &idempotencyRecord{
+		Status:    idempotencyPending,
+		ExpiresAt: time.Now().Add(m.settings.PendingLease),
+	}
+
+	states := core.GetStateService()
+
+	result := states.SetIfAbsent(ctx, stateKey, pending, m.settings.PendingLease)
+
+	if result.E != nil {
+		return false, result.E
+	}
+
+	if result.V == true {
+		return true, nil
+	}
+
+	// the adapters without ttl keep the expired record, which is dropped once
+	record, err := m.getRecord(ctx, stateKey)
+
+	if err != nil || record != nil {
+		return false, err
+	}
+
+	states.Delete(ctx, stateKey)
+
+	result = states.SetIfAbsent(ctx, stateKey, pending, m.settings.PendingLease)
+
+	return result.V == true, result.E
+}
+
+func (m *idempotencyMiddleware) getIdempotencyKey(ctx context.Context, request core.Request) string {
+	if keyer, ok := request.(IdempotencyKeyer); ok {
+		if key := strings.TrimSpace(keyer.GetIdempotencyKey()); key != "" {
+			return key
+		}
+	}
+
+	key, _ := ctx.Value(m.settings.KeyInContext).(string)
+
+	return strings.TrimSpace(key)
+}
+
+func (m *idempotencyMiddleware) getRecord(ctx context.Context, stateKey string) (*idempotencyRecord, error) {
+	states := core.GetStateService()
+
+	// not found would be counted as a failure by the circuit breaker
+	if result := states.Has(ctx, stateKey); result.E != nil || result.V != true {
+		return nil, result.E
+	}
+
+	record := new(idempotencyRecord)
+
+	result := states.Get(ctx, stateKey, record)
+
+	if errors.Is(result.E, core.ErrNotFound) {
+		return nil, nil
+	}
+
+	if result.E != nil {
+		return nil, result.E
+	}
+
+	if time.Now().After(record.ExpiresAt) {
+		return nil, nil
+	}
+
+	return record, nil
+}
+
+func (m *idempotencyMiddleware) waitInFlight(ctx context.Context, typeName string, inflightKey string, stateKey string) core.Result {
+	if !m.settings.WaitInFlight {
+		return core.Result{E: fmt.Errorf("%w idempotent request is in flight", core.ErrConflict)}
+	}
+
+	if value, ok := m.inflight.Get(inflightKey); ok {
+		timer := time.NewTimer(m.settings.WaitTimeout)
+		defer timer.Stop()
+
+		select {
+		case <-value.(chan struct{}):
+		case <-timer.C:
+			return core.Result{E: fmt.Errorf("%w idempotent request is in flight", core.ErrConflict)}
+		case <-ctx.Done():
+			return core.Result{E: ctx.Err()}
+		}
+	}
+
+	record, err := m.getRecord(ctx, stateKey)
+
+	if err != nil {
+		return core.Result{E: err}
+	}
+
+	if record == nil || record.Status != idempotencyCompleted {
+		return core.Result{E: fmt.Errorf("%w idempotent request is not completed", core.ErrConflict)}
+	}
+
+	return m.toResult(typeName, record)
+}
+
+// pollInFlight waits for a duplicate which is in flight in another process
+func (m *idempotencyMiddleware) pollInFlight(ctx context.Context, typeName string, stateKey string) core.Result {
+	if !m.settings.WaitInFlight {
+		return core.Result{E: fmt.Errorf("%w idempotent request is in flight", core.ErrConflict)}
+	}
+
+	deadline := time.Now().Add(m.settings.WaitTimeout)
+
+	for time.Now().Before(deadline) {
+		select {
+		case <-time.After(m.settings.PollingInterval):
+		case <-ctx.Done():
+			return core.Result{E: ctx.Err()}
+		}
+
+		record, err := m.getRecord(ctx, stateKey)
+
+		if err != nil {
+			return core.Result{E: err}
+		}
+
+		if record == nil {
+			return core.Result{E: fmt.Errorf("%w idempotent request is not completed", core.ErrConflict)}
+		}
+
+		if record.Status == idempotencyCompleted {
+			return m.toResult(typeName, record)
+		}
+	}
+
+	return core.Result{E: fmt.Errorf("%w idempotent request is in flight", core.ErrConflict)}
+}
+
+func (m *idempotencyMiddleware) toResult(typeName string, record *idempotencyRecord) core.Result {
+	result, _ := m.codec.decode(typeName, encodedResult{Type: record.Type, Value: record.Value})
+
+	return result
+}
+
+// detached keeps the values of the request but is never canceled
+type detached struct {
+	context.Context
+}
+
+func (detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detached) Done() <-chan struct{} {
+	return nil
+}
+
+func (detached) Err() error {
+	return nil
+}
@@ -20,5 +20,5 @@ func NewLogMiddleware(logger *zap.Logger) *logMiddleware {
 
 func (m *logMiddleware) Run(ctx context.Context, request core.Request) core.Result {
 	m.log.Info("send request log", zap.Reflect("request", request))
-	return m.Next()
+	return m.NextWith(ctx, request)
 }
@@ -19,7 +19,7 @@ func NewPanicRecoverMiddleware(callback func(interface{})) *panicRecoverMiddlewa
 
 func (m *panicRecoverMiddleware) Run(ctx context.Context, request core.Request) core.Result {
 	defer m.panicRecover()
-	return m.Next()
+	return m.NextWith(ctx, request)
 }
 
 func (m *panicRecoverMiddleware) panicRecover() {
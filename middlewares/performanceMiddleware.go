@@ -23,7 +23,7 @@ func NewPerformanceMiddleware(logger *zap.Logger, limitDuration time.Duration) *
 
 func (m *performanceMiddleware) Run(ctx context.Context, request core.Request) core.Result {
 	defer m.timeMeasurement(time.Now(), request)
-	return m.Next()
+	return m.NextWith(ctx, request)
 }
 
 func (m *performanceMiddleware) timeMeasurement(start time.Time, request core.Request) {
@@ -15,7 +15,7 @@ func NewPublishDomainEventsMiddleware() *publishDomainEventsMiddleware {
 }
 
 func (m *publishDomainEventsMiddleware) Run(ctx context.Context, request core.Request) core.Result {
-	result := m.Next()
+	result := m.NextWith(ctx, request)
 	core.GetEventBus().PublishDomainEvents(ctx)
 	return result
 }
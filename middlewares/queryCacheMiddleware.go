@@ -0,0 +1,298 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jybbang/go-core-architecture/core"
+	cmap "github.com/orcaman/concurrent-map"
+	"github.com/patrickmn/go-cache"
+	"gopkg.in/jeevatkm/go-model.v1"
+)
+
+type queryCacheMiddleware struct {
+	core.Middleware
+	settings      QueryCacheSettings
+	cache         *cache.Cache
+	queries       cmap.ConcurrentMap
+	invalidations cmap.ConcurrentMap
+	codec         *resultCodec
+}
+
+type QueryCacheSettings struct {
+	Expiration      time.Duration `model:",omitempty"`
+	CleanupInterval time.Duration `model:",omitempty"`
+	// caches through the state service instead of the in process cache
+	UseStateService bool
+}
+
+type cachedQuery struct {
+	expiration   time.Duration
+	tags         []string
+	perPrincipal bool
+}
+
+type queryCacheTag struct {
+	Version string
+}
+
+type queryCacheEntry struct {
+	Type      string
+	Value     []byte
+	ExpiresAt time.Time
+}
+
+func NewQueryCacheMiddleware() *queryCacheMiddleware {
+	return newQueryCacheMiddleware(newQueryCacheSettings())
+}
+
+func NewQueryCacheMiddlewareWithSettings(settings QueryCacheSettings) *queryCacheMiddleware {
+	s := newQueryCacheSettings()
+
+	err := model.Copy(&s, settings)
+
+	if err != nil {
+		panic(fmt.Errorf("settings mapping errors occurred: %v", err))
+	}
+
+	return newQueryCacheMiddleware(s)
+}
+
+func newQueryCacheSettings() QueryCacheSettings {
+	return QueryCacheSettings{
+		Expiration:      time.Duration(5 * time.Minute),
+		CleanupInterval: time.Duration(10 * time.Minute),
+	}
+}
+
+func newQueryCacheMiddleware(settings QueryCacheSettings) *queryCacheMiddleware {
+	return &queryCacheMiddleware{
+		settings:      settings,
+		cache:         cache.New(settings.Expiration, settings.CleanupInterval),
+		queries:       cmap.New(),
+		invalidations: cmap.New(),
+		codec:         newResultCodec(),
+	}
+}
+
+// AddQuery enables caching for the request type, zero expiration uses the default one.
+// The results are cached per tenant of ctx
+func (m *queryCacheMiddleware) AddQuery(request core.Request, expiration time.Duration, tags ...string) *queryCacheMiddleware {
+	return m.addQuery(request, expiration, false, tags)
+}
+
+// AddPrincipalQuery enables caching for the request type whose results depend on the caller,
+// the results are cached per tenant and per principal of ctx
+func (m *queryCacheMiddleware) AddPrincipalQuery(request core.Request, expiration time.Duration, tags ...string) *queryCacheMiddleware {
+	return m.addQuery(request, expiration, true, tags)
+}
+
+func (m *queryCacheMiddleware) addQuery(request core.Request, expiration time.Duration, perPrincipal bool, tags []string) *queryCacheMiddleware {
+	if request == nil {
+		panic("request is required")
+	}
+
+	if expiration <= 0 {
+		expiration = m.settings.Expiration
+	}
+
+	typeName := reflect.TypeOf(request).Elem().Name()
+
+	m.queries.Set(typeName, cachedQuery{
+		expiration:   expiration,
+		tags:         tags,
+		perPrincipal: perPrincipal,
+	})
+
+	return m
+}
+
+// InvalidateOn invalidates the tags when the domain event is published on the event bus,
+// the middleware should be registered with eventBus.AddDomainEventListener(m.OnDomainEventPublished)
+func (m *queryCacheMiddleware) InvalidateOn(event core.DomainEventer, tags ...string) *queryCacheMiddleware {
+	if event == nil {
+		panic("event is required")
+	}
+
+	if len(tags) == 0 {
+		panic("tags is required")
+	}
+
+	typeName := reflect.TypeOf(event).Elem().Name()
+
+	m.invalidations.Set(typeName, tags)
+
+	return m
+}
+
+func (m *queryCacheMiddleware) OnDomainEventPublished(ctx context.Context, event core.DomainEventer) {
+	typeName := reflect.TypeOf(event).Elem().Name()
+
+	if tags, ok := m.invalidations.Get(typeName); ok {
+		m.Invalidate(ctx, tags.([]string)...)
+	}
+}
+
+// Invalidate makes every cached query with the tags stale by bumping the tag versions
+func (m *queryCacheMiddleware) Invalidate(ctx context.Context, tags ...string) {
+	version := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	for _, tag := range tags {
+		key := "querycache:tag:" + tag
+
+		if m.settings.UseStateService {
+			core.GetStateService().Set(ctx, key, &queryCacheTag{Version: version})
+		} else {
+			m.cache.Set(key, version, cache.NoExpiration)
+		}
+	}
+}
+
+func (m *queryCacheMiddleware) Run(ctx context.Context, request core.Request) core.Result {
+	typeName := reflect.TypeOf(request).Elem().Name()
+
+	value, ok := m.queries.Get(typeName)
+
+	if !ok {
+		return m.NextWith(ctx, request)
+	}
+
+	query := value.(cachedQuery)
+
+	key, err := m.getCacheKey(ctx, typeName, request, query)
+
+	if err != nil {
+		return m.NextWith(ctx, request)
+	}
+
+	if cached, ok := m.get(ctx, key); ok {
+		// the result of an unknown type is a cache miss
+		if result, ok := m.codec.decode(typeName, cached); ok {
+			return result
+		}
+	}
+
+	result := m.NextWith(ctx, request)
+
+	if result.E != nil {
+		return result
+	}
+
+	if cached, err := m.codec.encode(typeName, result); err == nil {
+		m.set(ctx, key, cached, query.expiration)
+	}
+
+	return result
+}
+
+func (m *queryCacheMiddleware) getCacheKey(ctx context.Context, typeName string, request core.Request, query cachedQuery) (string, error) {
+	bytes, err := json.Marshal(request)
+
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	hash.Write(bytes)
+
+	// the same request of another tenant or caller must not hit the cached result
+	if tenantId, ok := core.TenantFromContext(ctx); ok {
+		hash.Write([]byte("tenant=" + tenantId))
+	}
+
+	if query.perPrincipal {
+		principal, ok := core.PrincipalFromContext(ctx)
+		if !ok {
+			return "", fmt.Errorf("%w principal is required", core.ErrUnauthorized)
+		}
+
+		hash.Write([]byte("principal=" + principal.UserId))
+	}
+
+	tags := append([]string(nil), query.tags...)
+	sort.Strings(tags)
+
+	for _, tag := range tags {
+		hash.Write([]byte(tag + "=" + m.getTagVersion(ctx, tag)))
+	}
+
+	return "querycache:" + typeName + ":" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (m *queryCacheMiddleware) getTagVersion(ctx context.Context, tag string) string {
+	key := "querycache:tag:" + tag
+
+	if !m.settings.UseStateService {
+		if version, ok := m.cache.Get(key); ok {
+			return version.(string)
+		}
+
+		return ""
+	}
+
+	states := core.GetStateService()
+
+	if result := states.Has(ctx, key); result.V != true {
+		return ""
+	}
+
+	version := new(queryCacheTag)
+
+	if result := states.Get(ctx, key, version); result.E != nil {
+		return ""
+	}
+
+	return version.Version
+}
+
+func (m *queryCacheMiddleware) get(ctx context.Context, key string) (encodedResult, bool) {
+	if !m.settings.UseStateService {
+		if value, ok := m.cache.Get(key); ok {
+			return value.(encodedResult), true
+		}
+
+		return encodedResult{}, false
+	}
+
+	states := core.GetStateService()
+
+	// not found would be counted as a failure by the circuit breaker
+	if result := states.Has(ctx, key); result.V != true {
+		return encodedResult{}, false
+	}
+
+	entry := new(queryCacheEntry)
+
+	if result := states.Get(ctx, key, entry); result.E != nil {
+		return encodedResult{}, false
+	}
+
+	if time.Now().After(entry.ExpiresAt) {
+		states.Delete(ctx, key)
+
+		return encodedResult{}, false
+	}
+
+	return encodedResult{Type: entry.Type, Value: entry.Value}, true
+}
+
+func (m *queryCacheMiddleware) set(ctx context.Context, key string, value encodedResult, expiration time.Duration) {
+	if !m.settings.UseStateService {
+		m.cache.Set(key, value, expiration)
+
+		return
+	}
+
+	core.GetStateService().SetWithTTL(ctx, key, &queryCacheEntry{
+		Type:      value.Type,
+		Value:     value.Value,
+		ExpiresAt: time.Now().Add(expiration),
+	}, expiration)
+}
@@ -0,0 +1,247 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/jybbang/go-core-architecture/core"
+	cmap "github.com/orcaman/concurrent-map"
+	"github.com/sony/gobreaker"
+)
+
+var ErrRateLimited = fmt.Errorf("%w: rate limit exceeded", gobreaker.ErrTooManyRequests)
+
+type rateLimitMiddleware struct {
+	core.Middleware
+	settings RateLimitSettings
+	requests cmap.ConcurrentMap
+	buckets  cmap.ConcurrentMap
+	sweptAt  time.Time
+	sweep    sync.Mutex
+}
+
+type RateLimitSettings struct {
+	// tokens refilled per second
+	Rate  float64
+	Burst int
+	// optional, limits by the extracted key (e.g. tenant id) instead of only by request type
+	KeySelector func(ctx context.Context, request core.Request) string
+	// optional, limits across the processes by the atomic counter of the state service,
+	// which allows Burst requests in each fixed window of Burst/Rate seconds
+	UseStateService bool
+	// optional, limits by the bucket of this process when the state service fails instead of failing the request
+	FallbackToLocal bool
+	// optional, evicts the local buckets idle for longer, which are full again then,
+	// the buckets are swept at this interval by the default settings
+	IdleTimeout time.Duration
+}
+
+type tokenBucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+type localBucket struct {
+	tokenBucket
+	sync.Mutex
+	idleTimeout time.Duration
+	evicted     bool
+}
+
+func NewRateLimitMiddleware(settings RateLimitSettings) *rateLimitMiddleware {
+	if settings.Rate <= 0 {
+		panic("rate is required")
+	}
+
+	if settings.Burst < 1 {
+		settings.Burst = 1
+	}
+
+	if settings.IdleTimeout <= 0 {
+		settings.IdleTimeout = time.Minute
+	}
+
+	return &rateLimitMiddleware{
+		settings: settings,
+		requests: cmap.New(),
+		buckets:  cmap.New(),
+	}
+}
+
+// ForRequest overrides the default settings for the given request type
+func (m *rateLimitMiddleware) ForRequest(request core.Request, settings RateLimitSettings) *rateLimitMiddleware {
+	if request == nil {
+		panic("request is required")
+	}
+
+	if settings.Rate <= 0 {
+		panic("rate is required")
+	}
+
+	if settings.Burst < 1 {
+		settings.Burst = 1
+	}
+
+	if settings.IdleTimeout <= 0 {
+		settings.IdleTimeout = time.Minute
+	}
+
+	typeName := reflect.TypeOf(request).Elem().Name()
+
+	m.requests.Set(typeName, settings)
+
+	return m
+}
+
+func (m *rateLimitMiddleware) Run(ctx context.Context, request core.Request) core.Result {
+	typeName := reflect.TypeOf(request).Elem().Name()
+
+	settings := m.settings
+
+	if value, ok := m.requests.Get(typeName); ok {
+		settings = value.(RateLimitSettings)
+	}
+
+	key := typeName
+
+	if settings.KeySelector != nil {
+		key = key + ":" + settings.KeySelector(ctx, request)
+	}
+
+	ok, err := m.take(ctx, key, settings)
+
+	if err != nil {
+		return core.Result{E: err}
+	}
+
+	if !ok {
+		return core.Result{E: ErrRateLimited}
+	}
+
+	return m.NextWith(ctx, request)
+}
+
+func (m *rateLimitMiddleware) take(ctx context.Context, key string, settings RateLimitSettings) (bool, error) {
+	if settings.UseStateService {
+		ok, err := m.takeShared(ctx, key, settings)
+
+		if err == nil || !settings.FallbackToLocal {
+			return ok, err
+		}
+	}
+
+	now := time.Now()
+
+	m.sweepIdle(now)
+
+	for {
+		m.buckets.SetIfAbsent(key, newLocalBucket(settings))
+
+		value, _ := m.buckets.Get(key)
+
+		local := value.(*localBucket)
+
+		local.Lock()
+
+		// the bucket is swept after it is got, the next one is taken instead
+		if local.evicted {
+			local.Unlock()
+			continue
+		}
+
+		ok := local.take(settings, now)
+
+		local.Unlock()
+
+		return ok, nil
+	}
+}
+
+// sweepIdle evicts the local buckets idle for longer than their idle timeout once in the interval
+func (m *rateLimitMiddleware) sweepIdle(now time.Time) {
+	m.sweep.Lock()
+
+	if now.Sub(m.sweptAt) < m.settings.IdleTimeout {
+		m.sweep.Unlock()
+		return
+	}
+
+	m.sweptAt = now
+
+	m.sweep.Unlock()
+
+	for _, key := range m.buckets.Keys() {
+		m.buckets.RemoveCb(key, func(key string, v interface{}, exists bool) bool {
+			if !exists {
+				return false
+			}
+
+			local := v.(*localBucket)
+
+			local.Lock()
+			defer local.Unlock()
+
+			local.evicted = now.Sub(local.UpdatedAt) >= local.idleTimeout
+
+			return local.evicted
+		})
+	}
+}
+
+// Buckets returns the count of the local buckets which are not evicted yet
+func (m *rateLimitMiddleware) Buckets() int {
+	return m.buckets.Count()
+}
+
+func (m *rateLimitMiddleware) takeShared(ctx context.Context, key string, settings RateLimitSettings) (bool, error) {
+	window := time.Duration(float64(settings.Burst) / settings.Rate * float64(time.Second))
+
+	stateKey := fmt.Sprintf("ratelimit:%s:%d", key, time.Now().UnixNano()/int64(window))
+
+	result := core.GetStateService().Increment(ctx, stateKey, 1, window)
+
+	if result.E != nil {
+		return false, result.E
+	}
+
+	return result.V.(int64) <= int64(settings.Burst), nil
+}
+
+// newLocalBucket is never evicted before it is full again
+func newLocalBucket(settings RateLimitSettings) *localBucket {
+	refill := time.Duration(float64(settings.Burst) / settings.Rate * float64(time.Second))
+
+	idleTimeout := settings.IdleTimeout
+
+	if idleTimeout < refill {
+		idleTimeout = refill
+	}
+
+	return &localBucket{
+		idleTimeout: idleTimeout,
+	}
+}
+
+func (b *tokenBucket) take(settings RateLimitSettings, now time.Time) bool {
+	if b.UpdatedAt.IsZero() {
+		b.Tokens = float64(settings.Burst)
+	} else {
+		elapsed := now.Sub(b.UpdatedAt).Seconds()
+
+		b.Tokens = math.Min(float64(settings.Burst), b.Tokens+elapsed*settings.Rate)
+	}
+
+	b.UpdatedAt = now
+
+	if b.Tokens < 1 {
+		return false
+	}
+
+	b.Tokens--
+
+	return true
+}
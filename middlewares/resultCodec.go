@@ -0,0 +1,77 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/jybbang/go-core-architecture/core"
+	cmap "github.com/orcaman/concurrent-map"
+)
+
+// resultCodec remembers the type of the results per request type,
+// so that the stored results can be decoded back into the same type
+type resultCodec struct {
+	types cmap.ConcurrentMap
+}
+
+// encodedResult carries the name of the result type, an empty name is the nil result
+type encodedResult struct {
+	Type  string
+	Value []byte
+}
+
+func newResultCodec() *resultCodec {
+	return &resultCodec{
+		types: cmap.New(),
+	}
+}
+
+// register makes the result type known before the request is handled by this process e.g. after a restart
+func (c *resultCodec) register(typeName string, result interface{}) {
+	c.types.Set(typeName, reflect.TypeOf(result))
+}
+
+func (c *resultCodec) encode(typeName string, result core.Result) (encodedResult, error) {
+	if result.V == nil {
+		return encodedResult{}, nil
+	}
+
+	value, err := json.Marshal(result.V)
+
+	if err != nil {
+		return encodedResult{}, err
+	}
+
+	typeOf := reflect.TypeOf(result.V)
+
+	c.types.Set(typeName, typeOf)
+
+	return encodedResult{Type: typeOf.String(), Value: value}, nil
+}
+
+// decode fails closed when the result type is not registered,
+// the handlers never get the result of another type than they returned
+func (c *resultCodec) decode(typeName string, encoded encodedResult) (core.Result, bool) {
+	if encoded.Type == "" {
+		if len(encoded.Value) == 0 || string(encoded.Value) == "null" {
+			return core.Result{}, true
+		}
+
+		return core.Result{E: fmt.Errorf("%w result type of %s is unknown", core.ErrInternalServerError, typeName)}, false
+	}
+
+	value, ok := c.types.Get(typeName)
+
+	if !ok || value.(reflect.Type).String() != encoded.Type {
+		return core.Result{E: fmt.Errorf("%w result type %s of %s is not registered", core.ErrInternalServerError, encoded.Type, typeName)}, false
+	}
+
+	v := reflect.New(value.(reflect.Type))
+
+	if err := json.Unmarshal(encoded.Value, v.Interface()); err != nil {
+		return core.Result{E: fmt.Errorf("%w %v", core.ErrInternalServerError, err)}, false
+	}
+
+	return core.Result{V: v.Elem().Interface()}, true
+}
@@ -2,25 +2,132 @@ package middlewares
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/fr"
+	"github.com/go-playground/locales/ja"
+	"github.com/go-playground/locales/pt_BR"
+	"github.com/go-playground/locales/ru"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	es_translations "github.com/go-playground/validator/v10/translations/es"
+	fr_translations "github.com/go-playground/validator/v10/translations/fr"
+	ja_translations "github.com/go-playground/validator/v10/translations/ja"
+	pt_BR_translations "github.com/go-playground/validator/v10/translations/pt_BR"
+	ru_translations "github.com/go-playground/validator/v10/translations/ru"
+	zh_translations "github.com/go-playground/validator/v10/translations/zh"
 	"github.com/jybbang/go-core-architecture/core"
 )
 
+// SelfValidator can be implemented by requests which validate themselves after the struct tags passed
+type SelfValidator interface {
+	Validate(ctx context.Context) error
+}
+
 type validationMiddleware struct {
 	core.Middleware
-	validate *validator.Validate
+	validate   *validator.Validate
+	translator ut.Translator
+}
+
+type ValidationSettings struct {
+	// en, es, fr, ja, pt_BR, ru or zh, defaults to en
+	Locale     string
+	Validators map[string]validator.Func
+}
+
+type translation struct {
+	locale   locales.Translator
+	register func(v *validator.Validate, trans ut.Translator) error
+}
+
+var translations = map[string]translation{
+	"en":    {en.New(), en_translations.RegisterDefaultTranslations},
+	"es":    {es.New(), es_translations.RegisterDefaultTranslations},
+	"fr":    {fr.New(), fr_translations.RegisterDefaultTranslations},
+	"ja":    {ja.New(), ja_translations.RegisterDefaultTranslations},
+	"pt_BR": {pt_BR.New(), pt_BR_translations.RegisterDefaultTranslations},
+	"ru":    {ru.New(), ru_translations.RegisterDefaultTranslations},
+	"zh":    {zh.New(), zh_translations.RegisterDefaultTranslations},
 }
 
 func NewValidationMiddleware() *validationMiddleware {
+	return NewValidationMiddlewareWithSettings(ValidationSettings{})
+}
+
+func NewValidationMiddlewareWithSettings(settings ValidationSettings) *validationMiddleware {
+	if settings.Locale == "" {
+		settings.Locale = "en"
+	}
+
+	t, ok := translations[settings.Locale]
+
+	if !ok {
+		panic(fmt.Errorf("locale %s is not supported", settings.Locale))
+	}
+
+	validate := validator.New()
+
+	for tag, fn := range settings.Validators {
+		if err := validate.RegisterValidation(tag, fn); err != nil {
+			panic(fmt.Errorf("validator %s registration errors occurred: %v", tag, err))
+		}
+	}
+
+	translator, _ := ut.New(t.locale, t.locale).GetTranslator(settings.Locale)
+
+	if err := t.register(validate, translator); err != nil {
+		panic(fmt.Errorf("translations registration errors occurred: %v", err))
+	}
+
 	return &validationMiddleware{
-		validate: validator.New(),
+		validate:   validate,
+		translator: translator,
 	}
 }
 
 func (m *validationMiddleware) Run(ctx context.Context, request core.Request) core.Result {
 	if err := m.validate.Struct(request); err != nil {
-		return core.Result{E: core.ErrBadRequest}
+		return core.Result{E: m.toValidationError(err)}
+	}
+
+	if v, ok := request.(SelfValidator); ok {
+		if err := v.Validate(ctx); err != nil {
+			if errors.Is(err, core.ErrBadRequest) {
+				return core.Result{E: err}
+			}
+
+			return core.Result{E: fmt.Errorf("%w %v", core.ErrBadRequest, err)}
+		}
+	}
+
+	return m.NextWith(ctx, request)
+}
+
+func (m *validationMiddleware) toValidationError(err error) error {
+	var validationErrors validator.ValidationErrors
+
+	if !errors.As(err, &validationErrors) {
+		return fmt.Errorf("%w %v", core.ErrBadRequest, err)
 	}
-	return m.Next()
+
+	fields := make([]core.FieldError, 0, len(validationErrors))
+
+	for _, v := range validationErrors {
+		fields = append(fields, core.FieldError{
+			Field:     v.Field(),
+			Namespace: v.Namespace(),
+			Tag:       v.Tag(),
+			Param:     v.Param(),
+			Message:   v.Translate(m.translator),
+		})
+	}
+
+	return core.NewValidationError(fields...)
 }
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/infrastructure/mocks"
+)
+
+func Test_aggregate_ShouldGroupByFields(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(tenantModel), "tenantModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	for i := 1; i <= 6; i++ {
+		dto := new(tenantModel)
+		dto.ID = uuid.New()
+		dto.TenantId = "b"
+		dto.Expect = i
+
+		if i%2 == 1 {
+			dto.TenantId = "a"
+		}
+
+		r.Add(context.Background(), dto)
+	}
+
+	result := r.Aggregate(context.Background(), core.Gt("Expect", 1), core.Aggregation{
+		GroupBy: []string{"TenantId"},
+		Aggregates: []core.Aggregate{
+			core.CountOf("count"),
+			core.SumOf("Expect", "sum"),
+			core.MinOf("Expect", "min"),
+			core.MaxOf("Expect", "max"),
+			core.AvgOf("Expect", "avg"),
+		},
+	})
+
+	if result.E != nil {
+		t.Fatalf("Test_aggregate_ShouldGroupByFields() err = %v", result.E)
+	}
+
+	rows := result.V.([]core.AggregateRow)
+
+	expect := []struct {
+		tenantId string
+		values   map[string]float64
+	}{
+		{"a", map[string]float64{"count": 2, "sum": 8, "min": 3, "max": 5, "avg": 4}},
+		{"b", map[string]float64{"count": 3, "sum": 12, "min": 2, "max": 6, "avg": 4}},
+	}
+
+	if len(rows) != len(expect) {
+		t.Fatalf("Test_aggregate_ShouldGroupByFields() rows = %v", rows)
+	}
+
+	for i, v := range expect {
+		if rows[i].Keys["TenantId"] != v.tenantId {
+			t.Errorf("Test_aggregate_ShouldGroupByFields() keys = %v, expect %v", rows[i].Keys, v.tenantId)
+		}
+
+		for alias, value := range v.values {
+			if rows[i].Values[alias] != value {
+				t.Errorf("Test_aggregate_ShouldGroupByFields() %s %s = %v, expect %v", v.tenantId, alias, rows[i].Values[alias], value)
+			}
+		}
+	}
+}
+
+func Test_aggregate_ShouldAnswerOneRowWithoutGroup(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	result := r.Aggregate(context.Background(), nil, core.Aggregation{
+		Aggregates: []core.Aggregate{core.CountOf("count"), core.SumOf("Expect", "sum")},
+	})
+
+	rows, _ := result.V.([]core.AggregateRow)
+
+	if result.E != nil || len(rows) != 1 || rows[0].Values["count"] != 0 || rows[0].Values["sum"] != 0 {
+		t.Errorf("Test_aggregate_ShouldAnswerOneRowWithoutGroup() rows = %v, err = %v", rows, result.E)
+	}
+}
+
+func Test_aggregate_ShouldValidateAggregation(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	tests := []struct {
+		name        string
+		aggregation core.Aggregation
+	}{
+		{"empty", core.Aggregation{}},
+		{"no field", core.Aggregation{Aggregates: []core.Aggregate{core.SumOf("", "sum")}}},
+		{"no alias", core.Aggregation{Aggregates: []core.Aggregate{core.CountOf("")}}},
+		{"duplicated alias", core.Aggregation{Aggregates: []core.Aggregate{core.CountOf("x"), core.SumOf("Expect", "x")}}},
+		{"unknown function", core.Aggregation{Aggregates: []core.Aggregate{{Function: "median", Field: "Expect", Alias: "x"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := r.Aggregate(context.Background(), nil, tt.aggregation); !errors.Is(result.E, core.ErrBadRequest) {
+				t.Errorf("Test_aggregate_ShouldValidateAggregation() err = %v, expect %v", result.E, core.ErrBadRequest)
+			}
+		})
+	}
+}
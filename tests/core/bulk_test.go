@@ -0,0 +1,207 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/infrastructure/mocks"
+)
+
+func Test_bulk_UpsertShouldKeepCreationStamp(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(versionModel), "versionModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := core.WithPrincipal(context.Background(), &core.Principal{UserId: "creator"})
+
+	dto := new(versionModel)
+	dto.ID = uuid.New()
+	r.Add(ctx, dto)
+
+	upserted := new(versionModel)
+	upserted.ID = dto.ID
+	upserted.Expect = 1
+
+	result := r.UpsertRange(core.WithPrincipal(ctx, &core.Principal{UserId: "updater"}), []core.Entitier{upserted, new(versionModel)})
+
+	if result.E != nil || result.V.(int64) != 2 {
+		t.Fatalf("Test_bulk_UpsertShouldKeepCreationStamp() affected = %v, err = %v", result.V, result.E)
+	}
+
+	dto2 := new(versionModel)
+	r.Find(ctx, dto.ID, dto2)
+
+	if dto2.CreateUser != "creator" || dto2.UpdateUser != "updater" || dto2.Version != 2 || dto2.Expect != 1 {
+		t.Errorf("Test_bulk_UpsertShouldKeepCreationStamp() result = %v", dto2)
+	}
+
+	if count := r.Count(ctx); count.V.(int64) != 2 {
+		t.Errorf("Test_bulk_UpsertShouldKeepCreationStamp() count = %v, expect %v", count.V, 2)
+	}
+}
+
+func Test_bulk_UpsertShouldStampOnlyMissingCreation(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(versionModel), "versionModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := context.Background()
+
+	createdAt := time.Now().Add(-time.Hour)
+
+	stamped := new(versionModel)
+	stamped.CreatedAt = createdAt
+
+	missing := new(versionModel)
+
+	if result := r.UpsertRange(ctx, []core.Entitier{stamped, missing}); result.E != nil {
+		t.Fatalf("Test_bulk_UpsertShouldStampOnlyMissingCreation() err = %v", result.E)
+	}
+
+	if !stamped.CreatedAt.Equal(createdAt) {
+		t.Errorf("Test_bulk_UpsertShouldStampOnlyMissingCreation() CreatedAt = %v, expect %v", stamped.CreatedAt, createdAt)
+	}
+
+	if missing.CreatedAt.IsZero() {
+		t.Errorf("Test_bulk_UpsertShouldStampOnlyMissingCreation() CreatedAt = %v, expect stamped", missing.CreatedAt)
+	}
+}
+
+func Test_bulk_PatchShouldUpdateFields(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(versionModel), "versionModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := context.Background()
+
+	dto := new(versionModel)
+	dto.ID = uuid.New()
+	r.Add(ctx, dto)
+
+	result := r.Patch(ctx, dto.ID, map[string]interface{}{"Expect": 3})
+
+	if result.E != nil || result.V.(int64) != 1 {
+		t.Fatalf("Test_bulk_PatchShouldUpdateFields() affected = %v, err = %v", result.V, result.E)
+	}
+
+	dto2 := new(versionModel)
+	r.Find(ctx, dto.ID, dto2)
+
+	if dto2.Expect != 3 || dto2.Version != 2 {
+		t.Errorf("Test_bulk_PatchShouldUpdateFields() result = %v", dto2)
+	}
+
+	if result := r.Patch(ctx, uuid.New(), map[string]interface{}{"Expect": 3}); result.E != nil || result.V.(int64) != 0 {
+		t.Errorf("Test_bulk_PatchShouldUpdateFields() affected = %v, err = %v, expect %v", result.V, result.E, 0)
+	}
+
+	if result := r.Patch(ctx, dto.ID, map[string]interface{}{"Version": 9}); !errors.Is(result.E, core.ErrBadRequest) {
+		t.Errorf("Test_bulk_PatchShouldUpdateFields() err = %v, expect %v", result.E, core.ErrBadRequest)
+	}
+
+	if result := r.Patch(ctx, dto.ID, map[string]interface{}{"Unknown": 9}); !errors.Is(result.E, core.ErrBadRequest) {
+		t.Errorf("Test_bulk_PatchShouldUpdateFields() err = %v, expect %v", result.E, core.ErrBadRequest)
+	}
+}
+
+func Test_bulk_PatchShouldNotRemoveSoftly(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(softDeleteModel), "softDeleteModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := context.Background()
+
+	dto := new(softDeleteModel)
+	dto.ID = uuid.New()
+	r.Add(ctx, dto)
+
+	now := time.Now()
+
+	if result := r.Patch(ctx, dto.ID, map[string]interface{}{core.DeletedAtField: &now}); !errors.Is(result.E, core.ErrBadRequest) {
+		t.Errorf("Test_bulk_PatchShouldNotRemoveSoftly() err = %v, expect %v", result.E, core.ErrBadRequest)
+	}
+
+	if result := r.UpdateWhere(ctx, core.Eq("ID", dto.ID), map[string]interface{}{core.DeleteUserField: "qwe"}); !errors.Is(result.E, core.ErrBadRequest) {
+		t.Errorf("Test_bulk_PatchShouldNotRemoveSoftly() err = %v, expect %v", result.E, core.ErrBadRequest)
+	}
+
+	if result := r.Find(ctx, dto.ID, new(softDeleteModel)); result.E != nil {
+		t.Errorf("Test_bulk_PatchShouldNotRemoveSoftly() err = %v", result.E)
+	}
+}
+
+func Test_bulk_UpdateWhereAndRemoveWhere(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		dto := new(testModel)
+		dto.ID = uuid.New()
+		dto.Expect = i
+
+		r.Add(ctx, dto)
+	}
+
+	if result := r.UpdateWhere(ctx, core.Lt("Expect", 5), map[string]interface{}{"Expect": 100}); result.E != nil || result.V.(int64) != 5 {
+		t.Errorf("Test_bulk_UpdateWhereAndRemoveWhere() updated = %v, err = %v, expect %v", result.V, result.E, 5)
+	}
+
+	if result := r.RemoveWhere(ctx, core.Eq("Expect", 100)); result.E != nil || result.V.(int64) != 5 {
+		t.Errorf("Test_bulk_UpdateWhereAndRemoveWhere() removed = %v, err = %v, expect %v", result.V, result.E, 5)
+	}
+
+	if count := r.Count(ctx); count.V.(int64) != 5 {
+		t.Errorf("Test_bulk_UpdateWhereAndRemoveWhere() count = %v, expect %v", count.V, 5)
+	}
+
+	if result := r.RemoveWhere(ctx, nil); result.E == nil {
+		t.Errorf("Test_bulk_UpdateWhereAndRemoveWhere() removed without spec")
+	}
+}
+
+func Test_bulk_RemoveWhereShouldRemoveSoftly(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(softDeleteModel), "softDeleteModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		dto := new(softDeleteModel)
+		dto.ID = uuid.New()
+		dto.Expect = i % 2
+
+		r.Add(ctx, dto)
+	}
+
+	if result := r.RemoveWhere(ctx, core.Eq("Expect", 1)); result.E != nil || result.V.(int64) != 2 {
+		t.Errorf("Test_bulk_RemoveWhereShouldRemoveSoftly() removed = %v, err = %v, expect %v", result.V, result.E, 2)
+	}
+
+	if count := r.Count(ctx); count.V.(int64) != 2 {
+		t.Errorf("Test_bulk_RemoveWhereShouldRemoveSoftly() count = %v, expect %v", count.V, 2)
+	}
+
+	if count := r.Count(core.WithDeletedScope(ctx, core.DeletedOnly)); count.V.(int64) != 2 {
+		t.Errorf("Test_bulk_RemoveWhereShouldRemoveSoftly() deleted = %v, expect %v", count.V, 2)
+	}
+}
@@ -33,10 +33,11 @@ func Test_commandRepositoryService_Remove(t *testing.T) {
 
 	time.Sleep(1 * time.Second)
 
+	// removing the removed entity is not found
 	result := r.Remove(ctx, dto.ID)
 
-	if result.E != nil {
-		t.Errorf("Test_commandRepositoryService_Remove() err = %v", result.E)
+	if !errors.Is(result.E, core.ErrNotFound) {
+		t.Errorf("Test_commandRepositoryService_Remove() err = %v, expect %v", result.E, core.ErrNotFound)
 	}
 
 	dto2 := new(testModel)
@@ -192,3 +193,86 @@ func Test_commandRepositoryService_UpdateRange(t *testing.T) {
 		t.Errorf("Test_commandRepositoryService_UpdateRange() cnt = %v, expect %v", result.V, cntExpect)
 	}
 }
+
+func Test_commandRepositoryService_AddShouldStampPrincipal(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := context.WithValue(context.Background(), "Userid", "raw")
+	ctx = core.WithPrincipal(ctx, &core.Principal{UserId: "principal"})
+
+	dto := new(testModel)
+	dto.ID = uuid.New()
+
+	if result := r.Add(ctx, dto); result.E != nil {
+		t.Errorf("Test_commandRepositoryService_AddShouldStampPrincipal() err = %v", result.E)
+	}
+
+	if dto.CreateUser != "principal" {
+		t.Errorf("Test_commandRepositoryService_AddShouldStampPrincipal() CreateUser = %v, expect %v", dto.CreateUser, "principal")
+	}
+}
+
+func Test_commandRepositoryService_AddShouldNotStampRawUserIdWithVerifier(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := context.WithValue(context.Background(), "Userid", "raw")
+	ctx = core.WithVerifier(ctx)
+
+	dto := new(testModel)
+	dto.ID = uuid.New()
+
+	if result := r.Add(ctx, dto); result.E != nil {
+		t.Errorf("Test_commandRepositoryService_AddShouldNotStampRawUserIdWithVerifier() err = %v", result.E)
+	}
+
+	if dto.CreateUser != "" {
+		t.Errorf("Test_commandRepositoryService_AddShouldNotStampRawUserIdWithVerifier() CreateUser = %v, expect empty", dto.CreateUser)
+	}
+}
+
+func Test_commandRepositoryService_ShouldAnswerConflictAndNotFound(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		CircuitBreaker(core.CircuitBreakerSettings{
+			SamplingFailureCount: 2,
+		}).
+		Create()
+
+	ctx := context.Background()
+
+	dto := new(testModel)
+	dto.ID = uuid.New()
+	r.Add(ctx, dto)
+
+	// the business errors do not trip the circuit
+	for i := 0; i < 5; i++ {
+		if result := r.Add(ctx, dto); !errors.Is(result.E, core.ErrConflict) {
+			t.Fatalf("Test_commandRepositoryService_ShouldAnswerConflictAndNotFound() err = %v, expect %v", result.E, core.ErrConflict)
+		}
+
+		missing := new(testModel)
+		missing.ID = uuid.New()
+
+		if result := r.Update(ctx, missing); !errors.Is(result.E, core.ErrNotFound) {
+			t.Fatalf("Test_commandRepositoryService_ShouldAnswerConflictAndNotFound() err = %v, expect %v", result.E, core.ErrNotFound)
+		}
+	}
+
+	if result := r.RemoveRange(ctx, []uuid.UUID{dto.ID, uuid.New()}); !errors.Is(result.E, core.ErrNotFound) {
+		t.Errorf("Test_commandRepositoryService_ShouldAnswerConflictAndNotFound() err = %v, expect %v", result.E, core.ErrNotFound)
+	}
+
+	if result := r.Count(ctx); result.V != int64(1) {
+		t.Errorf("Test_commandRepositoryService_ShouldAnswerConflictAndNotFound() count = %v, expect %v", result.V, 1)
+	}
+}
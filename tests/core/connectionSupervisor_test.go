@@ -0,0 +1,162 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/sony/gobreaker"
+)
+
+func Test_connectionSupervisor_ShouldReconnectInBackgroundWhenCircuitOpen(t *testing.T) {
+	ctx := context.Background()
+	connectDelay := time.Duration(300 * time.Millisecond)
+
+	flaky := &flakyStateAdapter{failures: 2, err: errTransient}
+	s := core.NewStateServiceBuilder().
+		StateAdapter(flaky).
+		CircuitBreaker(core.CircuitBreakerSettings{
+			DurationOfBreak:      time.Duration(10 * time.Millisecond),
+			SamplingFailureCount: 2,
+		}).
+		Create()
+
+	flaky.connectDelay = connectDelay
+
+	s.Set(ctx, "qwe", 123)
+	s.Set(ctx, "qwe", 123)
+
+	// circuit is half-open but the connection is still reconnecting
+	time.Sleep(50 * time.Millisecond)
+
+	start := time.Now()
+	result := s.Set(ctx, "qwe", 123)
+
+	if !errors.Is(result.E, gobreaker.ErrOpenState) {
+		t.Errorf("Test_connectionSupervisor_ShouldReconnectInBackgroundWhenCircuitOpen() err = %v, expect %v", result.E, gobreaker.ErrOpenState)
+	}
+
+	if elapsed := time.Since(start); elapsed > connectDelay/2 {
+		t.Errorf("Test_connectionSupervisor_ShouldReconnectInBackgroundWhenCircuitOpen() elapsed = %v, expect fail fast", elapsed)
+	}
+
+	time.Sleep(connectDelay)
+
+	result = s.Set(ctx, "qwe", 123)
+
+	if result.E != nil {
+		t.Errorf("Test_connectionSupervisor_ShouldReconnectInBackgroundWhenCircuitOpen() err = %v", result.E)
+	}
+
+	if flaky.GetDisconnects() != 1 {
+		t.Errorf("Test_connectionSupervisor_ShouldReconnectInBackgroundWhenCircuitOpen() disconnects = %v, expect %v", flaky.GetDisconnects(), 1)
+	}
+
+	if flaky.GetConnects() != 2 {
+		t.Errorf("Test_connectionSupervisor_ShouldReconnectInBackgroundWhenCircuitOpen() connects = %v, expect %v", flaky.GetConnects(), 2)
+	}
+}
+
+func Test_connectionSupervisor_StateChangeShouldBeNotified(t *testing.T) {
+	ctx := context.Background()
+
+	var mutex sync.Mutex
+	states := make([]string, 0)
+
+	flaky := &flakyStateAdapter{failures: 2, err: errTransient}
+	s := core.NewStateServiceBuilder().
+		StateAdapter(flaky).
+		CircuitBreaker(core.CircuitBreakerSettings{
+			SamplingFailureCount: 2,
+		}).
+		Reconnect(core.ReconnectSettings{
+			OnStateChange: func(name string, from string, to string) {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				states = append(states, to)
+			},
+		}).
+		Create()
+
+	s.Set(ctx, "qwe", 123)
+	s.Set(ctx, "qwe", 123)
+
+	time.Sleep(100 * time.Millisecond)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	expect := []string{core.StateReconnecting.String(), core.StateConnected.String()}
+
+	if len(states) != len(expect) || states[0] != expect[0] || states[1] != expect[1] {
+		t.Errorf("Test_connectionSupervisor_StateChangeShouldBeNotified() states = %v, expect %v", states, expect)
+	}
+}
+
+func Test_connectionSupervisor_ShouldNotReconnectWhenCircuitClosed(t *testing.T) {
+	ctx := context.Background()
+	timeout := time.Duration(50 * time.Millisecond)
+
+	flaky := &flakyStateAdapter{failures: 2, err: errTransient}
+	s := core.NewStateServiceBuilder().
+		StateAdapter(flaky).
+		CircuitBreaker(core.CircuitBreakerSettings{
+			DurationOfBreak:      timeout,
+			SamplingFailureCount: 2,
+		}).
+		Create()
+
+	s.Set(ctx, "qwe", 123)
+	s.Set(ctx, "qwe", 123)
+
+	time.Sleep(timeout * 2)
+
+	// open -> half-open -> closed
+	result := s.Set(ctx, "qwe", 123)
+
+	if result.E != nil {
+		t.Errorf("Test_connectionSupervisor_ShouldNotReconnectWhenCircuitClosed() err = %v", result.E)
+	}
+
+	if flaky.GetDisconnects() != 1 {
+		t.Errorf("Test_connectionSupervisor_ShouldNotReconnectWhenCircuitClosed() disconnects = %v, expect %v", flaky.GetDisconnects(), 1)
+	}
+}
+
+func Test_connectionSupervisor_CloseShouldStopReconnecting(t *testing.T) {
+	ctx := context.Background()
+
+	flaky := &flakyStateAdapter{failures: 2, err: errTransient, connectErr: errTransient}
+	s := core.NewStateServiceBuilder().
+		StateAdapter(flaky).
+		CircuitBreaker(core.CircuitBreakerSettings{
+			SamplingFailureCount: 2,
+		}).
+		Reconnect(core.ReconnectSettings{
+			InitialInterval: time.Duration(10 * time.Millisecond),
+		}).
+		Create()
+
+	s.Set(ctx, "qwe", 123)
+	s.Set(ctx, "qwe", 123)
+
+	time.Sleep(50 * time.Millisecond)
+
+	s.Close()
+
+	connects := flaky.GetConnects()
+
+	time.Sleep(50 * time.Millisecond)
+
+	if flaky.GetConnects() != connects {
+		t.Errorf("Test_connectionSupervisor_CloseShouldStopReconnecting() connects = %v, expect %v", flaky.GetConnects(), connects)
+	}
+
+	if result := s.Set(ctx, "qwe", 123); !errors.Is(result.E, gobreaker.ErrOpenState) {
+		t.Errorf("Test_connectionSupervisor_CloseShouldStopReconnecting() err = %v, expect %v", result.E, gobreaker.ErrOpenState)
+	}
+}
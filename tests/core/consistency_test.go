@@ -0,0 +1,51 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/infrastructure/mocks"
+)
+
+func Test_consistency_ShouldRequirePrimaryAfterWrite(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := core.WithReadYourWrites(context.Background())
+
+	r.Count(ctx)
+
+	if core.RequiresPrimary(ctx) {
+		t.Errorf("Test_consistency_ShouldRequirePrimaryAfterWrite() primary = %v, expect %v", true, false)
+	}
+
+	dto := new(testModel)
+	dto.ID = uuid.New()
+
+	if result := r.Add(ctx, dto); result.E != nil {
+		t.Fatalf("Test_consistency_ShouldRequirePrimaryAfterWrite() err = %v", result.E)
+	}
+
+	if !core.RequiresPrimary(ctx) {
+		t.Errorf("Test_consistency_ShouldRequirePrimaryAfterWrite() primary = %v, expect %v", false, true)
+	}
+
+	if core.RequiresPrimary(core.WithReadYourWrites(context.Background())) {
+		t.Errorf("Test_consistency_ShouldRequirePrimaryAfterWrite() primary of new context = %v, expect %v", true, false)
+	}
+}
+
+func Test_consistency_WithPrimary(t *testing.T) {
+	if core.RequiresPrimary(context.Background()) {
+		t.Errorf("Test_consistency_WithPrimary() primary = %v, expect %v", true, false)
+	}
+
+	if !core.RequiresPrimary(core.WithPrimary(context.Background())) {
+		t.Errorf("Test_consistency_WithPrimary() primary = %v, expect %v", false, true)
+	}
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+func Test_error_ShouldMatchKindAndCause(t *testing.T) {
+	cause := errors.New("driver error")
+	err := fmt.Errorf("wrapped: %w",
+		core.NewError(core.ErrConflict, "user.duplicated", "user already exist").WithCause(cause))
+
+	if !errors.Is(err, core.ErrConflict) {
+		t.Errorf("Test_error_ShouldMatchKindAndCause() expect kind is matched")
+	}
+
+	if !errors.Is(err, cause) {
+		t.Errorf("Test_error_ShouldMatchKindAndCause() expect cause is matched")
+	}
+
+	if errors.Is(err, core.ErrNotFound) {
+		t.Errorf("Test_error_ShouldMatchKindAndCause() expect other kind is not matched")
+	}
+}
+
+func Test_error_ShouldMapToHttpStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{core.ErrUnauthorized, http.StatusUnauthorized},
+		{core.ErrPreconditionFailed, http.StatusPreconditionFailed},
+		{core.ErrUnavailable, http.StatusServiceUnavailable},
+		{core.ErrTimeout, http.StatusGatewayTimeout},
+		{core.NewError(core.ErrNotFound, "", ""), http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		result := core.Result{E: tt.err}
+
+		if got := result.ToHttpStatus(); got != tt.want {
+			t.Errorf("Test_error_ShouldMapToHttpStatus() %v = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func Test_error_ShouldRenderProblemDetails(t *testing.T) {
+	result := core.Result{
+		E: core.NewError(core.ErrBadRequest, "order.invalid", "order is invalid").
+			WithDetail("orderId", "123"),
+	}
+
+	w := httptest.NewRecorder()
+
+	if err := result.WriteProblem(w, "/orders/123"); err != nil {
+		t.Fatalf("Test_error_ShouldRenderProblemDetails() err = %v", err)
+	}
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Test_error_ShouldRenderProblemDetails() status = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+
+	if w.Header().Get("Content-Type") != core.ProblemContentType {
+		t.Errorf("Test_error_ShouldRenderProblemDetails() content type = %v", w.Header().Get("Content-Type"))
+	}
+
+	body := make(map[string]interface{})
+	json.Unmarshal(w.Body.Bytes(), &body)
+
+	if body["code"] != "order.invalid" || body["detail"] != "order is invalid" || body["orderId"] != "123" {
+		t.Errorf("Test_error_ShouldRenderProblemDetails() body = %v", body)
+	}
+}
+
+func Test_error_ShouldHideServerErrorDetail(t *testing.T) {
+	result := core.Result{E: errors.New("secret connection string")}
+
+	problem := result.ToProblemDetails("")
+
+	if problem.Status != http.StatusInternalServerError || problem.Detail != "" {
+		t.Errorf("Test_error_ShouldHideServerErrorDetail() problem = %v", problem)
+	}
+}
+
+func Test_error_ShouldHideCauseOfClientError(t *testing.T) {
+	cause := errors.New(`duplicate key value violates unique constraint "uix_users_email" (SQLSTATE 23505)`)
+	result := core.Result{E: core.NewError(core.ErrConflict, core.CodeUniqueViolation, "").WithCause(cause)}
+
+	if problem := result.ToProblemDetails(""); strings.Contains(problem.Detail, "uix_users_email") || problem.Detail != core.ErrConflict.Error() {
+		t.Errorf("Test_error_ShouldHideCauseOfClientError() detail = %v", problem.Detail)
+	}
+
+	if s := result.ToGrpcStatus(); strings.Contains(s.Message(), "uix_users_email") {
+		t.Errorf("Test_error_ShouldHideCauseOfClientError() message = %v", s.Message())
+	}
+}
+
+func Test_error_ShouldConvertToGrpcStatus(t *testing.T) {
+	result := core.Result{
+		E: core.NewValidationError(core.FieldError{Field: "Name", Namespace: "User.Name", Tag: "required", Message: "Name is required"}),
+	}
+
+	s := result.ToGrpcStatus()
+
+	if s.Code() != codes.InvalidArgument {
+		t.Errorf("Test_error_ShouldConvertToGrpcStatus() code = %v, want %v", s.Code(), codes.InvalidArgument)
+	}
+
+	if len(s.Details()) != 1 {
+		t.Fatalf("Test_error_ShouldConvertToGrpcStatus() details = %v", s.Details())
+	}
+
+	badRequest, ok := s.Details()[0].(*errdetails.BadRequest)
+	if !ok || badRequest.FieldViolations[0].Field != "User.Name" {
+		t.Errorf("Test_error_ShouldConvertToGrpcStatus() detail = %v", s.Details()[0])
+	}
+}
+
+func Test_error_RetryableErrorShouldBeTransient(t *testing.T) {
+	if core.IsTransientError(core.ErrUnauthorized) {
+		t.Errorf("Test_error_RetryableErrorShouldBeTransient() expect unauthorized is not transient")
+	}
+
+	if !core.IsTransientError(core.NewError(core.ErrConflict, "lock", "").AsRetryable()) {
+		t.Errorf("Test_error_RetryableErrorShouldBeTransient() expect retryable error is transient")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -301,3 +302,47 @@ func Test_eventBus_PublishDomainEventsBufferedEventShouldBeWorking(t *testing.T)
 		t.Errorf("Test_eventBus_PublishDomainEventsCircuitBrakerShouldBeWorking() count = %v, expect %v", then, 1)
 	}
 }
+
+func Test_eventBus_PublishDomainEventsShouldRetryOnlyPublishing(t *testing.T) {
+	flaky := &flakyMessagingAdapter{failures: 2, err: errTransient}
+
+	var handled, listened int32
+
+	m := core.NewMediatorBuilder().
+		AddNotificationHandler(new(okNotification), func(ctx context.Context, notification interface{}) error {
+			atomic.AddInt32(&handled, 1)
+			return nil
+		}).
+		Create()
+	e := core.NewEventBusBuilder().
+		CircuitBreaker(core.CircuitBreakerSettings{
+			Name: "t8",
+		}).
+		Retry(core.RetrySettings{
+			MaxAttempts:     3,
+			InitialInterval: time.Duration(10 * time.Millisecond),
+		}).
+		MessaingAdapter(flaky).
+		CustomMediator(m).
+		Create()
+
+	e.AddDomainEventListener(func(ctx context.Context, event core.DomainEventer) {
+		atomic.AddInt32(&listened, 1)
+	})
+
+	event := new(okNotification)
+	event.Topic = "retry"
+	e.AddDomainEvent(event)
+
+	if err := e.PublishDomainEvents(context.Background()); err != nil {
+		t.Errorf("Test_eventBus_PublishDomainEventsShouldRetryOnlyPublishing() err = %v", err)
+	}
+
+	if flaky.GetCalls() != 3 {
+		t.Errorf("Test_eventBus_PublishDomainEventsShouldRetryOnlyPublishing() calls = %v, expect %v", flaky.GetCalls(), 3)
+	}
+
+	if handled != 1 || listened != 1 {
+		t.Errorf("Test_eventBus_PublishDomainEventsShouldRetryOnlyPublishing() handled = %v, listened = %v, expect %v", handled, listened, 1)
+	}
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+type indexedModel struct {
+	core.Entity
+	Code      string    `index:",unique"`
+	Tenant    string    `index:"idx_tenant_name"`
+	Name      string    `index:"idx_tenant_name,desc"`
+	Body      string    `index:"idx_body,text"`
+	ExpiresAt time.Time `index:"idx_expires,ttl=1h"`
+}
+
+func (m *indexedModel) Indexes() []core.Index {
+	return []core.Index{{Name: "idx_created", Fields: []core.IndexField{{Field: "CreatedAt", Descending: true}}}}
+}
+
+type invalidIndexModel struct {
+	core.Entity
+	Code string `index:",unknown"`
+}
+
+func Test_indexesOf_ShouldReadTagsAndIndexer(t *testing.T) {
+	indexes, err := core.IndexesOf(new(indexedModel))
+
+	expect := []core.Index{
+		{Name: "idx_created", Fields: []core.IndexField{{Field: "CreatedAt", Descending: true}}},
+		{Name: "idx_code", Fields: []core.IndexField{{Field: "Code"}}, Unique: true},
+		{Name: "idx_tenant_name", Fields: []core.IndexField{{Field: "Tenant"}, {Field: "Name", Descending: true}}},
+		{Name: "idx_body", Fields: []core.IndexField{{Field: "Body"}}, Text: true},
+		{Name: "idx_expires", Fields: []core.IndexField{{Field: "ExpiresAt"}}, TTL: time.Hour},
+	}
+
+	if err != nil || !reflect.DeepEqual(indexes, expect) {
+		t.Errorf("Test_indexesOf_ShouldReadTagsAndIndexer() indexes = %v, err = %v, expect %v", indexes, err, expect)
+	}
+}
+
+func Test_indexesOf_ShouldRejectUnknownOption(t *testing.T) {
+	if _, err := core.IndexesOf(new(invalidIndexModel)); !errors.Is(err, core.ErrInternalServerError) {
+		t.Errorf("Test_indexesOf_ShouldRejectUnknownOption() err = %v, expect %v", err, core.ErrInternalServerError)
+	}
+}
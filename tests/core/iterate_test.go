@@ -0,0 +1,75 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/infrastructure/mocks"
+)
+
+func Test_iterate_ShouldStreamMatchedEntities(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := context.Background()
+
+	for i := 0; i < 100; i++ {
+		dto := new(testModel)
+		dto.ID = uuid.New()
+		dto.Expect = i
+
+		r.Add(ctx, dto)
+	}
+
+	count := 0
+
+	result := r.Iterate(ctx, core.Lt("Expect", 10), func(entity core.Entitier) error {
+		if entity.(*testModel).Expect >= 10 {
+			t.Errorf("Test_iterate_ShouldStreamMatchedEntities() unexpected %v", entity)
+		}
+
+		count++
+
+		return nil
+	})
+
+	if result.E != nil || count != 10 {
+		t.Errorf("Test_iterate_ShouldStreamMatchedEntities() count = %v, err = %v", count, result.E)
+	}
+}
+
+func Test_iterate_ShouldStopByCallbackError(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		dto := new(testModel)
+		dto.ID = uuid.New()
+
+		r.Add(ctx, dto)
+	}
+
+	errStop := errors.New("stop")
+	count := 0
+
+	result := r.Iterate(ctx, nil, func(entity core.Entitier) error {
+		count++
+
+		return errStop
+	})
+
+	if !errors.Is(result.E, errStop) || count != 1 {
+		t.Errorf("Test_iterate_ShouldStopByCallbackError() count = %v, err = %v", count, result.E)
+	}
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/infrastructure/mocks"
+)
+
+func Test_page_ShouldPageByOffset(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		dto := new(testModel)
+		dto.ID = uuid.New()
+		dto.Expect = i
+
+		r.Add(ctx, dto)
+	}
+
+	dest := make([]*testModel, 0)
+
+	result := r.Page(ctx, core.Gt("Expect", 1), core.PageRequest{
+		OrderBy: []core.Order{{Field: "Expect", Descending: true}},
+		Offset:  2,
+		Limit:   3,
+	}, &dest)
+
+	if result.E != nil {
+		t.Fatalf("Test_page_ShouldPageByOffset() err = %v", result.E)
+	}
+
+	page := result.V.(*core.Page)
+
+	if page.TotalCount != 8 || !page.HasMore || page.NextCursor == "" {
+		t.Errorf("Test_page_ShouldPageByOffset() page = %v", page)
+	}
+
+	if len(dest) != 3 || dest[0].Expect != 7 || dest[2].Expect != 5 {
+		t.Errorf("Test_page_ShouldPageByOffset() dest = %v", dest)
+	}
+}
+
+func Test_page_ShouldPageByCursor(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := context.Background()
+
+	expect := 25
+	for i := 0; i < expect; i++ {
+		dto := new(testModel)
+		dto.ID = uuid.New()
+		dto.Expect = i % 5
+
+		r.Add(ctx, dto)
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	request := core.PageRequest{
+		OrderBy: []core.Order{{Field: "Expect"}},
+		Limit:   4,
+		Fields:  []string{"Expect"},
+	}
+
+	for {
+		dest := make([]*testModel, 0)
+
+		result := r.Page(ctx, nil, request, &dest)
+		if result.E != nil {
+			t.Fatalf("Test_page_ShouldPageByCursor() err = %v", result.E)
+		}
+
+		for _, v := range dest {
+			if seen[v.ID] {
+				t.Errorf("Test_page_ShouldPageByCursor() duplicated %v", v.ID)
+			}
+
+			seen[v.ID] = true
+		}
+
+		page := result.V.(*core.Page)
+		if !page.HasMore {
+			break
+		}
+
+		request.Cursor = page.NextCursor
+	}
+
+	if len(seen) != expect {
+		t.Errorf("Test_page_ShouldPageByCursor() seen = %v, expect %v", len(seen), expect)
+	}
+}
@@ -127,6 +127,34 @@ func Test_queryRepositoryService_AnyWithFilter(t *testing.T) {
 	}
 }
 
+func Test_queryRepositoryService_AnyWithSpecification(t *testing.T) {
+	ctx := context.Background()
+
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	dto := new(testModel)
+	dto.ID = uuid.New()
+	dto.Expect = 123
+
+	r.Add(ctx, dto)
+
+	result := r.AnyWithFilter(ctx, core.Eq("Expect", 123), nil)
+
+	if result.V != true || result.E != nil {
+		t.Errorf("Test_queryRepositoryService_AnyWithSpecification() ok = %v, err = %v, expect %v", result.V, result.E, true)
+	}
+
+	result = r.AnyWithFilter(ctx, core.Eq("Expect", 456), nil)
+
+	if result.V != false || result.E != nil {
+		t.Errorf("Test_queryRepositoryService_AnyWithSpecification() ok = %v, err = %v, expect %v", result.V, result.E, false)
+	}
+}
+
 func Test_queryRepositoryService_Count(t *testing.T) {
 	ctx := context.Background()
 
@@ -194,6 +222,35 @@ func Test_queryRepositoryService_CountWithFilter(t *testing.T) {
 	}
 }
 
+func Test_queryRepositoryService_CountWithSpecification(t *testing.T) {
+	ctx := context.Background()
+
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	expect := 100
+	for i := 0; i < expect*2; i++ {
+		dto := new(testModel)
+		dto.ID = uuid.New()
+		dto.Expect = i % 2
+
+		r.Add(ctx, dto)
+	}
+
+	result := r.CountWithFilter(ctx, core.Eq("Expect", 1), nil)
+
+	if result.V.(int64) != int64(expect) {
+		t.Errorf("Test_queryRepositoryService_CountWithSpecification() result = %v, expect %v", result.V, expect)
+	}
+
+	if result.E != nil {
+		t.Errorf("Test_queryRepositoryService_CountWithSpecification() err = %v", result.E)
+	}
+}
+
 func Test_queryRepositoryService_List(t *testing.T) {
 	ctx := context.Background()
 
@@ -265,3 +322,39 @@ func Test_queryRepositoryService_ListWithFilter(t *testing.T) {
 		t.Errorf("Test_queryRepositoryService_ListWithFilter() err = %v", result.E)
 	}
 }
+
+func Test_queryRepositoryService_ListWithSpecification(t *testing.T) {
+	ctx := context.Background()
+
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	expect := 100
+	for i := 0; i < expect*2; i++ {
+		dto := new(testModel)
+		dto.ID = uuid.New()
+		dto.Expect = i % 2
+
+		r.Add(ctx, dto)
+	}
+
+	var dest = make([]*testModel, 0)
+	result := r.ListWithFilter(ctx, core.Or(core.Eq("Expect", 1), core.Lt("Expect", 0)), nil, &dest)
+
+	if len(dest) != expect {
+		t.Errorf("Test_queryRepositoryService_ListWithSpecification() cnt = %v, expect %v", len(dest), expect)
+	}
+
+	for _, v := range dest {
+		if v.Expect != 1 {
+			t.Errorf("Test_queryRepositoryService_ListWithSpecification() expect = %v, expect %v", v.Expect, 1)
+		}
+	}
+
+	if result.E != nil {
+		t.Errorf("Test_queryRepositoryService_ListWithSpecification() err = %v", result.E)
+	}
+}
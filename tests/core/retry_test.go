@@ -0,0 +1,168 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/sony/gobreaker"
+)
+
+var errTransient = fmt.Errorf("%w transient error", core.ErrUnavailable)
+
+func Test_retry_ShouldRetryTransientError(t *testing.T) {
+	ctx := context.Background()
+
+	flaky := &flakyStateAdapter{failures: 2, err: errTransient}
+	s := core.NewStateServiceBuilder().
+		StateAdapter(flaky).
+		Retry(core.RetrySettings{
+			MaxAttempts:     3,
+			InitialInterval: time.Duration(10 * time.Millisecond),
+		}).
+		Create()
+
+	result := s.Set(ctx, "qwe", 123)
+
+	if result.E != nil {
+		t.Errorf("Test_retry_ShouldRetryTransientError() err = %v", result.E)
+	}
+
+	if flaky.GetCalls() != 3 {
+		t.Errorf("Test_retry_ShouldRetryTransientError() calls = %v, expect %v", flaky.GetCalls(), 3)
+	}
+}
+
+func Test_retry_ShouldNotRetryBusinessError(t *testing.T) {
+	ctx := context.Background()
+
+	flaky := &flakyStateAdapter{failures: 2, err: core.ErrNotFound}
+	s := core.NewStateServiceBuilder().
+		StateAdapter(flaky).
+		Retry(core.RetrySettings{
+			MaxAttempts:     3,
+			InitialInterval: time.Duration(10 * time.Millisecond),
+		}).
+		Create()
+
+	dest := &testModel{}
+	result := s.Get(ctx, "qwe", dest)
+
+	if !errors.Is(result.E, core.ErrNotFound) {
+		t.Errorf("Test_retry_ShouldNotRetryBusinessError() err = %v, expect %v", result.E, core.ErrNotFound)
+	}
+
+	if flaky.GetCalls() != 1 {
+		t.Errorf("Test_retry_ShouldNotRetryBusinessError() calls = %v, expect %v", flaky.GetCalls(), 1)
+	}
+}
+
+func Test_retry_ShouldNotRetryInternalServerError(t *testing.T) {
+	ctx := context.Background()
+
+	flaky := &flakyStateAdapter{failures: 2, err: core.ErrInternalServerError}
+	s := core.NewStateServiceBuilder().
+		StateAdapter(flaky).
+		Retry(core.RetrySettings{
+			MaxAttempts:     3,
+			InitialInterval: time.Duration(10 * time.Millisecond),
+		}).
+		Create()
+
+	result := s.Set(ctx, "qwe", 123)
+
+	if !errors.Is(result.E, core.ErrInternalServerError) {
+		t.Errorf("Test_retry_ShouldNotRetryInternalServerError() err = %v, expect %v", result.E, core.ErrInternalServerError)
+	}
+
+	if flaky.GetCalls() != 1 {
+		t.Errorf("Test_retry_ShouldNotRetryInternalServerError() calls = %v, expect %v", flaky.GetCalls(), 1)
+	}
+
+	if !core.IsTransientError(&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}) {
+		t.Errorf("Test_retry_ShouldNotRetryInternalServerError() expect network error is transient")
+	}
+}
+
+func Test_retry_CustomClassifierShouldBeWorking(t *testing.T) {
+	ctx := context.Background()
+
+	flaky := &flakyStateAdapter{failures: 2, err: errTransient}
+	s := core.NewStateServiceBuilder().
+		StateAdapter(flaky).
+		Retry(core.RetrySettings{
+			MaxAttempts:     3,
+			InitialInterval: time.Duration(10 * time.Millisecond),
+			IsRetryable: func(err error) bool {
+				return false
+			},
+		}).
+		Create()
+
+	result := s.Set(ctx, "qwe", 123)
+
+	if !errors.Is(result.E, errTransient) {
+		t.Errorf("Test_retry_CustomClassifierShouldBeWorking() err = %v, expect %v", result.E, errTransient)
+	}
+
+	if flaky.GetCalls() != 1 {
+		t.Errorf("Test_retry_CustomClassifierShouldBeWorking() calls = %v, expect %v", flaky.GetCalls(), 1)
+	}
+}
+
+func Test_retry_ShouldStopWhenCircuitOpen(t *testing.T) {
+	ctx := context.Background()
+
+	flaky := &flakyStateAdapter{failures: 10, err: errTransient}
+	s := core.NewStateServiceBuilder().
+		StateAdapter(flaky).
+		CircuitBreaker(core.CircuitBreakerSettings{
+			SamplingFailureCount: 2,
+		}).
+		Retry(core.RetrySettings{
+			MaxAttempts:     5,
+			InitialInterval: time.Duration(10 * time.Millisecond),
+		}).
+		Create()
+
+	result := s.Set(ctx, "qwe", 123)
+
+	if !errors.Is(result.E, gobreaker.ErrOpenState) {
+		t.Errorf("Test_retry_ShouldStopWhenCircuitOpen() err = %v, expect %v", result.E, gobreaker.ErrOpenState)
+	}
+
+	if flaky.GetCalls() != 2 {
+		t.Errorf("Test_retry_ShouldStopWhenCircuitOpen() calls = %v, expect %v", flaky.GetCalls(), 2)
+	}
+}
+
+func Test_retry_ShouldRespectContextDeadline(t *testing.T) {
+	timeout := time.Duration(100 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	flaky := &flakyStateAdapter{failures: 10, err: errTransient}
+	s := core.NewStateServiceBuilder().
+		StateAdapter(flaky).
+		Retry(core.RetrySettings{
+			MaxAttempts:     10,
+			InitialInterval: time.Duration(1 * time.Second),
+		}).
+		Create()
+
+	start := time.Now()
+	result := s.Set(ctx, "qwe", 123)
+
+	if !errors.Is(result.E, errTransient) {
+		t.Errorf("Test_retry_ShouldRespectContextDeadline() err = %v, expect %v", result.E, errTransient)
+	}
+
+	if elapsed := time.Since(start); elapsed > timeout {
+		t.Errorf("Test_retry_ShouldRespectContextDeadline() elapsed = %v, expect less than %v", elapsed, timeout)
+	}
+}
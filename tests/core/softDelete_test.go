@@ -0,0 +1,77 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/infrastructure/mocks"
+)
+
+func Test_softDelete_RemoveShouldStampDeleted(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(softDeleteModel), "softDeleteModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := core.WithPrincipal(context.Background(), &core.Principal{UserId: "qwe"})
+
+	dto := new(softDeleteModel)
+	dto.ID = uuid.New()
+	r.Add(ctx, dto)
+
+	if result := r.Remove(ctx, dto.ID); result.E != nil {
+		t.Fatalf("Test_softDelete_RemoveShouldStampDeleted() err = %v", result.E)
+	}
+
+	if result := r.Find(ctx, dto.ID, new(softDeleteModel)); !errors.Is(result.E, core.ErrNotFound) {
+		t.Errorf("Test_softDelete_RemoveShouldStampDeleted() err = %v, expect %v", result.E, core.ErrNotFound)
+	}
+
+	if result := r.Count(ctx); result.V != int64(0) {
+		t.Errorf("Test_softDelete_RemoveShouldStampDeleted() count = %v, expect %v", result.V, 0)
+	}
+
+	deleted := make([]*softDeleteModel, 0)
+	r.ListDeleted(ctx, &deleted)
+
+	if len(deleted) != 1 || deleted[0].DeleteUser != "qwe" || !deleted[0].IsDeleted() {
+		t.Errorf("Test_softDelete_RemoveShouldStampDeleted() deleted = %v", deleted)
+	}
+}
+
+func Test_softDelete_RestoreAndPurge(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(softDeleteModel), "softDeleteModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := context.Background()
+
+	dto := new(softDeleteModel)
+	dto.ID = uuid.New()
+	r.Add(ctx, dto)
+	r.Remove(ctx, dto.ID)
+
+	if result := r.Restore(ctx, dto.ID); result.E != nil {
+		t.Fatalf("Test_softDelete_RestoreAndPurge() err = %v", result.E)
+	}
+
+	if result := r.Find(ctx, dto.ID, new(softDeleteModel)); result.E != nil {
+		t.Errorf("Test_softDelete_RestoreAndPurge() err = %v", result.E)
+	}
+
+	r.Remove(ctx, dto.ID)
+
+	if result := r.Purge(ctx, dto.ID); result.E != nil {
+		t.Errorf("Test_softDelete_RestoreAndPurge() err = %v", result.E)
+	}
+
+	if mock.GetDbCount() != 0 {
+		t.Errorf("Test_softDelete_RestoreAndPurge() db count = %v, expect %v", mock.GetDbCount(), 0)
+	}
+}
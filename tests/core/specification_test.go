@@ -0,0 +1,181 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/infrastructure/mocks"
+)
+
+func Test_specification_IsSatisfiedBy(t *testing.T) {
+	dto := new(testModel)
+	dto.ID = uuid.New()
+	dto.Expect = 123
+	dto.CreateUser = "qwe"
+
+	tests := []struct {
+		name string
+		spec *core.Specification
+		want bool
+	}{
+		{"eq", core.Eq("Expect", 123), true},
+		{"eq promoted", core.Eq("ID", dto.ID), true},
+		{"eq dotted", core.Eq("Entity.CreateUser", "qwe"), true},
+		{"ne", core.Ne("Expect", 123), false},
+		{"in", core.In("Expect", []int{1, 123}), true},
+		{"gt", core.Gt("Expect", 100), true},
+		{"lt", core.Lt("Expect", 100), false},
+		{"like", core.Like("CreateUser", "q_e%"), true},
+		{"and", core.And(core.Gt("Expect", 100), core.Lt("Expect", 200)), true},
+		{"or", core.Or(core.Eq("Expect", 1), core.Eq("Expect", 2)), false},
+		{"not", core.Not(core.Eq("Expect", 1)), true},
+		{"unknown field", core.Eq("Unknown", 1), false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.spec.IsSatisfiedBy(dto); got != tt.want {
+			t.Errorf("Test_specification_IsSatisfiedBy() %v = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_specification_LikeShouldBeReusedConcurrently(t *testing.T) {
+	spec := core.Like("CreateUser", "q_e%")
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			dto := new(testModel)
+			dto.CreateUser = "qwe"
+
+			if i%2 == 1 {
+				dto.CreateUser = "ewq"
+			}
+
+			if got := spec.IsSatisfiedBy(dto); got != (i%2 == 0) {
+				t.Errorf("Test_specification_LikeShouldBeReusedConcurrently() %v = %v, want %v", dto.CreateUser, got, i%2 == 0)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+func Test_specification_MockShouldFilter(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		dto := new(testModel)
+		dto.ID = uuid.New()
+		dto.Expect = i
+
+		r.Add(ctx, dto)
+	}
+
+	spec := core.Or(core.Lt("Expect", 3), core.In("Expect", []int{7, 8}))
+
+	if result := r.CountWithFilter(ctx, spec, nil); result.V != int64(5) {
+		t.Errorf("Test_specification_MockShouldFilter() count = %v, expect %v", result.V, 5)
+	}
+
+	dest := make([]*testModel, 0)
+	r.ListWithFilter(ctx, core.Not(spec), nil, &dest)
+
+	if len(dest) != 5 {
+		t.Errorf("Test_specification_MockShouldFilter() list = %v, expect %v", len(dest), 5)
+	}
+}
+
+func Test_specification_MockShouldFilterByFieldMap(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		dto := new(testModel)
+		dto.ID = uuid.New()
+		dto.Expect = i % 2
+
+		r.Add(ctx, dto)
+	}
+
+	if result := r.CountWithFilter(ctx, map[string]interface{}{"Expect": 1}, nil); result.V != int64(5) {
+		t.Errorf("Test_specification_MockShouldFilterByFieldMap() count = %v, expect %v", result.V, 5)
+	}
+
+	if result := r.CountWithFilter(ctx, "expect = ?", 1); !errors.Is(result.E, core.ErrBadRequest) {
+		t.Errorf("Test_specification_MockShouldFilterByFieldMap() err = %v, expect %v", result.E, core.ErrBadRequest)
+	}
+}
+
+func Test_specification_MockShouldCopyEntities(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := context.Background()
+
+	dto := new(testModel)
+	dto.ID = uuid.New()
+	dto.Expect = 1
+
+	r.Add(ctx, dto)
+
+	dto.Expect = 2
+
+	dest := make([]*testModel, 0)
+	r.ListWithFilter(ctx, nil, nil, &dest)
+
+	if len(dest) != 1 || dest[0].Expect != 1 {
+		t.Errorf("Test_specification_MockShouldCopyEntities() list = %v, expect %v", dest, 1)
+	}
+
+	dest[0].Expect = 3
+
+	found := new(testModel)
+	r.Find(ctx, dto.ID, found)
+
+	if found.Expect != 1 {
+		t.Errorf("Test_specification_MockShouldCopyEntities() expect = %v, expect %v", found.Expect, 1)
+	}
+}
+
+func Test_specification_MockFindShouldRejectAnotherType(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(testModel), "testModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := context.Background()
+
+	dto := new(testModel)
+	dto.ID = uuid.New()
+
+	r.Add(ctx, dto)
+
+	if err := mock.Find(ctx, dto.ID, new(versionModel)); !errors.Is(err, core.ErrInternalServerError) {
+		t.Errorf("Test_specification_MockFindShouldRejectAnotherType() err = %v, expect %v", err, core.ErrInternalServerError)
+	}
+}
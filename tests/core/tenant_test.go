@@ -0,0 +1,129 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/infrastructure/mocks"
+)
+
+func Test_tenant_RepositoryShouldBeScopedByTenant(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(tenantModel), "tenantModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctxA := core.WithTenant(context.Background(), "a")
+	ctxB := core.WithTenant(context.Background(), "b")
+
+	dto := new(tenantModel)
+	dto.ID = uuid.New()
+	dto.TenantId = "b"
+
+	r.Add(ctxA, dto)
+
+	if dto.TenantId != "a" {
+		t.Errorf("Test_tenant_RepositoryShouldBeScopedByTenant() TenantId = %v, expect %v", dto.TenantId, "a")
+	}
+
+	if result := r.Find(ctxB, dto.ID, new(tenantModel)); !errors.Is(result.E, core.ErrNotFound) {
+		t.Errorf("Test_tenant_RepositoryShouldBeScopedByTenant() err = %v, expect %v", result.E, core.ErrNotFound)
+	}
+
+	if result := r.Count(ctxB); result.V != int64(0) {
+		t.Errorf("Test_tenant_RepositoryShouldBeScopedByTenant() count = %v, expect %v", result.V, 0)
+	}
+
+	r.Remove(ctxB, dto.ID)
+
+	if result := r.Find(ctxA, dto.ID, new(tenantModel)); result.E != nil {
+		t.Errorf("Test_tenant_RepositoryShouldBeScopedByTenant() err = %v", result.E)
+	}
+
+	list := make([]*tenantModel, 0)
+
+	if r.List(ctxA, &list); len(list) != 1 {
+		t.Errorf("Test_tenant_RepositoryShouldBeScopedByTenant() list = %v, expect %v", len(list), 1)
+	}
+}
+
+func Test_tenant_FindOutOfScopeShouldNotFillDest(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(tenantModel), "tenantModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(&unscopedQueryAdapter{mock}).
+		Create()
+
+	dto := new(tenantModel)
+	dto.ID = uuid.New()
+	dto.Expect = 123
+
+	r.Add(core.WithTenant(context.Background(), "a"), dto)
+
+	dest := new(tenantModel)
+	result := r.Find(core.WithTenant(context.Background(), "b"), dto.ID, dest)
+
+	if !errors.Is(result.E, core.ErrNotFound) {
+		t.Errorf("Test_tenant_FindOutOfScopeShouldNotFillDest() err = %v, expect %v", result.E, core.ErrNotFound)
+	}
+
+	if dest.ID != uuid.Nil || dest.TenantId != "" || dest.Expect != 0 {
+		t.Errorf("Test_tenant_FindOutOfScopeShouldNotFillDest() dest = %v, expect empty", dest)
+	}
+}
+
+func Test_tenant_MockUpsertShouldRejectAnotherTenant(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(tenantModel), "tenantModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctxA := core.WithTenant(context.Background(), "a")
+	ctxB := core.WithTenant(context.Background(), "b")
+
+	dto := new(tenantModel)
+	dto.ID = uuid.New()
+	dto.Expect = 1
+
+	if result := r.Upsert(ctxA, dto); result.E != nil || result.V != int64(1) {
+		t.Errorf("Test_tenant_MockUpsertShouldRejectAnotherTenant() affected = %v, err = %v, expect %v", result.V, result.E, 1)
+	}
+
+	other := new(tenantModel)
+	other.ID = dto.ID
+	other.Expect = 2
+
+	if result := r.Upsert(ctxB, other); !errors.Is(result.E, core.ErrConflict) {
+		t.Errorf("Test_tenant_MockUpsertShouldRejectAnotherTenant() err = %v, expect %v", result.E, core.ErrConflict)
+	}
+
+	found := new(tenantModel)
+
+	if result := r.Find(ctxA, dto.ID, found); result.E != nil || found.Expect != 1 {
+		t.Errorf("Test_tenant_MockUpsertShouldRejectAnotherTenant() expect = %v, err = %v, expect %v", found.Expect, result.E, 1)
+	}
+}
+
+func Test_tenant_StateServiceShouldPrefixKeys(t *testing.T) {
+	s := core.NewStateServiceBuilder().
+		StateAdapter(mocks.NewMockAdapter()).
+		Create()
+
+	ctxA := core.WithTenant(context.Background(), "a")
+	ctxB := core.WithTenant(context.Background(), "b")
+
+	s.Set(ctxA, "qwe", 123)
+
+	if result := s.Has(ctxA, "qwe"); result.V != true {
+		t.Errorf("Test_tenant_StateServiceShouldPrefixKeys() has = %v, expect %v", result.V, true)
+	}
+
+	if result := s.Has(ctxB, "qwe"); result.V != false {
+		t.Errorf("Test_tenant_StateServiceShouldPrefixKeys() has = %v, expect %v", result.V, false)
+	}
+}
@@ -2,7 +2,10 @@ package core
 
 import (
 	"context"
+	"sync/atomic"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jybbang/go-core-architecture/core"
 )
 
@@ -11,6 +14,24 @@ type testModel struct {
 	Expect int `bson:"expect,omitempty"`
 }
 
+type softDeleteModel struct {
+	core.Entity
+	core.SoftDeleteEntity
+	Expect int `bson:"expect,omitempty"`
+}
+
+type versionModel struct {
+	core.Entity
+	core.VersionEntity
+	Expect int `bson:"expect,omitempty"`
+}
+
+type tenantModel struct {
+	core.Entity
+	core.TenantEntity
+	Expect int `bson:"expect,omitempty"`
+}
+
 type okCommand struct {
 	Expect int
 }
@@ -42,3 +63,141 @@ func okNotificationHandler(ctx context.Context, notification interface{}) error
 func errNotificationHandler(ctx context.Context, notification interface{}) error {
 	return core.ErrForbiddenAcccess
 }
+
+type queryRepositoryAdapter interface {
+	IsConnected() bool
+	Connect(ctx context.Context) error
+	Disconnect()
+	SetModel(model core.Entitier, tableName string)
+	Find(ctx context.Context, id uuid.UUID, dest core.Entitier) error
+	Any(ctx context.Context) (ok bool, err error)
+	AnyWithFilter(ctx context.Context, query interface{}, args interface{}) (ok bool, err error)
+	Count(ctx context.Context) (count int64, err error)
+	CountWithFilter(ctx context.Context, query interface{}, args interface{}) (count int64, err error)
+	List(ctx context.Context, dest interface{}) (err error)
+	ListWithFilter(ctx context.Context, query interface{}, args interface{}, dest interface{}) error
+	ListWithOptions(ctx context.Context, query interface{}, options core.QueryOptions, dest interface{}) error
+	Iterate(ctx context.Context, query interface{}, fn func(entity core.Entitier) error) error
+	Aggregate(ctx context.Context, query interface{}, aggregation core.Aggregation) ([]core.AggregateRow, error)
+}
+
+// unscopedQueryAdapter finds the entities of every tenant like the adapters which are not scoped by tenant
+type unscopedQueryAdapter struct {
+	queryRepositoryAdapter
+}
+
+func (a *unscopedQueryAdapter) Find(ctx context.Context, id uuid.UUID, dest core.Entitier) error {
+	return a.queryRepositoryAdapter.Find(context.Background(), id, dest)
+}
+
+type flakyStateAdapter struct {
+	failures     int32
+	calls        int32
+	connects     int32
+	disconnects  int32
+	connectDelay time.Duration
+	connectErr   error
+	err          error
+}
+
+func (a *flakyStateAdapter) IsConnected() bool {
+	return true
+}
+
+func (a *flakyStateAdapter) Connect(ctx context.Context) error {
+	time.Sleep(a.connectDelay)
+
+	if atomic.AddInt32(&a.connects, 1) > 1 {
+		return a.connectErr
+	}
+
+	return nil
+}
+
+func (a *flakyStateAdapter) Disconnect() {
+	atomic.AddInt32(&a.disconnects, 1)
+}
+
+func (a *flakyStateAdapter) Has(ctx context.Context, key string) bool {
+	return a.Get(ctx, key, nil) == nil
+}
+
+func (a *flakyStateAdapter) Get(ctx context.Context, key string, dest interface{}) error {
+	if atomic.AddInt32(&a.calls, 1) <= a.failures {
+		return a.err
+	}
+
+	return nil
+}
+
+func (a *flakyStateAdapter) Set(ctx context.Context, key string, value interface{}) error {
+	return a.Get(ctx, key, nil)
+}
+
+func (a *flakyStateAdapter) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return a.Get(ctx, key, nil)
+}
+
+func (a *flakyStateAdapter) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return true, a.Get(ctx, key, nil)
+}
+
+func (a *flakyStateAdapter) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	return delta, a.Get(ctx, key, nil)
+}
+
+func (a *flakyStateAdapter) BatchSet(ctx context.Context, kvs []core.KV) error {
+	return a.Get(ctx, "", nil)
+}
+
+func (a *flakyStateAdapter) Delete(ctx context.Context, key string) error {
+	return a.Get(ctx, key, nil)
+}
+
+func (a *flakyStateAdapter) GetCalls() int {
+	return int(atomic.LoadInt32(&a.calls))
+}
+
+func (a *flakyStateAdapter) GetConnects() int {
+	return int(atomic.LoadInt32(&a.connects))
+}
+
+func (a *flakyStateAdapter) GetDisconnects() int {
+	return int(atomic.LoadInt32(&a.disconnects))
+}
+
+type flakyMessagingAdapter struct {
+	failures int32
+	calls    int32
+	err      error
+}
+
+func (a *flakyMessagingAdapter) IsConnected() bool {
+	return true
+}
+
+func (a *flakyMessagingAdapter) Connect(ctx context.Context) error {
+	return nil
+}
+
+func (a *flakyMessagingAdapter) Disconnect() {}
+
+func (a *flakyMessagingAdapter) Publish(ctx context.Context, event core.DomainEventer) error {
+	if atomic.AddInt32(&a.calls, 1) <= a.failures {
+		return a.err
+	}
+
+	return nil
+}
+
+func (a *flakyMessagingAdapter) Subscribe(ctx context.Context, topic string, handler core.ReplyHandler) error {
+	return nil
+}
+
+func (a *flakyMessagingAdapter) Unsubscribe(ctx context.Context, topic string) error {
+	return nil
+}
+
+func (a *flakyMessagingAdapter) GetCalls() int {
+	return int(atomic.LoadInt32(&a.calls))
+}
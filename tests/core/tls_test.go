@@ -0,0 +1,80 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+func Test_tlsSettings_ToTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+
+	disabled := core.TLSSettings{CAFile: certFile}
+
+	if config, err := disabled.ToTLSConfig(); config != nil || err != nil {
+		t.Errorf("Test_tlsSettings_ToTLSConfig() disabled = %v, %v, expect nil", config, err)
+	}
+
+	settings := core.TLSSettings{
+		Enabled:    true,
+		CAFile:     certFile,
+		CertFile:   certFile,
+		KeyFile:    keyFile,
+		ServerName: "localhost",
+	}
+
+	config, err := settings.ToTLSConfig()
+	if err != nil {
+		t.Fatalf("Test_tlsSettings_ToTLSConfig() err = %v", err)
+	}
+
+	if config.RootCAs == nil || len(config.Certificates) != 1 || config.ServerName != "localhost" {
+		t.Errorf("Test_tlsSettings_ToTLSConfig() config = %v", config)
+	}
+
+	missing := core.TLSSettings{Enabled: true, CAFile: filepath.Join(dir, "missing.pem")}
+
+	if _, err := missing.ToTLSConfig(); err == nil {
+		t.Errorf("Test_tlsSettings_ToTLSConfig() err = %v, expect error", err)
+	}
+}
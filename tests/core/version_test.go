@@ -0,0 +1,54 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/infrastructure/mocks"
+)
+
+func Test_version_UpdateShouldConflictWithStaleVersion(t *testing.T) {
+	mock := mocks.NewMockAdapter()
+	r := core.NewRepositoryServiceBuilder(new(versionModel), "versionModel").
+		CommandRepositoryAdapter(mock).
+		QueryRepositoryAdapter(mock).
+		Create()
+
+	ctx := context.Background()
+
+	dto := new(versionModel)
+	dto.ID = uuid.New()
+	r.Add(ctx, dto)
+
+	if dto.Version != 1 {
+		t.Errorf("Test_version_UpdateShouldConflictWithStaleVersion() version = %v, expect %v", dto.Version, 1)
+	}
+
+	first := new(versionModel)
+	second := new(versionModel)
+	r.Find(ctx, dto.ID, first)
+	r.Find(ctx, dto.ID, second)
+
+	first.Expect = 1
+
+	if result := r.Update(ctx, first); result.E != nil {
+		t.Fatalf("Test_version_UpdateShouldConflictWithStaleVersion() err = %v", result.E)
+	}
+
+	if first.Version != 2 {
+		t.Errorf("Test_version_UpdateShouldConflictWithStaleVersion() version = %v, expect %v", first.Version, 2)
+	}
+
+	second.Expect = 2
+
+	if result := r.Update(ctx, second); !errors.Is(result.E, core.ErrConflict) {
+		t.Errorf("Test_version_UpdateShouldConflictWithStaleVersion() err = %v, expect %v", result.E, core.ErrConflict)
+	}
+
+	if second.Version != 1 {
+		t.Errorf("Test_version_UpdateShouldConflictWithStaleVersion() version = %v, expect %v", second.Version, 1)
+	}
+}
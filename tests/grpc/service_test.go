@@ -0,0 +1,177 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	coregrpc "github.com/jybbang/go-core-architecture/infrastructure/grpc"
+	"github.com/jybbang/go-core-architecture/infrastructure/jwt"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/jybbang/go-core-architecture/core"
+)
+
+func newTestConn(t *testing.T) *grpc.ClientConn {
+	return newTestConnWith(t, grpc.UnaryInterceptor(coregrpc.UnaryServerInterceptor(coregrpc.ServiceSettings{})))
+}
+
+func newTestConnWith(t *testing.T, opts ...grpc.ServerOption) *grpc.ClientConn {
+	useMediator()
+
+	listener := bufconn.Listen(1024 * 1024)
+
+	server := grpc.NewServer(opts...)
+
+	coregrpc.NewService("test.Greeter").
+		Handle("Echo", new(wrapperspb.StringValue), new(wrapperspb.StringValue)).
+		HandleMapped("Greet", new(wrapperspb.StringValue), new(emptypb.Empty), func(ctx context.Context, in proto.Message) (core.Request, error) {
+			return &greetCommand{Name: in.(*wrapperspb.StringValue).Value}, nil
+		}).
+		HandleMapped("Ping", new(wrapperspb.StringValue), new(wrapperspb.StringValue), func(ctx context.Context, in proto.Message) (core.Request, error) {
+			return &greetCommand{Name: in.(*wrapperspb.StringValue).Value}, nil
+		}).
+		HandleMapped("Farewell", new(wrapperspb.StringValue), new(emptypb.Empty), func(ctx context.Context, in proto.Message) (core.Request, error) {
+			return &farewellCommand{Name: in.(*wrapperspb.StringValue).Value}, nil
+		}).
+		Register(server)
+
+	go server.Serve(listener)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("newTestConn() err = %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+
+	return conn
+}
+
+func Test_service_ShouldPropagateUserId(t *testing.T) {
+	conn := newTestConn(t)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "userid", "tester")
+
+	out := new(wrapperspb.StringValue)
+
+	if err := conn.Invoke(ctx, "/test.Greeter/Echo", wrapperspb.String("qwe"), out); err != nil {
+		t.Fatalf("Test_service_ShouldPropagateUserId() err = %v", err)
+	}
+
+	if out.Value != "tester:qwe" {
+		t.Errorf("Test_service_ShouldPropagateUserId() out = %v, want %v", out.Value, "tester:qwe")
+	}
+}
+
+func Test_service_ShouldNotTrustUserIdWithVerifier(t *testing.T) {
+	conn := newTestConnWith(t, grpc.ChainUnaryInterceptor(
+		jwt.NewVerifier(jwt.JwtSettings{Secret: []byte("secret")}).UnaryServerInterceptor(),
+		coregrpc.UnaryServerInterceptor(coregrpc.ServiceSettings{})))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "userid", "tester")
+
+	out := new(wrapperspb.StringValue)
+
+	if err := conn.Invoke(ctx, "/test.Greeter/Echo", wrapperspb.String("qwe"), out); err != nil {
+		t.Fatalf("Test_service_ShouldNotTrustUserIdWithVerifier() err = %v", err)
+	}
+
+	if out.Value != ":qwe" {
+		t.Errorf("Test_service_ShouldNotTrustUserIdWithVerifier() out = %v, want %v", out.Value, ":qwe")
+	}
+}
+
+func Test_service_ShouldMapErrorsToStatusCodes(t *testing.T) {
+	conn := newTestConn(t)
+
+	tests := []struct {
+		name string
+		want codes.Code
+	}{
+		{"qwe", codes.OK},
+		{"", codes.InvalidArgument},
+		{"unknown", codes.NotFound},
+		{"duplicated", codes.AlreadyExists},
+	}
+
+	for _, tt := range tests {
+		err := conn.Invoke(context.Background(), "/test.Greeter/Greet", wrapperspb.String(tt.name), new(emptypb.Empty))
+
+		if got := status.Code(err); got != tt.want {
+			t.Errorf("Test_service_ShouldMapErrorsToStatusCodes() %v code = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func Test_service_ShouldStartOneSpanWithInterceptor(t *testing.T) {
+	tracer := mocktracer.New()
+
+	previous := opentracing.GlobalTracer()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(previous)
+
+	conn := newTestConn(t)
+
+	if err := conn.Invoke(context.Background(), "/test.Greeter/Echo", wrapperspb.String("qwe"), new(wrapperspb.StringValue)); err != nil {
+		t.Fatalf("Test_service_ShouldStartOneSpanWithInterceptor() err = %v", err)
+	}
+
+	spans := 0
+
+	for _, v := range tracer.FinishedSpans() {
+		if v.OperationName == "/test.Greeter/Echo" {
+			spans++
+		}
+	}
+
+	if spans != 1 {
+		t.Errorf("Test_service_ShouldStartOneSpanWithInterceptor() spans = %v, want %v", spans, 1)
+	}
+}
+
+func Test_service_NilResultShouldBeDeclaredMessage(t *testing.T) {
+	var resp interface{}
+
+	conn := newTestConnWith(t, grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		out, err := handler(ctx, req)
+
+		resp = out
+
+		return out, err
+	}))
+
+	if err := conn.Invoke(context.Background(), "/test.Greeter/Ping", wrapperspb.String("qwe"), new(wrapperspb.StringValue)); err != nil {
+		t.Fatalf("Test_service_NilResultShouldBeDeclaredMessage() err = %v", err)
+	}
+
+	if _, ok := resp.(*wrapperspb.StringValue); !ok {
+		t.Errorf("Test_service_NilResultShouldBeDeclaredMessage() resp = %T, want %T", resp, new(wrapperspb.StringValue))
+	}
+}
+
+func Test_service_NotAddedHandlerShouldBeUnimplemented(t *testing.T) {
+	conn := newTestConn(t)
+
+	err := conn.Invoke(context.Background(), "/test.Greeter/Farewell", wrapperspb.String("qwe"), new(emptypb.Empty))
+
+	if got := status.Code(err); got != codes.Unimplemented {
+		t.Errorf("Test_service_NotAddedHandlerShouldBeUnimplemented() code = %v, want %v", got, codes.Unimplemented)
+	}
+}
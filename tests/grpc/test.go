@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type greetCommand struct {
+	Name string
+}
+
+// farewellCommand is mapped without the handler
+type farewellCommand struct {
+	Name string
+}
+
+var mediatorSync sync.Once
+
+func useMediator() {
+	mediatorSync.Do(func() {
+		core.NewMediatorBuilder().
+			AddHandler(new(wrapperspb.StringValue), echoHandler).
+			AddHandler(new(greetCommand), greetCommandHandler).
+			Build()
+	})
+}
+
+func echoHandler(ctx context.Context, request interface{}) core.Result {
+	userId, _ := core.UserIdFromContext(ctx)
+
+	return core.Result{V: wrapperspb.String(userId + ":" + request.(*wrapperspb.StringValue).Value)}
+}
+
+func greetCommandHandler(ctx context.Context, request interface{}) core.Result {
+	command := request.(*greetCommand)
+
+	switch command.Name {
+	case "":
+		return core.Result{E: fmt.Errorf("%w name is required", core.ErrBadRequest)}
+	case "unknown":
+		return core.Result{E: fmt.Errorf("%w %s", core.ErrNotFound, command.Name)}
+	case "duplicated":
+		return core.Result{E: core.ErrConflict}
+	default:
+		return core.Result{}
+	}
+}
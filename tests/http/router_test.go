@@ -0,0 +1,164 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jybbang/go-core-architecture/core"
+	corehttp "github.com/jybbang/go-core-architecture/infrastructure/http"
+	"github.com/jybbang/go-core-architecture/infrastructure/jwt"
+)
+
+func newTestServer() *httptest.Server {
+	useMediator()
+
+	router := corehttp.NewRouter().
+		Get("/users/{id}", new(getUserQuery)).
+		Post("/users", new(createUserCommand)).
+		Delete("/users/{id}", new(removeUserCommand)).
+		Put("/users/{id}", new(updateUserCommand))
+
+	return httptest.NewServer(router)
+}
+
+func Test_router_ShouldBindPathAndQuery(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	id := uuid.New()
+
+	resp, err := http.Get(server.URL + "/users/" + id.String() + "?verbose=true")
+	if err != nil {
+		t.Fatalf("Test_router_ShouldBindPathAndQuery() err = %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Test_router_ShouldBindPathAndQuery() status = %v, want %v", resp.StatusCode, http.StatusOK)
+	}
+
+	result := new(user)
+	json.NewDecoder(resp.Body).Decode(result)
+
+	if result.ID != id || !result.Verbose {
+		t.Errorf("Test_router_ShouldBindPathAndQuery() result = %v", result)
+	}
+}
+
+func Test_router_ShouldBindBodyAndUserId(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/users?tag=a&tag=b", strings.NewReader(`{"Name":"qwe"}`))
+	req.Header.Set("userid", "tester")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Test_router_ShouldBindBodyAndUserId() err = %v", err)
+	}
+	defer resp.Body.Close()
+
+	result := new(user)
+	json.NewDecoder(resp.Body).Decode(result)
+
+	if result.Name != "qwe" || len(result.Tags) != 2 || result.Creator != "tester" {
+		t.Errorf("Test_router_ShouldBindBodyAndUserId() result = %v", result)
+	}
+}
+
+func Test_router_ShouldNotTrustUserIdWithVerifier(t *testing.T) {
+	useMediator()
+
+	router := corehttp.NewRouter().
+		Post("/users", new(createUserCommand))
+
+	server := httptest.NewServer(jwt.NewVerifier(jwt.JwtSettings{Secret: []byte("secret")}).HttpMiddleware(router))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/users", strings.NewReader(`{"Name":"qwe"}`))
+	req.Header.Set("userid", "tester")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Test_router_ShouldNotTrustUserIdWithVerifier() err = %v", err)
+	}
+	defer resp.Body.Close()
+
+	result := new(user)
+	json.NewDecoder(resp.Body).Decode(result)
+
+	if result.Name != "qwe" || result.Creator != "" {
+		t.Errorf("Test_router_ShouldNotTrustUserIdWithVerifier() result = %v", result)
+	}
+}
+
+func Test_router_CustomUserIdHeaderShouldStampEntity(t *testing.T) {
+	useMediator()
+
+	router := corehttp.NewRouterWithSettings(corehttp.RouterSettings{UserIdHeader: "X-User-Id"}).
+		Post("/users", new(createUserCommand))
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/users", strings.NewReader(`{"Name":"qwe"}`))
+	req.Header.Set("x-user-id", "tester")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Test_router_CustomUserIdHeaderShouldStampEntity() err = %v", err)
+	}
+	defer resp.Body.Close()
+
+	result := new(user)
+	json.NewDecoder(resp.Body).Decode(result)
+
+	if result.Creator != "tester" {
+		t.Errorf("Test_router_CustomUserIdHeaderShouldStampEntity() Creator = %v, expect %v", result.Creator, "tester")
+	}
+}
+
+func Test_router_ShouldWriteProblem(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"validation", http.MethodPost, "/users", `{}`, http.StatusBadRequest},
+		{"invalid body", http.MethodPost, "/users", `{`, http.StatusBadRequest},
+		{"invalid path", http.MethodGet, "/users/qwe", "", http.StatusBadRequest},
+		{"not found", http.MethodDelete, "/users/qwe", "", http.StatusNotFound},
+		{"no route", http.MethodGet, "/orders", "", http.StatusNotFound},
+		{"method not allowed", http.MethodPut, "/users", "", http.StatusMethodNotAllowed},
+		{"not implemented", http.MethodPut, "/users/qwe", `{}`, http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, server.URL+tt.path, strings.NewReader(tt.body))
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Test_router_ShouldWriteProblem() err = %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("Test_router_ShouldWriteProblem() status = %v, want %v", resp.StatusCode, tt.want)
+			}
+
+			if resp.Header.Get("Content-Type") != core.ProblemContentType {
+				t.Errorf("Test_router_ShouldWriteProblem() content type = %v", resp.Header.Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/infrastructure/mocks"
+)
+
+type getUserQuery struct {
+	ID      uuid.UUID `path:"id"`
+	Verbose bool      `query:"verbose"`
+}
+
+type createUserCommand struct {
+	Name   string
+	Tags   []string `query:"tag"`
+	UserId string
+}
+
+type removeUserCommand struct {
+	ID string `path:"id"`
+}
+
+// updateUserCommand is routed without the handler
+type updateUserCommand struct {
+	ID string `path:"id"`
+}
+
+type user struct {
+	ID      uuid.UUID
+	Name    string
+	Verbose bool
+	Tags    []string
+	Creator string
+}
+
+type userModel struct {
+	core.Entity
+	Name string
+}
+
+var mediatorSync sync.Once
+
+var usersAdapter = mocks.NewMockAdapter()
+
+var users = core.NewRepositoryServiceBuilder(new(userModel), "users").
+	CommandRepositoryAdapter(usersAdapter).
+	QueryRepositoryAdapter(usersAdapter).
+	Create()
+
+func useMediator() {
+	mediatorSync.Do(func() {
+		core.NewMediatorBuilder().
+			AddHandler(new(getUserQuery), getUserQueryHandler).
+			AddHandler(new(createUserCommand), createUserCommandHandler).
+			AddHandler(new(removeUserCommand), removeUserCommandHandler).
+			Build()
+	})
+}
+
+func getUserQueryHandler(ctx context.Context, request interface{}) core.Result {
+	query := request.(*getUserQuery)
+
+	return core.Result{V: &user{ID: query.ID, Verbose: query.Verbose}}
+}
+
+func createUserCommandHandler(ctx context.Context, request interface{}) core.Result {
+	command := request.(*createUserCommand)
+
+	if command.Name == "" {
+		return core.Result{E: core.NewValidationError(core.FieldError{
+			Field:     "Name",
+			Namespace: "createUserCommand.Name",
+			Tag:       "required",
+			Message:   "Name is a required field",
+		})}
+	}
+
+	entity := &userModel{Name: command.Name}
+	entity.ID = uuid.New()
+
+	if result := users.Add(ctx, entity); result.E != nil {
+		return result
+	}
+
+	return core.Result{V: &user{ID: entity.ID, Name: command.Name, Tags: command.Tags, Creator: entity.CreateUser}}
+}
+
+func removeUserCommandHandler(ctx context.Context, request interface{}) core.Result {
+	command := request.(*removeUserCommand)
+
+	return core.Result{E: fmt.Errorf("%w user %s", core.ErrNotFound, command.ID)}
+}
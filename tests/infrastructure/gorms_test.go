@@ -342,10 +342,11 @@ func Test_gormsCommandRepositoryService_Remove(t *testing.T) {
 
 	time.Sleep(1 * time.Second)
 
+	// removing the removed entity is not found
 	result := r.Remove(ctx, dto.ID)
 
-	if result.E != nil {
-		t.Errorf("Test_gormsCommandRepositoryService_Remove() err = %v", result.E)
+	if !errors.Is(result.E, core.ErrNotFound) {
+		t.Errorf("Test_gormsCommandRepositoryService_Remove() err = %v, expect %v", result.E, core.ErrNotFound)
 	}
 
 	dto2 := new(testModel)
@@ -0,0 +1,298 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/infrastructure/memory"
+	"github.com/sony/gobreaker"
+)
+
+var errMemoryTransient = fmt.Errorf("%w transient error", core.ErrUnavailable)
+
+func Test_memoryRepositoryService_ShouldIsolateStoredEntities(t *testing.T) {
+	ctx := context.Background()
+
+	mem := memory.NewMemoryAdapter(memory.MemorySettings{})
+	r := core.NewRepositoryServiceBuilder(new(testModel), "T_TESTMODEL").
+		CommandRepositoryAdapter(mem).
+		QueryRepositoryAdapter(mem).
+		Create()
+
+	dto := new(testModel)
+	dto.ID = uuid.New()
+	dto.Expect = 123
+
+	if result := r.Add(ctx, dto); result.E != nil {
+		t.Fatalf("Test_memoryRepositoryService_ShouldIsolateStoredEntities() err = %v", result.E)
+	}
+
+	dto.Expect = 456
+
+	listed := make([]*testModel, 0)
+	r.List(ctx, &listed)
+
+	listed[0].Expect = 789
+
+	dest := new(testModel)
+	r.Find(ctx, dto.ID, dest)
+
+	if dest.Expect != 123 {
+		t.Errorf("Test_memoryRepositoryService_ShouldIsolateStoredEntities() expect = %v, expect %v", dest.Expect, 123)
+	}
+}
+
+func Test_memoryRepositoryService_ShouldEvaluateQueries(t *testing.T) {
+	ctx := context.Background()
+
+	mem := memory.NewMemoryAdapter(memory.MemorySettings{})
+	r := core.NewRepositoryServiceBuilder(new(testModel), "T_TESTMODEL").
+		CommandRepositoryAdapter(mem).
+		QueryRepositoryAdapter(mem).
+		Create()
+
+	for i := 1; i <= 5; i++ {
+		dto := new(testModel)
+		dto.ID = uuid.New()
+		dto.Expect = i
+
+		r.Add(ctx, dto)
+	}
+
+	if result := r.CountWithFilter(ctx, core.Gt("Expect", 2), nil); result.V != int64(3) {
+		t.Errorf("Test_memoryRepositoryService_ShouldEvaluateQueries() count = %v, expect %v", result.V, 3)
+	}
+
+	filtered := make([]*testModel, 0)
+
+	if result := r.ListWithFilter(ctx, map[string]interface{}{"Expect": 4}, nil, &filtered); result.E != nil || len(filtered) != 1 {
+		t.Errorf("Test_memoryRepositoryService_ShouldEvaluateQueries() filtered = %v, err = %v", len(filtered), result.E)
+	}
+
+	if result := r.CountWithFilter(ctx, "expect = ?", 4); !errors.Is(result.E, core.ErrBadRequest) {
+		t.Errorf("Test_memoryRepositoryService_ShouldEvaluateQueries() err = %v, expect %v", result.E, core.ErrBadRequest)
+	}
+
+	paged := make([]*testModel, 0)
+
+	r.Page(ctx, nil, core.PageRequest{
+		OrderBy: []core.Order{{Field: "Expect", Descending: true}},
+		Offset:  1,
+		Limit:   2,
+	}, &paged)
+
+	if len(paged) != 2 || paged[0].Expect != 4 || paged[1].Expect != 3 {
+		t.Errorf("Test_memoryRepositoryService_ShouldEvaluateQueries() paged = %v, expect %v", len(paged), 2)
+	}
+}
+
+func Test_memoryRepositoryService_ShouldRejectUniqueViolation(t *testing.T) {
+	ctx := context.Background()
+
+	mem := memory.NewMemoryAdapter(memory.MemorySettings{})
+	r := core.NewRepositoryServiceBuilder(new(indexModel), "T_INDEXMODEL").
+		CommandRepositoryAdapter(mem).
+		QueryRepositoryAdapter(mem).
+		Create()
+
+	dto := new(indexModel)
+	dto.ID = uuid.New()
+	dto.Code = "a"
+
+	r.Add(ctx, dto)
+
+	duplicated := new(indexModel)
+	duplicated.ID = uuid.New()
+	duplicated.Code = "a"
+
+	var coreError *core.Error
+
+	result := r.AddRange(ctx, []core.Entitier{
+		&indexModel{Entity: core.Entity{ID: uuid.New()}, Code: "b"},
+		duplicated,
+	})
+
+	if !errors.As(result.E, &coreError) || coreError.Code != core.CodeUniqueViolation {
+		t.Errorf("Test_memoryRepositoryService_ShouldRejectUniqueViolation() err = %v, expect %v", result.E, core.CodeUniqueViolation)
+	}
+
+	if result := r.Count(ctx); result.V != int64(1) {
+		t.Errorf("Test_memoryRepositoryService_ShouldRejectUniqueViolation() count = %v, expect %v", result.V, 1)
+	}
+}
+
+func Test_memoryRepositoryService_ShouldRollbackTransaction(t *testing.T) {
+	ctx := context.Background()
+
+	mem := memory.NewMemoryAdapter(memory.MemorySettings{})
+	r := core.NewRepositoryServiceBuilder(new(testModel), "T_TESTMODEL").
+		CommandRepositoryAdapter(mem).
+		QueryRepositoryAdapter(mem).
+		Create()
+
+	dto := new(testModel)
+	dto.ID = uuid.New()
+	dto.Expect = 123
+
+	r.Add(ctx, dto)
+
+	err := mem.Transaction(ctx, func(ctx context.Context) error {
+		added := new(testModel)
+		added.ID = uuid.New()
+
+		if result := r.Add(ctx, added); result.E != nil {
+			return result.E
+		}
+
+		if result := r.Patch(ctx, dto.ID, map[string]interface{}{"Expect": 456}); result.E != nil {
+			return result.E
+		}
+
+		return core.ErrConflict
+	})
+
+	if !errors.Is(err, core.ErrConflict) {
+		t.Errorf("Test_memoryRepositoryService_ShouldRollbackTransaction() err = %v, expect %v", err, core.ErrConflict)
+	}
+
+	if result := r.Count(ctx); result.V != int64(1) {
+		t.Errorf("Test_memoryRepositoryService_ShouldRollbackTransaction() count = %v, expect %v", result.V, 1)
+	}
+
+	dest := new(testModel)
+	r.Find(ctx, dto.ID, dest)
+
+	if dest.Expect != 123 {
+		t.Errorf("Test_memoryRepositoryService_ShouldRollbackTransaction() expect = %v, expect %v", dest.Expect, 123)
+	}
+}
+
+func Test_memoryRepositoryService_ShouldRetryInjectedFailure(t *testing.T) {
+	ctx := context.Background()
+
+	mem := memory.NewMemoryAdapter(memory.MemorySettings{})
+	r := core.NewRepositoryServiceBuilder(new(testModel), "T_TESTMODEL").
+		CommandRepositoryAdapter(mem).
+		QueryRepositoryAdapter(mem).
+		Retry(core.RetrySettings{
+			MaxAttempts:     3,
+			InitialInterval: time.Duration(10 * time.Millisecond),
+		}).
+		Create()
+
+	calls := 0
+
+	mem.InjectFailure(func(operation string) error {
+		if operation != "AddRange" {
+			return nil
+		}
+
+		if calls++; calls <= 2 {
+			return errMemoryTransient
+		}
+
+		return nil
+	})
+
+	dto := new(testModel)
+	dto.ID = uuid.New()
+
+	if result := r.Add(ctx, dto); result.E != nil {
+		t.Errorf("Test_memoryRepositoryService_ShouldRetryInjectedFailure() err = %v", result.E)
+	}
+
+	if calls != 3 {
+		t.Errorf("Test_memoryRepositoryService_ShouldRetryInjectedFailure() calls = %v, expect %v", calls, 3)
+	}
+}
+
+func Test_memoryRepositoryService_ShouldOpenCircuitByInjectedFailure(t *testing.T) {
+	ctx := context.Background()
+
+	mem := memory.NewMemoryAdapter(memory.MemorySettings{})
+	r := core.NewRepositoryServiceBuilder(new(testModel), "T_TESTMODEL").
+		CommandRepositoryAdapter(mem).
+		QueryRepositoryAdapter(mem).
+		CircuitBreaker(core.CircuitBreakerSettings{
+			SamplingFailureCount: 2,
+		}).
+		Create()
+
+	mem.FailNext(2, errMemoryTransient)
+
+	r.Count(ctx)
+	r.Count(ctx)
+
+	if result := r.Count(ctx); !errors.Is(result.E, gobreaker.ErrOpenState) {
+		t.Errorf("Test_memoryRepositoryService_ShouldOpenCircuitByInjectedFailure() err = %v, expect %v", result.E, gobreaker.ErrOpenState)
+	}
+}
+
+func Test_memoryRepositoryService_ShouldTimeoutByInjectedLatency(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(50*time.Millisecond))
+	defer cancel()
+
+	mem := memory.NewMemoryAdapter(memory.MemorySettings{})
+	r := core.NewRepositoryServiceBuilder(new(testModel), "T_TESTMODEL").
+		CommandRepositoryAdapter(mem).
+		QueryRepositoryAdapter(mem).
+		Create()
+
+	mem.InjectLatency(time.Duration(1 * time.Second))
+
+	if result := r.Count(ctx); !errors.Is(result.E, context.DeadlineExceeded) {
+		t.Errorf("Test_memoryRepositoryService_ShouldTimeoutByInjectedLatency() err = %v, expect %v", result.E, context.DeadlineExceeded)
+	}
+}
+
+func Test_memoryRepositoryService_SoftRemoveRangeShouldUpdateOnce(t *testing.T) {
+	ctx := context.Background()
+
+	mem := memory.NewMemoryAdapter(memory.MemorySettings{})
+	r := core.NewRepositoryServiceBuilder(new(softDeleteModel), "T_SOFTDELETEMODEL").
+		CommandRepositoryAdapter(mem).
+		QueryRepositoryAdapter(mem).
+		Create()
+
+	ids := make([]uuid.UUID, 0)
+
+	for i := 0; i < 3; i++ {
+		dto := new(softDeleteModel)
+		dto.ID = uuid.New()
+
+		r.Add(ctx, dto)
+
+		ids = append(ids, dto.ID)
+	}
+
+	operations := make([]string, 0)
+
+	mem.InjectFailure(func(operation string) error {
+		operations = append(operations, operation)
+
+		return nil
+	})
+
+	if result := r.RemoveRange(ctx, ids); result.E != nil {
+		t.Errorf("Test_memoryRepositoryService_SoftRemoveRangeShouldUpdateOnce() err = %v", result.E)
+	}
+
+	if len(operations) != 1 || operations[0] != "UpdateWhere" {
+		t.Errorf("Test_memoryRepositoryService_SoftRemoveRangeShouldUpdateOnce() operations = %v, expect %v", operations, []string{"UpdateWhere"})
+	}
+
+	mem.InjectFailure(nil)
+
+	if result := r.Count(ctx); result.V != int64(0) {
+		t.Errorf("Test_memoryRepositoryService_SoftRemoveRangeShouldUpdateOnce() count = %v, expect %v", result.V, 0)
+	}
+
+	if result := r.RemoveRange(ctx, []uuid.UUID{ids[0], uuid.New()}); !errors.Is(result.E, core.ErrNotFound) {
+		t.Errorf("Test_memoryRepositoryService_SoftRemoveRangeShouldUpdateOnce() err = %v, expect %v", result.E, core.ErrNotFound)
+	}
+}
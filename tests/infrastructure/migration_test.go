@@ -0,0 +1,149 @@
+package infrastructure
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/uuid"
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/infrastructure/gorms"
+	"gorm.io/gorm"
+)
+
+func Test_sqliteMigration_AutoMigrateShouldAddColumns(t *testing.T) {
+	ctx := context.Background()
+	connectionString := filepath.Join(t.TempDir(), "test.db")
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: connectionString,
+		CanCreateTable:   true,
+	})
+	core.NewRepositoryServiceBuilder(new(testModel), "T_MIGRATIONMODEL").
+		CommandRepositoryAdapter(sqlite).
+		QueryRepositoryAdapter(sqlite).
+		Create()
+
+	migrated := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: connectionString,
+		AutoMigrate:      true,
+	})
+	r := core.NewRepositoryServiceBuilder(new(migrationModel), "T_MIGRATIONMODEL").
+		CommandRepositoryAdapter(migrated).
+		QueryRepositoryAdapter(migrated).
+		Create()
+
+	dto := new(migrationModel)
+	dto.ID = uuid.New()
+	dto.Note = "note"
+
+	if result := r.Add(ctx, dto); result.E != nil {
+		t.Fatalf("Test_sqliteMigration_AutoMigrateShouldAddColumns() err = %v", result.E)
+	}
+
+	dto2 := new(migrationModel)
+
+	if result := r.Find(ctx, dto.ID, dto2); result.E != nil || dto2.Note != "note" {
+		t.Errorf("Test_sqliteMigration_AutoMigrateShouldAddColumns() result = %v, err = %v", dto2, result.E)
+	}
+}
+
+func Test_sqliteMigration_ShouldMigrateUpAndDown(t *testing.T) {
+	ctx := context.Background()
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(t.TempDir(), "test.db"),
+		Migrations: []gorms.Migration{
+			gorms.SqlMigration("002", "index_expect",
+				"CREATE INDEX idx_{{table}}_expect ON {{table}} (expect);",
+				"DROP INDEX idx_{{table}}_expect;"),
+			{
+				Version: "001",
+				Name:    "create_table",
+				Up: func(tx *gorm.DB) error {
+					return tx.Migrator().CreateTable(new(testModel))
+				},
+				Down: func(tx *gorm.DB) error {
+					return tx.Migrator().DropTable(tx.Statement.Table)
+				},
+			},
+		},
+	})
+	r := core.NewRepositoryServiceBuilder(new(testModel), "T_TESTMODEL").
+		CommandRepositoryAdapter(sqlite).
+		QueryRepositoryAdapter(sqlite).
+		Create()
+
+	statements, err := sqlite.MigrateUpDryRun(ctx)
+
+	if err != nil || len(statements) != 4 || !strings.HasPrefix(statements[0], "CREATE TABLE `T_TESTMODEL`") || !strings.HasPrefix(statements[2], "CREATE INDEX idx_T_TESTMODEL_expect") {
+		t.Errorf("Test_sqliteMigration_ShouldMigrateUpAndDown() statements = %v, err = %v", statements, err)
+	}
+
+	if pending, err := sqlite.PendingMigrations(ctx); err != nil || !reflect.DeepEqual(pending, []string{"001", "002"}) {
+		t.Errorf("Test_sqliteMigration_ShouldMigrateUpAndDown() pending = %v, err = %v", pending, err)
+	}
+
+	if applied, err := sqlite.MigrateUp(ctx); err != nil || !reflect.DeepEqual(applied, []string{"001", "002"}) {
+		t.Fatalf("Test_sqliteMigration_ShouldMigrateUpAndDown() applied = %v, err = %v", applied, err)
+	}
+
+	dto := new(testModel)
+	dto.ID = uuid.New()
+
+	if result := r.Add(ctx, dto); result.E != nil {
+		t.Errorf("Test_sqliteMigration_ShouldMigrateUpAndDown() err = %v", result.E)
+	}
+
+	if reverted, err := sqlite.MigrateDown(ctx, 1); err != nil || !reflect.DeepEqual(reverted, []string{"002"}) {
+		t.Errorf("Test_sqliteMigration_ShouldMigrateUpAndDown() reverted = %v, err = %v", reverted, err)
+	}
+
+	if pending, err := sqlite.PendingMigrations(ctx); err != nil || !reflect.DeepEqual(pending, []string{"002"}) {
+		t.Errorf("Test_sqliteMigration_ShouldMigrateUpAndDown() pending = %v, err = %v", pending, err)
+	}
+
+	if applied, err := sqlite.MigrateUp(ctx); err != nil || !reflect.DeepEqual(applied, []string{"002"}) {
+		t.Errorf("Test_sqliteMigration_ShouldMigrateUpAndDown() applied = %v, err = %v", applied, err)
+	}
+}
+
+func Test_sqliteMigration_ShouldLoadSqlFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/002_index.up.sql":   {Data: []byte("CREATE INDEX idx_expect ON {{table}} (expect);")},
+		"migrations/002_index.down.sql": {Data: []byte("DROP INDEX idx_expect;")},
+		"migrations/001_table.up.sql":   {Data: []byte("CREATE TABLE {{table}} (\n  id TEXT PRIMARY KEY,\n  expect INTEGER\n);\nINSERT INTO {{table}} VALUES ('a', 1);")},
+		"migrations/README.md":          {Data: []byte("ignored")},
+	}
+
+	migrations, err := gorms.LoadSqlMigrations(fsys, "migrations")
+
+	if err != nil || len(migrations) != 2 {
+		t.Fatalf("Test_sqliteMigration_ShouldLoadSqlFiles() migrations = %v, err = %v", migrations, err)
+	}
+
+	if migrations[0].Version != "001" || migrations[0].Name != "table" || migrations[0].Down != nil || migrations[1].Down == nil {
+		t.Errorf("Test_sqliteMigration_ShouldLoadSqlFiles() migrations = %v", migrations)
+	}
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(t.TempDir(), "test.db"),
+		Migrations:       migrations,
+		MigrateOnConnect: true,
+	})
+	r := core.NewRepositoryServiceBuilder(new(testModel), "T_RAW").
+		CommandRepositoryAdapter(sqlite).
+		QueryRepositoryAdapter(sqlite).
+		Create()
+
+	if pending, err := sqlite.PendingMigrations(context.Background()); err != nil || len(pending) != 0 {
+		t.Errorf("Test_sqliteMigration_ShouldLoadSqlFiles() pending = %v, err = %v", pending, err)
+	}
+
+	if result := r.Count(context.Background()); result.E != nil || result.V.(int64) != 1 {
+		t.Errorf("Test_sqliteMigration_ShouldLoadSqlFiles() count = %v, err = %v", result.V, result.E)
+	}
+}
@@ -0,0 +1,775 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/infrastructure/gorms"
+)
+
+func Test_sqliteRepositoryService_ShouldBeScopedByTenant(t *testing.T) {
+	ctx := context.Background()
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(t.TempDir(), "test.db"),
+		CanCreateTable:   true,
+	})
+	r := core.NewRepositoryServiceBuilder(new(tenantModel), "T_TENANTMODEL").
+		CommandRepositoryAdapter(sqlite).
+		QueryRepositoryAdapter(sqlite).
+		Create()
+
+	ctxA := core.WithTenant(ctx, "a")
+	ctxB := core.WithTenant(ctx, "b")
+
+	dto := new(tenantModel)
+	dto.ID = uuid.New()
+	dto.Expect = 123
+
+	if result := r.Add(ctxA, dto); result.E != nil {
+		t.Fatalf("Test_sqliteRepositoryService_ShouldBeScopedByTenant() err = %v", result.E)
+	}
+
+	if result := r.Find(ctxB, dto.ID, new(tenantModel)); !errors.Is(result.E, core.ErrNotFound) {
+		t.Errorf("Test_sqliteRepositoryService_ShouldBeScopedByTenant() err = %v, expect %v", result.E, core.ErrNotFound)
+	}
+
+	r.Remove(ctxB, dto.ID)
+
+	if result := r.Count(ctxA); result.V != int64(1) {
+		t.Errorf("Test_sqliteRepositoryService_ShouldBeScopedByTenant() count = %v, expect %v", result.V, 1)
+	}
+
+	if result := r.Count(ctxB); result.V != int64(0) {
+		t.Errorf("Test_sqliteRepositoryService_ShouldBeScopedByTenant() count = %v, expect %v", result.V, 0)
+	}
+}
+
+func Test_sqliteRepositoryService_ShouldUseTenantDatabase(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(dir, "default.db"),
+		CanCreateTable:   true,
+		TenantConnectionStrings: map[string]string{
+			"a": filepath.Join(dir, "a.db"),
+		},
+	})
+	r := core.NewRepositoryServiceBuilder(new(testModel), "T_TESTMODEL").
+		CommandRepositoryAdapter(sqlite).
+		QueryRepositoryAdapter(sqlite).
+		Create()
+
+	dto := new(testModel)
+	dto.ID = uuid.New()
+
+	if result := r.Add(core.WithTenant(ctx, "a"), dto); result.E != nil {
+		t.Fatalf("Test_sqliteRepositoryService_ShouldUseTenantDatabase() err = %v", result.E)
+	}
+
+	if result := r.Count(ctx); result.V != int64(0) {
+		t.Errorf("Test_sqliteRepositoryService_ShouldUseTenantDatabase() default count = %v, expect %v", result.V, 0)
+	}
+
+	if result := r.Count(core.WithTenant(ctx, "a")); result.V != int64(1) {
+		t.Errorf("Test_sqliteRepositoryService_ShouldUseTenantDatabase() tenant count = %v, expect %v", result.V, 1)
+	}
+}
+
+func Test_sqliteRepositoryService_ReconnectShouldReleaseTenantPool(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(dir, "default.db"),
+		CanCreateTable:   true,
+		TenantConnectionStrings: map[string]string{
+			"a": filepath.Join(dir, "a.db"),
+		},
+	})
+	sqlite.SetModel(new(testModel), "T_TESTMODEL")
+
+	if err := sqlite.Connect(ctx); err != nil {
+		t.Fatalf("Test_sqliteRepositoryService_ReconnectShouldReleaseTenantPool() err = %v", err)
+	}
+	defer sqlite.Disconnect()
+
+	if _, err := sqlite.Count(core.WithTenant(ctx, "a")); err != nil {
+		t.Fatalf("Test_sqliteRepositoryService_ReconnectShouldReleaseTenantPool() err = %v", err)
+	}
+
+	if err := sqlite.Connect(ctx); err != nil {
+		t.Fatalf("Test_sqliteRepositoryService_ReconnectShouldReleaseTenantPool() err = %v", err)
+	}
+
+	// the only holder of the tenant database, its pool is closed by disconnect unless the reconnect leaked it
+	tenant := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(dir, "a.db"),
+		CanCreateTable:   true,
+	})
+	tenant.SetModel(new(testModel), "T_TESTMODEL")
+
+	if err := tenant.Connect(ctx); err != nil {
+		t.Fatalf("Test_sqliteRepositoryService_ReconnectShouldReleaseTenantPool() err = %v", err)
+	}
+
+	tenant.Disconnect()
+
+	if _, err := tenant.Count(ctx); err == nil {
+		t.Errorf("Test_sqliteRepositoryService_ReconnectShouldReleaseTenantPool() expect closed pool error")
+	}
+}
+
+func Test_sqliteRepositoryService_SoftDelete(t *testing.T) {
+	ctx := context.Background()
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(t.TempDir(), "test.db"),
+		CanCreateTable:   true,
+	})
+	r := core.NewRepositoryServiceBuilder(new(softDeleteModel), "T_SOFTDELETEMODEL").
+		CommandRepositoryAdapter(sqlite).
+		QueryRepositoryAdapter(sqlite).
+		Create()
+
+	dto := new(softDeleteModel)
+	dto.ID = uuid.New()
+	dto.Expect = 123
+
+	r.Add(ctx, dto)
+
+	if result := r.Remove(ctx, dto.ID); result.E != nil {
+		t.Fatalf("Test_sqliteRepositoryService_SoftDelete() err = %v", result.E)
+	}
+
+	if result := r.Count(ctx); result.V != int64(0) {
+		t.Errorf("Test_sqliteRepositoryService_SoftDelete() count = %v, expect %v", result.V, 0)
+	}
+
+	deleted := make([]*softDeleteModel, 0)
+
+	if r.ListDeleted(ctx, &deleted); len(deleted) != 1 {
+		t.Errorf("Test_sqliteRepositoryService_SoftDelete() deleted = %v, expect %v", len(deleted), 1)
+	}
+
+	if result := r.Restore(ctx, dto.ID); result.E != nil {
+		t.Errorf("Test_sqliteRepositoryService_SoftDelete() err = %v", result.E)
+	}
+
+	dto2 := new(softDeleteModel)
+
+	if result := r.Find(ctx, dto.ID, dto2); result.E != nil || dto2.Expect != 123 {
+		t.Errorf("Test_sqliteRepositoryService_SoftDelete() err = %v, result = %v", result.E, dto2)
+	}
+
+	r.Purge(ctx, dto.ID)
+
+	if result := r.Count(core.WithDeletedScope(ctx, core.DeletedIncluded)); result.V != int64(0) {
+		t.Errorf("Test_sqliteRepositoryService_SoftDelete() count = %v, expect %v", result.V, 0)
+	}
+}
+
+func Test_sqliteRepositoryService_UpdateShouldConflictWithStaleVersion(t *testing.T) {
+	ctx := context.Background()
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(t.TempDir(), "test.db"),
+		CanCreateTable:   true,
+	})
+	r := core.NewRepositoryServiceBuilder(new(versionModel), "T_VERSIONMODEL").
+		CommandRepositoryAdapter(sqlite).
+		QueryRepositoryAdapter(sqlite).
+		Create()
+
+	dto := new(versionModel)
+	dto.ID = uuid.New()
+	r.Add(ctx, dto)
+
+	first := new(versionModel)
+	second := new(versionModel)
+	r.Find(ctx, dto.ID, first)
+	r.Find(ctx, dto.ID, second)
+
+	first.Expect = 1
+
+	if result := r.Update(ctx, first); result.E != nil {
+		t.Fatalf("Test_sqliteRepositoryService_UpdateShouldConflictWithStaleVersion() err = %v", result.E)
+	}
+
+	second.Expect = 2
+
+	if result := r.Update(ctx, second); !errors.Is(result.E, core.ErrConflict) {
+		t.Errorf("Test_sqliteRepositoryService_UpdateShouldConflictWithStaleVersion() err = %v, expect %v", result.E, core.ErrConflict)
+	}
+
+	dto2 := new(versionModel)
+	r.Find(ctx, dto.ID, dto2)
+
+	if dto2.Version != 2 || dto2.Expect != 1 {
+		t.Errorf("Test_sqliteRepositoryService_UpdateShouldConflictWithStaleVersion() result = %v", dto2)
+	}
+}
+
+func Test_sqliteRepositoryService_ListWithSpecification(t *testing.T) {
+	ctx := core.WithPrincipal(context.Background(), &core.Principal{UserId: "user"})
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(t.TempDir(), "test.db"),
+		CanCreateTable:   true,
+	})
+	r := core.NewRepositoryServiceBuilder(new(testModel), "T_TESTMODEL").
+		CommandRepositoryAdapter(sqlite).
+		QueryRepositoryAdapter(sqlite).
+		Create()
+
+	for i := 0; i < 10; i++ {
+		dto := new(testModel)
+		dto.ID = uuid.New()
+		dto.Expect = i
+
+		r.Add(ctx, dto)
+	}
+
+	spec := core.And(
+		core.Or(core.Lt("Expect", 3), core.In("Expect", []int{7, 8})),
+		core.Not(core.Eq("Expect", 0)),
+		core.Like("CreateUser", "us%"))
+
+	if result := r.CountWithFilter(ctx, spec, nil); result.E != nil || result.V != int64(4) {
+		t.Errorf("Test_sqliteRepositoryService_ListWithSpecification() count = %v, err = %v, expect %v", result.V, result.E, 4)
+	}
+
+	dest := make([]*testModel, 0)
+	r.ListWithFilter(ctx, core.Gt("Expect", 5), nil, &dest)
+
+	if len(dest) != 4 {
+		t.Errorf("Test_sqliteRepositoryService_ListWithSpecification() list = %v, expect %v", len(dest), 4)
+	}
+
+	if result := r.CountWithFilter(ctx, core.Eq("Unknown", 1), nil); result.E == nil {
+		t.Errorf("Test_sqliteRepositoryService_ListWithSpecification() expect unknown field error")
+	}
+}
+
+func Test_sqliteRepositoryService_PageByCursor(t *testing.T) {
+	ctx := context.Background()
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(t.TempDir(), "test.db"),
+		CanCreateTable:   true,
+	})
+	r := core.NewRepositoryServiceBuilder(new(testModel), "T_TESTMODEL").
+		CommandRepositoryAdapter(sqlite).
+		QueryRepositoryAdapter(sqlite).
+		Create()
+
+	expect := 25
+	for i := 0; i < expect; i++ {
+		dto := new(testModel)
+		dto.ID = uuid.New()
+		dto.Expect = i % 5
+
+		r.Add(ctx, dto)
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	request := core.PageRequest{
+		OrderBy: []core.Order{{Field: "Expect", Descending: true}},
+		Limit:   4,
+		Fields:  []string{"Expect"},
+	}
+
+	for {
+		dest := make([]*testModel, 0)
+
+		result := r.Page(ctx, nil, request, &dest)
+		if result.E != nil {
+			t.Fatalf("Test_sqliteRepositoryService_PageByCursor() err = %v", result.E)
+		}
+
+		for _, v := range dest {
+			seen[v.ID] = true
+		}
+
+		page := result.V.(*core.Page)
+		if page.TotalCount != int64(expect) {
+			t.Errorf("Test_sqliteRepositoryService_PageByCursor() total = %v, expect %v", page.TotalCount, expect)
+		}
+
+		if !page.HasMore {
+			break
+		}
+
+		request.Cursor = page.NextCursor
+	}
+
+	if len(seen) != expect {
+		t.Errorf("Test_sqliteRepositoryService_PageByCursor() seen = %v, expect %v", len(seen), expect)
+	}
+}
+
+func Test_sqliteRepositoryService_Iterate(t *testing.T) {
+	ctx := context.Background()
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(t.TempDir(), "test.db"),
+		CanCreateTable:   true,
+	})
+	r := core.NewRepositoryServiceBuilder(new(testModel), "T_TESTMODEL").
+		CommandRepositoryAdapter(sqlite).
+		QueryRepositoryAdapter(sqlite).
+		Create()
+
+	for i := 0; i < 100; i++ {
+		dto := new(testModel)
+		dto.ID = uuid.New()
+		dto.Expect = i
+
+		r.Add(ctx, dto)
+	}
+
+	sum := 0
+
+	result := r.Iterate(ctx, core.Lt("Expect", 10), func(entity core.Entitier) error {
+		sum += entity.(*testModel).Expect
+
+		return nil
+	})
+
+	if result.E != nil || sum != 45 {
+		t.Errorf("Test_sqliteRepositoryService_Iterate() sum = %v, err = %v, expect %v", sum, result.E, 45)
+	}
+}
+
+func Test_sqliteRepositoryService_Bulk(t *testing.T) {
+	ctx := core.WithPrincipal(context.Background(), &core.Principal{UserId: "creator"})
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(t.TempDir(), "test.db"),
+		CanCreateTable:   true,
+		BatchSize:        3,
+	})
+	r := core.NewRepositoryServiceBuilder(new(versionModel), "T_VERSIONMODEL").
+		CommandRepositoryAdapter(sqlite).
+		QueryRepositoryAdapter(sqlite).
+		Create()
+
+	entities := make([]core.Entitier, 0, 10)
+
+	for i := 0; i < 10; i++ {
+		dto := new(versionModel)
+		dto.ID = uuid.New()
+		dto.Expect = i
+
+		entities = append(entities, dto)
+	}
+
+	if result := r.AddRange(ctx, entities); result.E != nil {
+		t.Fatalf("Test_sqliteRepositoryService_Bulk() err = %v", result.E)
+	}
+
+	upserted := new(versionModel)
+	upserted.ID = entities[0].GetID()
+	upserted.Expect = 100
+
+	updater := core.WithPrincipal(ctx, &core.Principal{UserId: "updater"})
+
+	if result := r.UpsertRange(updater, []core.Entitier{upserted, new(versionModel)}); result.E != nil || result.V.(int64) != 2 {
+		t.Errorf("Test_sqliteRepositoryService_Bulk() upserted = %v, err = %v, expect %v", result.V, result.E, 2)
+	}
+
+	dto := new(versionModel)
+	r.Find(ctx, upserted.ID, dto)
+
+	if dto.CreateUser != "creator" || dto.UpdateUser != "updater" || dto.Version != 2 || dto.Expect != 100 {
+		t.Errorf("Test_sqliteRepositoryService_Bulk() upserted = %v", dto)
+	}
+
+	if result := r.Patch(ctx, upserted.ID, map[string]interface{}{"Expect": 200}); result.E != nil || result.V.(int64) != 1 {
+		t.Errorf("Test_sqliteRepositoryService_Bulk() patched = %v, err = %v, expect %v", result.V, result.E, 1)
+	}
+
+	r.Find(ctx, upserted.ID, dto)
+
+	if dto.Version != 3 || dto.Expect != 200 {
+		t.Errorf("Test_sqliteRepositoryService_Bulk() patched = %v", dto)
+	}
+
+	if result := r.UpdateWhere(ctx, core.Lt("Expect", 5), map[string]interface{}{"Expect": 300}); result.E != nil || result.V.(int64) != 5 {
+		t.Errorf("Test_sqliteRepositoryService_Bulk() updated = %v, err = %v, expect %v", result.V, result.E, 5)
+	}
+
+	if result := r.RemoveWhere(ctx, core.Eq("Expect", 300)); result.E != nil || result.V.(int64) != 5 {
+		t.Errorf("Test_sqliteRepositoryService_Bulk() removed = %v, err = %v, expect %v", result.V, result.E, 5)
+	}
+
+	if count := r.Count(ctx); count.V.(int64) != 6 {
+		t.Errorf("Test_sqliteRepositoryService_Bulk() count = %v, expect %v", count.V, 6)
+	}
+}
+
+func Test_sqliteRepositoryService_Aggregate(t *testing.T) {
+	ctx := context.Background()
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(t.TempDir(), "test.db"),
+		CanCreateTable:   true,
+	})
+	r := core.NewRepositoryServiceBuilder(new(tenantModel), "T_TENANTMODEL").
+		CommandRepositoryAdapter(sqlite).
+		QueryRepositoryAdapter(sqlite).
+		Create()
+
+	for i := 1; i <= 6; i++ {
+		dto := new(tenantModel)
+		dto.ID = uuid.New()
+		dto.TenantId = "b"
+		dto.Expect = i
+
+		if i%2 == 1 {
+			dto.TenantId = "a"
+		}
+
+		r.Add(ctx, dto)
+	}
+
+	result := r.Aggregate(ctx, core.Gt("Expect", 1), core.Aggregation{
+		GroupBy:    []string{"TenantId"},
+		Aggregates: []core.Aggregate{core.CountOf("count"), core.SumOf("Expect", "sum"), core.AvgOf("Expect", "avg")},
+	})
+
+	rows, _ := result.V.([]core.AggregateRow)
+
+	if result.E != nil || len(rows) != 2 {
+		t.Fatalf("Test_sqliteRepositoryService_Aggregate() rows = %v, err = %v", rows, result.E)
+	}
+
+	if rows[0].Keys["TenantId"] != "a" || rows[0].Values["count"] != 2 || rows[0].Values["sum"] != 8 || rows[0].Values["avg"] != 4 {
+		t.Errorf("Test_sqliteRepositoryService_Aggregate() row = %v", rows[0])
+	}
+
+	if rows[1].Keys["TenantId"] != "b" || rows[1].Values["count"] != 3 || rows[1].Values["sum"] != 12 {
+		t.Errorf("Test_sqliteRepositoryService_Aggregate() row = %v", rows[1])
+	}
+
+	result = r.Aggregate(ctx, core.Gt("Expect", 100), core.Aggregation{
+		Aggregates: []core.Aggregate{core.CountOf("count"), core.MaxOf("Expect", "max")},
+	})
+
+	if rows, _ := result.V.([]core.AggregateRow); result.E != nil || len(rows) != 1 || rows[0].Values["count"] != 0 || rows[0].Values["max"] != 0 {
+		t.Errorf("Test_sqliteRepositoryService_Aggregate() rows = %v, err = %v", rows, result.E)
+	}
+}
+
+func Test_sqliteRepositoryService_ShouldTranslateErrors(t *testing.T) {
+	ctx := context.Background()
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(t.TempDir(), "test.db"),
+		CanCreateTable:   true,
+	})
+	r := core.NewRepositoryServiceBuilder(new(versionModel), "T_VERSIONMODEL").
+		CommandRepositoryAdapter(sqlite).
+		QueryRepositoryAdapter(sqlite).
+		Create()
+
+	dto := new(versionModel)
+	dto.ID = uuid.New()
+	r.Add(ctx, dto)
+
+	duplicated := new(versionModel)
+	duplicated.ID = dto.ID
+
+	result := r.Add(ctx, duplicated)
+
+	var coreErr *core.Error
+
+	if !errors.Is(result.E, core.ErrConflict) || !errors.As(result.E, &coreErr) || coreErr.Code != core.CodeUniqueViolation || coreErr.Cause == nil {
+		t.Errorf("Test_sqliteRepositoryService_ShouldTranslateErrors() err = %v, expect %v", result.E, core.ErrConflict)
+	}
+
+	if status := result.ToHttpStatus(); status != 409 {
+		t.Errorf("Test_sqliteRepositoryService_ShouldTranslateErrors() status = %v, expect %v", status, 409)
+	}
+
+	if problem := result.ToProblemDetails(""); problem.Detail != "unique constraint violated" || strings.Contains(problem.Detail, coreErr.Cause.Error()) {
+		t.Errorf("Test_sqliteRepositoryService_ShouldTranslateErrors() detail = %v, expect driver message is hidden", problem.Detail)
+	}
+
+	missing := new(versionModel)
+	missing.ID = uuid.New()
+	missing.Version = 1
+
+	if result := r.Update(ctx, missing); !errors.Is(result.E, core.ErrNotFound) {
+		t.Errorf("Test_sqliteRepositoryService_ShouldTranslateErrors() err = %v, expect %v", result.E, core.ErrNotFound)
+	}
+
+	if result := r.Remove(ctx, missing.ID); !errors.Is(result.E, core.ErrNotFound) {
+		t.Errorf("Test_sqliteRepositoryService_ShouldTranslateErrors() err = %v, expect %v", result.E, core.ErrNotFound)
+	}
+
+	if result := r.RemoveRange(ctx, []uuid.UUID{dto.ID, missing.ID}); !errors.Is(result.E, core.ErrNotFound) {
+		t.Errorf("Test_sqliteRepositoryService_ShouldTranslateErrors() err = %v, expect %v", result.E, core.ErrNotFound)
+	}
+
+	if result := r.Count(ctx); result.V != int64(1) {
+		t.Errorf("Test_sqliteRepositoryService_ShouldTranslateErrors() count = %v, expect %v", result.V, 1)
+	}
+}
+
+func Test_sqliteRepositoryService_ShouldReadFromReplica(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// the replica is not replicated, so the reads tell which database answered
+	replica := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(dir, "replica.db"),
+		CanCreateTable:   true,
+	})
+	replica.SetModel(new(testModel), "T_TESTMODEL")
+
+	if err := replica.Connect(ctx); err != nil {
+		t.Fatalf("Test_sqliteRepositoryService_ShouldReadFromReplica() err = %v", err)
+	}
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(dir, "primary.db"),
+		CanCreateTable:   true,
+		Replicas:         []string{filepath.Join(dir, "replica.db")},
+	})
+	r := core.NewRepositoryServiceBuilder(new(testModel), "T_TESTMODEL").
+		CommandRepositoryAdapter(sqlite).
+		QueryRepositoryAdapter(sqlite).
+		Create()
+
+	dto := new(testModel)
+	dto.ID = uuid.New()
+
+	if result := r.Add(ctx, dto); result.E != nil {
+		t.Fatalf("Test_sqliteRepositoryService_ShouldReadFromReplica() err = %v", result.E)
+	}
+
+	if result := r.Count(ctx); result.V != int64(0) {
+		t.Errorf("Test_sqliteRepositoryService_ShouldReadFromReplica() replica count = %v, expect %v", result.V, 0)
+	}
+
+	if result := r.Count(core.WithPrimary(ctx)); result.V != int64(1) {
+		t.Errorf("Test_sqliteRepositoryService_ShouldReadFromReplica() primary count = %v, expect %v", result.V, 1)
+	}
+
+	ctx = core.WithReadYourWrites(ctx)
+
+	dto = new(testModel)
+	dto.ID = uuid.New()
+
+	if result := r.Add(ctx, dto); result.E != nil {
+		t.Fatalf("Test_sqliteRepositoryService_ShouldReadFromReplica() err = %v", result.E)
+	}
+
+	if result := r.Count(ctx); result.V != int64(2) {
+		t.Errorf("Test_sqliteRepositoryService_ShouldReadFromReplica() read your writes count = %v, expect %v", result.V, 2)
+	}
+}
+
+func Test_sqliteRepositoryService_ShouldEjectUnhealthyReplica(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(dir, "primary.db"),
+		CanCreateTable:   true,
+		Replicas:         []string{filepath.Join(dir, "missing", "replica.db")},
+		ReplicaPolicy:    gorms.ReplicaLeastLatency,
+	})
+	r := core.NewRepositoryServiceBuilder(new(testModel), "T_TESTMODEL").
+		CommandRepositoryAdapter(sqlite).
+		QueryRepositoryAdapter(sqlite).
+		Create()
+
+	dto := new(testModel)
+	dto.ID = uuid.New()
+
+	if result := r.Add(ctx, dto); result.E != nil {
+		t.Fatalf("Test_sqliteRepositoryService_ShouldEjectUnhealthyReplica() err = %v", result.E)
+	}
+
+	if result := r.Count(ctx); result.V != int64(1) {
+		t.Errorf("Test_sqliteRepositoryService_ShouldEjectUnhealthyReplica() count = %v, expect %v", result.V, 1)
+	}
+}
+
+func Test_sqliteRepositoryService_DisconnectShouldCloseUnsharedPool(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(dir, "test.db"),
+		CanCreateTable:   true,
+	})
+	sqlite.SetModel(new(testModel), "T_TESTMODEL")
+
+	for i := 0; i < 3; i++ {
+		if err := sqlite.Connect(ctx); err != nil {
+			t.Fatalf("Test_sqliteRepositoryService_DisconnectShouldCloseUnsharedPool() err = %v", err)
+		}
+
+		if _, err := sqlite.Count(ctx); err != nil {
+			t.Errorf("Test_sqliteRepositoryService_DisconnectShouldCloseUnsharedPool() err = %v", err)
+		}
+
+		sqlite.Disconnect()
+
+		if sqlite.IsConnected() {
+			t.Errorf("Test_sqliteRepositoryService_DisconnectShouldCloseUnsharedPool() connected after disconnect")
+		}
+
+		// the pool is closed instead of leaking until the next connect opens another one
+		if _, err := sqlite.Count(ctx); err == nil {
+			t.Errorf("Test_sqliteRepositoryService_DisconnectShouldCloseUnsharedPool() expect closed pool error")
+		}
+	}
+}
+
+func Test_sqliteRepositoryService_DisconnectShouldKeepSharedReplica(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	// the replica database is also the primary of another adapter, so its pool is shared
+	replica := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(dir, "replica.db"),
+		CanCreateTable:   true,
+	})
+	replica.SetModel(new(testModel), "T_TESTMODEL")
+
+	if err := replica.Connect(ctx); err != nil {
+		t.Fatalf("Test_sqliteRepositoryService_DisconnectShouldKeepSharedReplica() err = %v", err)
+	}
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString:           filepath.Join(dir, "primary.db"),
+		CanCreateTable:             true,
+		Replicas:                   []string{filepath.Join(dir, "replica.db")},
+		ReplicaHealthCheckInterval: 5 * time.Millisecond,
+	})
+	sqlite.SetModel(new(testModel), "T_TESTMODEL")
+
+	for i := 0; i < 3; i++ {
+		if err := sqlite.Connect(ctx); err != nil {
+			t.Fatalf("Test_sqliteRepositoryService_DisconnectShouldKeepSharedReplica() err = %v", err)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+
+		sqlite.Disconnect()
+	}
+
+	if _, err := replica.Count(ctx); err != nil {
+		t.Errorf("Test_sqliteRepositoryService_DisconnectShouldKeepSharedReplica() err = %v", err)
+	}
+}
+
+func Test_sqliteRepositoryService_ShouldShareClientByConfiguration(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(dir, "test.db"),
+		CanCreateTable:   true,
+		Pool: gorms.GormPoolSettings{
+			MaxOpenConns:    1,
+			ConnMaxLifetime: time.Minute,
+		},
+	})
+	sqlite.SetModel(new(testModel), "T_TESTMODEL")
+
+	if err := sqlite.Connect(ctx); err != nil {
+		t.Fatalf("Test_sqliteRepositoryService_ShouldShareClientByConfiguration() err = %v", err)
+	}
+
+	defer sqlite.Disconnect()
+
+	// the same connection string with another configuration has its own client
+	secure := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(dir, "test.db"),
+		TLS:              core.TLSSettings{Enabled: true},
+	})
+
+	if err := secure.Connect(ctx); err == nil {
+		t.Errorf("Test_sqliteRepositoryService_ShouldShareClientByConfiguration() err = %v, expect error", err)
+	}
+}
+
+func Test_sqliteRepositoryService_UpsertShouldNotOverwriteOtherTenant(t *testing.T) {
+	ctx := context.Background()
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(t.TempDir(), "test.db"),
+		CanCreateTable:   true,
+	})
+	r := core.NewRepositoryServiceBuilder(new(tenantModel), "T_TENANTMODEL").
+		CommandRepositoryAdapter(sqlite).
+		QueryRepositoryAdapter(sqlite).
+		Create()
+
+	ctxA := core.WithTenant(ctx, "a")
+	ctxB := core.WithTenant(ctx, "b")
+
+	dto := new(tenantModel)
+	dto.ID = uuid.New()
+	dto.Expect = 123
+
+	r.Add(ctxA, dto)
+
+	forged := new(tenantModel)
+	forged.ID = dto.ID
+	forged.Expect = 456
+
+	if result := r.Upsert(ctxB, forged); !errors.Is(result.E, core.ErrConflict) {
+		t.Errorf("Test_sqliteRepositoryService_UpsertShouldNotOverwriteOtherTenant() err = %v, expect %v", result.E, core.ErrConflict)
+	}
+
+	dest := new(tenantModel)
+	r.Find(ctxA, dto.ID, dest)
+
+	if dest.Expect != 123 {
+		t.Errorf("Test_sqliteRepositoryService_UpsertShouldNotOverwriteOtherTenant() expect = %v, expect %v", dest.Expect, 123)
+	}
+}
+
+func Test_sqliteRepositoryService_ShouldConnectTenantDatabaseOnce(t *testing.T) {
+	ctx := core.WithTenant(context.Background(), "a")
+	dir := t.TempDir()
+
+	sqlite := gorms.NewSqliteAdapter(gorms.GormSettings{
+		ConnectionString: filepath.Join(dir, "default.db"),
+		CanCreateTable:   true,
+		TenantConnectionStrings: map[string]string{
+			"a": filepath.Join(dir, "a.db"),
+		},
+	})
+	r := core.NewRepositoryServiceBuilder(new(testModel), "T_TESTMODEL").
+		CommandRepositoryAdapter(sqlite).
+		QueryRepositoryAdapter(sqlite).
+		Create()
+
+	errs := make(chan error, 10)
+
+	for i := 0; i < 10; i++ {
+		go func() {
+			errs <- r.Count(ctx).E
+		}()
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("Test_sqliteRepositoryService_ShouldConnectTenantDatabaseOnce() err = %v", err)
+		}
+	}
+}
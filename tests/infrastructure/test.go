@@ -8,3 +8,32 @@ type testModel struct {
 	core.Entity
 	Expect int `bson:"expect,omitempty"`
 }
+
+type softDeleteModel struct {
+	core.Entity
+	core.SoftDeleteEntity
+	Expect int `bson:"expect,omitempty"`
+}
+
+type versionModel struct {
+	core.Entity
+	core.VersionEntity
+	Expect int `bson:"expect,omitempty"`
+}
+
+type tenantModel struct {
+	core.Entity
+	core.TenantEntity
+	Expect int `bson:"expect,omitempty"`
+}
+
+type migrationModel struct {
+	core.Entity
+	Expect int    `bson:"expect,omitempty"`
+	Note   string `gorm:"index"`
+}
+
+type indexModel struct {
+	core.Entity
+	Code string `bson:"code" index:",unique"`
+}
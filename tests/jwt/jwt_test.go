@@ -0,0 +1,126 @@
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/infrastructure/jwt"
+)
+
+var secret = []byte("secret")
+
+func sign(claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func Test_verifier_ShouldReturnPrincipal(t *testing.T) {
+	v := jwt.NewVerifier(jwt.JwtSettings{Secret: secret, Issuer: "test"})
+
+	token := sign(map[string]interface{}{
+		"sub":   "qwe",
+		"iss":   "test",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"admin", "user"},
+	})
+
+	principal, err := v.Verify(token)
+	if err != nil {
+		t.Fatalf("Test_verifier_ShouldReturnPrincipal() err = %v", err)
+	}
+
+	if principal.UserId != "qwe" || !principal.IsInRole("admin") {
+		t.Errorf("Test_verifier_ShouldReturnPrincipal() principal = %v", principal)
+	}
+}
+
+func Test_verifier_ShouldRejectInvalidToken(t *testing.T) {
+	v := jwt.NewVerifier(jwt.JwtSettings{Secret: secret, Issuer: "test"})
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"malformed", "qwe"},
+		{"expired", sign(map[string]interface{}{"sub": "qwe", "iss": "test", "exp": time.Now().Add(-time.Hour).Unix()})},
+		{"issuer", sign(map[string]interface{}{"sub": "qwe", "iss": "other", "exp": time.Now().Add(time.Hour).Unix()})},
+		{"signature", sign(map[string]interface{}{"sub": "qwe", "iss": "test", "exp": time.Now().Add(time.Hour).Unix()}) + "x"},
+		{"expiration", sign(map[string]interface{}{"sub": "qwe", "iss": "test"})},
+	}
+
+	for _, tt := range tests {
+		if _, err := v.Verify(tt.token); !errors.Is(err, core.ErrUnauthorized) {
+			t.Errorf("Test_verifier_ShouldRejectInvalidToken() %v err = %v, expect %v", tt.name, err, core.ErrUnauthorized)
+		}
+	}
+}
+
+func Test_verifier_HttpMiddlewareShouldPopulatePrincipal(t *testing.T) {
+	v := jwt.NewVerifier(jwt.JwtSettings{Secret: secret})
+
+	var userId string
+
+	handler := v.HttpMiddleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if principal, ok := core.PrincipalFromContext(req.Context()); ok {
+			userId = principal.UserId
+		}
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+sign(map[string]interface{}{"sub": "qwe", "exp": time.Now().Add(time.Hour).Unix()}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if userId != "qwe" {
+		t.Errorf("Test_verifier_HttpMiddlewareShouldPopulatePrincipal() userId = %v, expect %v", userId, "qwe")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer qwe")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Test_verifier_HttpMiddlewareShouldPopulatePrincipal() status = %v, expect %v", w.Code, http.StatusUnauthorized)
+	}
+
+	if w.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("Test_verifier_HttpMiddlewareShouldPopulatePrincipal() expect WWW-Authenticate header")
+	}
+}
+
+func Test_verifier_AllowMissingExpirationShouldBeWorking(t *testing.T) {
+	v := jwt.NewVerifier(jwt.JwtSettings{Secret: secret, AllowMissingExpiration: true})
+
+	if _, err := v.Verify(sign(map[string]interface{}{"sub": "qwe"})); err != nil {
+		t.Errorf("Test_verifier_AllowMissingExpirationShouldBeWorking() err = %v", err)
+	}
+}
+
+func Test_verifier_NoLeewayShouldBeWorking(t *testing.T) {
+	token := sign(map[string]interface{}{"sub": "qwe", "exp": time.Now().Add(-time.Second).Unix()})
+
+	if _, err := jwt.NewVerifier(jwt.JwtSettings{Secret: secret}).Verify(token); err != nil {
+		t.Errorf("Test_verifier_NoLeewayShouldBeWorking() err = %v, expect %v", err, nil)
+	}
+
+	if _, err := jwt.NewVerifier(jwt.JwtSettings{Secret: secret, NoLeeway: true}).Verify(token); !errors.Is(err, core.ErrUnauthorized) {
+		t.Errorf("Test_verifier_NoLeewayShouldBeWorking() err = %v, expect %v", err, core.ErrUnauthorized)
+	}
+}
@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/middlewares"
+)
+
+func Test_authorizationMiddleware_ShouldCheckRoles(t *testing.T) {
+	m := core.NewMediatorBuilder().
+		AddHandler(new(adminCommand), adminCommandHandler).
+		AddHandler(new(okCommand), okCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewAuthorizationMiddleware())
+
+	ctx := context.Background()
+
+	if result := m.Send(ctx, &okCommand{Expect: 1}); result.E != nil {
+		t.Errorf("Test_authorizationMiddleware_ShouldCheckRoles() anonymous err = %v", result.E)
+	}
+
+	if result := m.Send(ctx, &adminCommand{Expect: 1}); !errors.Is(result.E, core.ErrUnauthorized) {
+		t.Errorf("Test_authorizationMiddleware_ShouldCheckRoles() err = %v, expect %v", result.E, core.ErrUnauthorized)
+	}
+
+	userCtx := core.WithPrincipal(ctx, &core.Principal{UserId: "qwe", Roles: []string{"user"}})
+
+	if result := m.Send(userCtx, &adminCommand{Expect: 1}); !errors.Is(result.E, core.ErrForbiddenAcccess) {
+		t.Errorf("Test_authorizationMiddleware_ShouldCheckRoles() err = %v, expect %v", result.E, core.ErrForbiddenAcccess)
+	}
+
+	adminCtx := core.WithPrincipal(ctx, &core.Principal{UserId: "qwe", Roles: []string{"admin"}})
+
+	if result := m.Send(adminCtx, &adminCommand{Expect: 1}); result.E != nil || result.V != 1 {
+		t.Errorf("Test_authorizationMiddleware_ShouldCheckRoles() result = %v", result)
+	}
+}
+
+func Test_authorizationMiddleware_ShouldCheckPolicies(t *testing.T) {
+	m := core.NewMediatorBuilder().
+		AddHandler(new(okCommand), okCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewAuthorizationMiddleware().
+		AddPolicy("positive", func(ctx context.Context, principal *core.Principal, request core.Request) bool {
+			return request.(*okCommand).Expect > 0
+		}).
+		RequirePolicies(new(okCommand), "positive"))
+
+	ctx := core.WithPrincipal(context.Background(), &core.Principal{UserId: "qwe"})
+
+	if result := m.Send(ctx, &okCommand{Expect: 1}); result.E != nil {
+		t.Errorf("Test_authorizationMiddleware_ShouldCheckPolicies() err = %v", result.E)
+	}
+
+	if result := m.Send(ctx, &okCommand{Expect: -1}); !errors.Is(result.E, core.ErrForbiddenAcccess) {
+		t.Errorf("Test_authorizationMiddleware_ShouldCheckPolicies() err = %v, expect %v", result.E, core.ErrForbiddenAcccess)
+	}
+}
@@ -0,0 +1,110 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/middlewares"
+)
+
+func Test_bulkheadMiddleware_ShouldLimitConcurrentRequests(t *testing.T) {
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(slowCommand), slowCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewBulkheadMiddleware(middlewares.BulkheadSettings{
+		MaxConcurrentRequests: 2,
+		MaxQueueLength:        1,
+	}))
+
+	count := 10
+	results := make(chan core.Result, count)
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results <- m.Send(ctx, &slowCommand{Expect: i})
+		}(i)
+	}
+
+	wg.Wait()
+	close(results)
+
+	succeeded := 0
+	for result := range results {
+		if result.E == nil {
+			succeeded++
+			continue
+		}
+
+		if !errors.Is(result.E, middlewares.ErrBulkheadFull) {
+			t.Errorf("Test_bulkheadMiddleware_ShouldLimitConcurrentRequests() err = %v, expect %v", result.E, middlewares.ErrBulkheadFull)
+		}
+
+		if result.ToHttpStatus() != http.StatusTooManyRequests {
+			t.Errorf("Test_bulkheadMiddleware_ShouldLimitConcurrentRequests() status = %v, expect %v", result.ToHttpStatus(), http.StatusTooManyRequests)
+		}
+	}
+
+	if succeeded != 3 {
+		t.Errorf("Test_bulkheadMiddleware_ShouldLimitConcurrentRequests() succeeded = %v, expect %v", succeeded, 3)
+	}
+}
+
+func Test_bulkheadMiddleware_QueueTimeoutShouldBeRejected(t *testing.T) {
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(slowCommand), slowCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewBulkheadMiddleware(middlewares.BulkheadSettings{
+		MaxConcurrentRequests: 1,
+		MaxQueueLength:        1,
+		QueueTimeout:          time.Duration(10 * time.Millisecond),
+	}))
+
+	go m.Send(ctx, &slowCommand{})
+
+	time.Sleep(10 * time.Millisecond)
+
+	result := m.Send(ctx, &slowCommand{})
+
+	if !errors.Is(result.E, middlewares.ErrBulkheadFull) {
+		t.Errorf("Test_bulkheadMiddleware_QueueTimeoutShouldBeRejected() err = %v, expect %v", result.E, middlewares.ErrBulkheadFull)
+	}
+}
+
+func Test_bulkheadMiddleware_ShouldBeIsolatedByRequestType(t *testing.T) {
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(okCommand), okCommandHandler).
+		AddHandler(new(slowCommand), slowCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewBulkheadMiddleware(middlewares.BulkheadSettings{
+		MaxConcurrentRequests: 1,
+	}))
+
+	go m.Send(ctx, &slowCommand{})
+
+	time.Sleep(10 * time.Millisecond)
+
+	expect := 123
+	result := m.Send(ctx, &okCommand{Expect: expect})
+
+	if result.E != nil {
+		t.Errorf("Test_bulkheadMiddleware_ShouldBeIsolatedByRequestType() err = %v", result.E)
+	}
+
+	if result.V != expect {
+		t.Errorf("Test_bulkheadMiddleware_ShouldBeIsolatedByRequestType() result = %v, expect %v", result.V, expect)
+	}
+}
@@ -0,0 +1,293 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/middlewares"
+)
+
+func Test_idempotencyMiddleware_DuplicateShouldReturnStoredResult(t *testing.T) {
+	useStateService()
+
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(countCommand), countCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewIdempotencyMiddleware())
+
+	key := uuid.NewString()
+
+	expect := m.Send(ctx, &countCommand{Key: key, Expect: 123})
+	result := m.Send(ctx, &countCommand{Key: key, Expect: 123})
+
+	if result.E != nil {
+		t.Errorf("Test_idempotencyMiddleware_DuplicateShouldReturnStoredResult() err = %v", result.E)
+	}
+
+	if !reflect.DeepEqual(result.V, expect.V) {
+		t.Errorf("Test_idempotencyMiddleware_DuplicateShouldReturnStoredResult() result = %v, expect %v", result.V, expect.V)
+	}
+
+	other := m.Send(ctx, &countCommand{Key: uuid.NewString(), Expect: 123})
+
+	if reflect.DeepEqual(other.V, expect.V) {
+		t.Errorf("Test_idempotencyMiddleware_DuplicateShouldReturnStoredResult() result = %v, expect new result", other.V)
+	}
+}
+
+func Test_idempotencyMiddleware_KeyInContextShouldBeWorking(t *testing.T) {
+	useStateService()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(countCommand), countCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewIdempotencyMiddlewareWithSettings(middlewares.IdempotencySettings{
+		KeyInContext: "X-Request-Id",
+	}))
+
+	ctx := context.WithValue(context.Background(), "X-Request-Id", uuid.NewString())
+
+	expect := m.Send(ctx, &countCommand{Expect: 123})
+	result := m.Send(ctx, &countCommand{Expect: 123})
+
+	if !reflect.DeepEqual(result.V, expect.V) {
+		t.Errorf("Test_idempotencyMiddleware_KeyInContextShouldBeWorking() result = %v, expect %v", result.V, expect.V)
+	}
+}
+
+func Test_idempotencyMiddleware_ConcurrentDuplicateShouldBeConflict(t *testing.T) {
+	useStateService()
+
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(countCommand), countCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewIdempotencyMiddleware())
+
+	key := uuid.NewString()
+
+	go m.Send(ctx, &countCommand{Key: key, Expect: 123})
+
+	time.Sleep(10 * time.Millisecond)
+
+	result := m.Send(ctx, &countCommand{Key: key, Expect: 123})
+
+	if !errors.Is(result.E, core.ErrConflict) {
+		t.Errorf("Test_idempotencyMiddleware_ConcurrentDuplicateShouldBeConflict() err = %v, expect %v", result.E, core.ErrConflict)
+	}
+}
+
+func Test_idempotencyMiddleware_ConcurrentDuplicateShouldWait(t *testing.T) {
+	useStateService()
+
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(countCommand), countCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewIdempotencyMiddlewareWithSettings(middlewares.IdempotencySettings{
+		WaitInFlight: true,
+	}))
+
+	key := uuid.NewString()
+	ch := make(chan core.Result, 1)
+
+	go func() {
+		ch <- m.Send(ctx, &countCommand{Key: key, Expect: 123})
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	result := m.Send(ctx, &countCommand{Key: key, Expect: 123})
+	expect := <-ch
+
+	if result.E != nil {
+		t.Errorf("Test_idempotencyMiddleware_ConcurrentDuplicateShouldWait() err = %v", result.E)
+	}
+
+	if !reflect.DeepEqual(result.V, expect.V) {
+		t.Errorf("Test_idempotencyMiddleware_ConcurrentDuplicateShouldWait() result = %v, expect %v", result.V, expect.V)
+	}
+}
+
+func Test_idempotencyMiddleware_FailureShouldNotBeStored(t *testing.T) {
+	useStateService()
+
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(countCommand), countCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewIdempotencyMiddleware())
+
+	key := uuid.NewString()
+
+	m.Send(ctx, &countCommand{Key: key, Expect: -1})
+	result := m.Send(ctx, &countCommand{Key: key, Expect: 123})
+
+	if result.E != nil {
+		t.Errorf("Test_idempotencyMiddleware_FailureShouldNotBeStored() err = %v", result.E)
+	}
+
+	if result.V.(*countResult).Expect != 123 {
+		t.Errorf("Test_idempotencyMiddleware_FailureShouldNotBeStored() result = %v, expect %v", result.V, 123)
+	}
+}
+
+func Test_idempotencyMiddleware_RestartedProcessShouldDecodeRegisteredResult(t *testing.T) {
+	useStateService()
+
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(countCommand), countCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewIdempotencyMiddleware())
+
+	key := uuid.NewString()
+
+	expect := m.Send(ctx, &countCommand{Key: key, Expect: 123})
+
+	restarted := core.NewMediatorBuilder().
+		AddHandler(new(countCommand), countCommandHandler).
+		Create()
+	restarted.AddMiddleware(middlewares.NewIdempotencyMiddleware().
+		RegisterResult(new(countCommand), new(countResult)))
+
+	result := restarted.Send(ctx, &countCommand{Key: key, Expect: 123})
+
+	if result.E != nil {
+		t.Errorf("Test_idempotencyMiddleware_RestartedProcessShouldDecodeRegisteredResult() err = %v", result.E)
+	}
+
+	if !reflect.DeepEqual(result.V, expect.V) {
+		t.Errorf("Test_idempotencyMiddleware_RestartedProcessShouldDecodeRegisteredResult() result = %v, expect %v", result.V, expect.V)
+	}
+}
+
+func Test_idempotencyMiddleware_NotRegisteredResultShouldFailClosed(t *testing.T) {
+	useStateService()
+
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(countCommand), countCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewIdempotencyMiddleware())
+
+	key := uuid.NewString()
+
+	m.Send(ctx, &countCommand{Key: key, Expect: 123})
+
+	restarted := core.NewMediatorBuilder().
+		AddHandler(new(countCommand), countCommandHandler).
+		Create()
+	restarted.AddMiddleware(middlewares.NewIdempotencyMiddleware())
+
+	result := restarted.Send(ctx, &countCommand{Key: key, Expect: 123})
+
+	if !errors.Is(result.E, core.ErrInternalServerError) {
+		t.Errorf("Test_idempotencyMiddleware_NotRegisteredResultShouldFailClosed() err = %v, expect %v", result.E, core.ErrInternalServerError)
+	}
+}
+
+func Test_idempotencyMiddleware_PendingLeaseShouldExpire(t *testing.T) {
+	useStateService()
+
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(countCommand), countCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewIdempotencyMiddlewareWithSettings(middlewares.IdempotencySettings{
+		PendingLease: time.Duration(20 * time.Millisecond),
+	}))
+
+	key := uuid.NewString()
+
+	// the pending record of a crashed process
+	core.GetStateService().SetWithTTL(ctx, "idempotency:countCommand::"+key, &struct {
+		Status    string
+		ExpiresAt time.Time
+	}{"pending", time.Now().Add(20 * time.Millisecond)}, time.Duration(20*time.Millisecond))
+
+	if result := m.Send(ctx, &countCommand{Key: key, Expect: 123}); !errors.Is(result.E, core.ErrConflict) {
+		t.Errorf("Test_idempotencyMiddleware_PendingLeaseShouldExpire() err = %v, expect %v", result.E, core.ErrConflict)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if result := m.Send(ctx, &countCommand{Key: key, Expect: 123}); result.E != nil {
+		t.Errorf("Test_idempotencyMiddleware_PendingLeaseShouldExpire() err = %v", result.E)
+	}
+}
+
+func Test_idempotencyMiddleware_TenantShouldBeWorking(t *testing.T) {
+	useStateService()
+
+	ctx := core.WithTenant(context.Background(), "tenant-a")
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(countCommand), countCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewIdempotencyMiddleware())
+
+	key := uuid.NewString()
+
+	if result := m.Send(ctx, &countCommand{Key: key, Expect: -1}); !errors.Is(result.E, core.ErrBadRequest) {
+		t.Errorf("Test_idempotencyMiddleware_TenantShouldBeWorking() err = %v, expect %v", result.E, core.ErrBadRequest)
+	}
+
+	// the failure is deleted from the tenant key, so the retry is not blocked by the lease
+	expect := m.Send(ctx, &countCommand{Key: key, Expect: 123})
+
+	if expect.E != nil {
+		t.Errorf("Test_idempotencyMiddleware_TenantShouldBeWorking() err = %v", expect.E)
+	}
+
+	result := m.Send(ctx, &countCommand{Key: key, Expect: 123})
+
+	if result.E != nil || !reflect.DeepEqual(result.V, expect.V) {
+		t.Errorf("Test_idempotencyMiddleware_TenantShouldBeWorking() result = %v, err = %v, expect %v", result.V, result.E, expect.V)
+	}
+}
+
+func Test_idempotencyMiddleware_ShouldBeScopedByTenantAndPrincipal(t *testing.T) {
+	useStateService()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(countCommand), countCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewIdempotencyMiddleware())
+
+	key := uuid.NewString()
+
+	user1 := core.WithPrincipal(context.Background(), &core.Principal{UserId: "user1"})
+	user2 := core.WithPrincipal(context.Background(), &core.Principal{UserId: "user2"})
+
+	expect := m.Send(user1, &countCommand{Key: key, Expect: 123})
+	result := m.Send(user2, &countCommand{Key: key, Expect: 123})
+
+	if result.E != nil || reflect.DeepEqual(result.V, expect.V) {
+		t.Errorf("Test_idempotencyMiddleware_ShouldBeScopedByTenantAndPrincipal() result = %v, err = %v, expect new result", result.V, result.E)
+	}
+
+	tenantA := core.WithTenant(context.Background(), "tenant-a")
+	tenantB := core.WithTenant(context.Background(), "tenant-b")
+
+	go m.Send(tenantA, &countCommand{Key: key, Expect: 123})
+
+	time.Sleep(10 * time.Millisecond)
+
+	if result := m.Send(tenantB, &countCommand{Key: key, Expect: 123}); result.E != nil {
+		t.Errorf("Test_idempotencyMiddleware_ShouldBeScopedByTenantAndPrincipal() err = %v", result.E)
+	}
+}
@@ -0,0 +1,54 @@
+package middlewares
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/middlewares"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type slowWriter struct{}
+
+func (w slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(10 * time.Millisecond)
+
+	return len(p), nil
+}
+
+func Test_middleware_ConcurrentRequestsShouldNotBeMixed(t *testing.T) {
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(okCommand), okCommandHandler).
+		Create()
+	// the slow log middleware overlaps the concurrent requests before calling the next one
+	slow := zap.New(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		zapcore.AddSync(slowWriter{}),
+		zap.InfoLevel))
+
+	m.AddMiddleware(middlewares.NewPanicRecoverMiddleware(nil)).
+		AddNext(middlewares.NewLogMiddleware(slow)).
+		AddNext(middlewares.NewPerformanceMiddleware(slow, time.Duration(1*time.Second)))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+
+		go func(expect int) {
+			defer wg.Done()
+
+			if result := m.Send(ctx, &okCommand{Expect: expect}); result.V != expect {
+				t.Errorf("Test_middleware_ConcurrentRequestsShouldNotBeMixed() result = %v, expect %v", result.V, expect)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
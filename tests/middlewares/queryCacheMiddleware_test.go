@@ -0,0 +1,160 @@
+package middlewares
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/infrastructure/mocks"
+	"github.com/jybbang/go-core-architecture/middlewares"
+)
+
+func Test_queryCacheMiddleware_ShouldReturnCachedResult(t *testing.T) {
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(countQuery), countQueryHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewQueryCacheMiddleware().
+		AddQuery(new(countQuery), 0))
+
+	expect := m.Send(ctx, &countQuery{Expect: 1})
+	result := m.Send(ctx, &countQuery{Expect: 1})
+
+	if !reflect.DeepEqual(result.V, expect.V) {
+		t.Errorf("Test_queryCacheMiddleware_ShouldReturnCachedResult() result = %v, expect %v", result.V, expect.V)
+	}
+
+	other := m.Send(ctx, &countQuery{Expect: 2})
+
+	if reflect.DeepEqual(other.V, expect.V) {
+		t.Errorf("Test_queryCacheMiddleware_ShouldReturnCachedResult() result = %v, expect new result", other.V)
+	}
+}
+
+func Test_queryCacheMiddleware_NotRegisteredQueryShouldNotBeCached(t *testing.T) {
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(countQuery), countQueryHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewQueryCacheMiddleware())
+
+	expect := m.Send(ctx, &countQuery{Expect: 1})
+	result := m.Send(ctx, &countQuery{Expect: 1})
+
+	if reflect.DeepEqual(result.V, expect.V) {
+		t.Errorf("Test_queryCacheMiddleware_NotRegisteredQueryShouldNotBeCached() result = %v, expect new result", result.V)
+	}
+}
+
+func Test_queryCacheMiddleware_ShouldBeExpired(t *testing.T) {
+	ctx := context.Background()
+	expiration := time.Duration(50 * time.Millisecond)
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(countQuery), countQueryHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewQueryCacheMiddlewareWithSettings(middlewares.QueryCacheSettings{
+		UseStateService: true,
+	}).AddQuery(new(countQuery), expiration))
+
+	useStateService()
+
+	expect := m.Send(ctx, &countQuery{Expect: 3})
+	result := m.Send(ctx, &countQuery{Expect: 3})
+
+	if !reflect.DeepEqual(result.V, expect.V) {
+		t.Errorf("Test_queryCacheMiddleware_ShouldBeExpired() result = %v, expect %v", result.V, expect.V)
+	}
+
+	time.Sleep(expiration * 2)
+
+	result = m.Send(ctx, &countQuery{Expect: 3})
+
+	if reflect.DeepEqual(result.V, expect.V) {
+		t.Errorf("Test_queryCacheMiddleware_ShouldBeExpired() result = %v, expect new result", result.V)
+	}
+}
+
+func Test_queryCacheMiddleware_DomainEventShouldInvalidateTags(t *testing.T) {
+	ctx := context.Background()
+
+	cache := middlewares.NewQueryCacheMiddleware().
+		AddQuery(new(countQuery), 0, "count").
+		InvalidateOn(new(countUpdated), "count")
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(countQuery), countQueryHandler).
+		AddNotificationHandler(new(countUpdated), countUpdatedHandler).
+		Create()
+	m.AddMiddleware(cache)
+
+	e := core.NewEventBusBuilder().
+		MessaingAdapter(mocks.NewMockAdapter()).
+		CustomMediator(m).
+		Create()
+	e.AddDomainEventListener(cache.OnDomainEventPublished)
+
+	expect := m.Send(ctx, &countQuery{Expect: 4})
+
+	event := new(countUpdated)
+	event.Topic = "count"
+	e.AddDomainEvent(event)
+	e.PublishDomainEvents(ctx)
+
+	result := m.Send(ctx, &countQuery{Expect: 4})
+
+	if reflect.DeepEqual(result.V, expect.V) {
+		t.Errorf("Test_queryCacheMiddleware_DomainEventShouldInvalidateTags() result = %v, expect new result", result.V)
+	}
+
+	expect = result
+	result = m.Send(ctx, &countQuery{Expect: 4})
+
+	if !reflect.DeepEqual(result.V, expect.V) {
+		t.Errorf("Test_queryCacheMiddleware_DomainEventShouldInvalidateTags() result = %v, expect %v", result.V, expect.V)
+	}
+}
+
+func Test_queryCacheMiddleware_ShouldNotShareResultAcrossTenants(t *testing.T) {
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(countQuery), countQueryHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewQueryCacheMiddleware().
+		AddQuery(new(countQuery), 0))
+
+	expect := m.Send(core.WithTenant(ctx, "a"), &countQuery{Expect: 4})
+	result := m.Send(core.WithTenant(ctx, "b"), &countQuery{Expect: 4})
+
+	if reflect.DeepEqual(result.V, expect.V) {
+		t.Errorf("Test_queryCacheMiddleware_ShouldNotShareResultAcrossTenants() result = %v, expect new result", result.V)
+	}
+
+	result = m.Send(core.WithTenant(ctx, "a"), &countQuery{Expect: 4})
+
+	if !reflect.DeepEqual(result.V, expect.V) {
+		t.Errorf("Test_queryCacheMiddleware_ShouldNotShareResultAcrossTenants() result = %v, expect %v", result.V, expect.V)
+	}
+}
+
+func Test_queryCacheMiddleware_PrincipalQueryShouldNotShareResultAcrossPrincipals(t *testing.T) {
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(countQuery), countQueryHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewQueryCacheMiddleware().
+		AddPrincipalQuery(new(countQuery), 0))
+
+	expect := m.Send(core.WithPrincipal(ctx, &core.Principal{UserId: "a"}), &countQuery{Expect: 5})
+	result := m.Send(core.WithPrincipal(ctx, &core.Principal{UserId: "b"}), &countQuery{Expect: 5})
+
+	if reflect.DeepEqual(result.V, expect.V) {
+		t.Errorf("Test_queryCacheMiddleware_PrincipalQueryShouldNotShareResultAcrossPrincipals() result = %v, expect new result", result.V)
+	}
+}
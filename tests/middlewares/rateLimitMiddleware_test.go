@@ -0,0 +1,198 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/middlewares"
+)
+
+type tenantKey struct{}
+
+func Test_rateLimitMiddleware_ShouldLimitBurst(t *testing.T) {
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(okCommand), okCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewRateLimitMiddleware(middlewares.RateLimitSettings{
+		Rate:  10,
+		Burst: 3,
+	}))
+
+	for i := 0; i < 3; i++ {
+		if result := m.Send(ctx, &okCommand{}); result.E != nil {
+			t.Errorf("Test_rateLimitMiddleware_ShouldLimitBurst() err = %v", result.E)
+		}
+	}
+
+	result := m.Send(ctx, &okCommand{})
+
+	if !errors.Is(result.E, middlewares.ErrRateLimited) {
+		t.Errorf("Test_rateLimitMiddleware_ShouldLimitBurst() err = %v, expect %v", result.E, middlewares.ErrRateLimited)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+
+	if result := m.Send(ctx, &okCommand{}); result.E != nil {
+		t.Errorf("Test_rateLimitMiddleware_ShouldLimitBurst() err = %v", result.E)
+	}
+}
+
+func Test_rateLimitMiddleware_KeySelectorShouldBeWorking(t *testing.T) {
+	m := core.NewMediatorBuilder().
+		AddHandler(new(okCommand), okCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewRateLimitMiddleware(middlewares.RateLimitSettings{
+		Rate:  1,
+		Burst: 1,
+		KeySelector: func(ctx context.Context, request core.Request) string {
+			tenant, _ := ctx.Value(tenantKey{}).(string)
+			return tenant
+		},
+	}))
+
+	ctxA := context.WithValue(context.Background(), tenantKey{}, "a")
+	ctxB := context.WithValue(context.Background(), tenantKey{}, "b")
+
+	if result := m.Send(ctxA, &okCommand{}); result.E != nil {
+		t.Errorf("Test_rateLimitMiddleware_KeySelectorShouldBeWorking() err = %v", result.E)
+	}
+
+	if result := m.Send(ctxB, &okCommand{}); result.E != nil {
+		t.Errorf("Test_rateLimitMiddleware_KeySelectorShouldBeWorking() err = %v", result.E)
+	}
+
+	if result := m.Send(ctxA, &okCommand{}); !errors.Is(result.E, middlewares.ErrRateLimited) {
+		t.Errorf("Test_rateLimitMiddleware_KeySelectorShouldBeWorking() err = %v, expect %v", result.E, middlewares.ErrRateLimited)
+	}
+}
+
+func Test_rateLimitMiddleware_UseStateServiceShouldBeShared(t *testing.T) {
+	useStateService()
+
+	ctx := context.Background()
+	settings := middlewares.RateLimitSettings{
+		Rate:            0.01,
+		Burst:           2,
+		UseStateService: true,
+	}
+
+	m1 := core.NewMediatorBuilder().
+		AddHandler(new(slowCommand), slowCommandHandler).
+		Create()
+	m1.AddMiddleware(middlewares.NewRateLimitMiddleware(settings))
+
+	m2 := core.NewMediatorBuilder().
+		AddHandler(new(slowCommand), slowCommandHandler).
+		Create()
+	m2.AddMiddleware(middlewares.NewRateLimitMiddleware(settings))
+
+	if result := m1.Send(ctx, &slowCommand{}); result.E != nil {
+		t.Errorf("Test_rateLimitMiddleware_UseStateServiceShouldBeShared() err = %v", result.E)
+	}
+
+	if result := m2.Send(ctx, &slowCommand{}); result.E != nil {
+		t.Errorf("Test_rateLimitMiddleware_UseStateServiceShouldBeShared() err = %v", result.E)
+	}
+
+	if result := m1.Send(ctx, &slowCommand{}); !errors.Is(result.E, middlewares.ErrRateLimited) {
+		t.Errorf("Test_rateLimitMiddleware_UseStateServiceShouldBeShared() err = %v, expect %v", result.E, middlewares.ErrRateLimited)
+	}
+}
+
+func Test_rateLimitMiddleware_UseStateServiceShouldBeAtomic(t *testing.T) {
+	useStateService()
+
+	ctx := context.Background()
+	settings := middlewares.RateLimitSettings{
+		Rate:            0.01,
+		Burst:           5,
+		KeySelector:     func(ctx context.Context, request core.Request) string { return "atomic" },
+		UseStateService: true,
+	}
+
+	m1 := core.NewMediatorBuilder().
+		AddHandler(new(okCommand), okCommandHandler).
+		Create()
+	m1.AddMiddleware(middlewares.NewRateLimitMiddleware(settings))
+
+	m2 := core.NewMediatorBuilder().
+		AddHandler(new(okCommand), okCommandHandler).
+		Create()
+	m2.AddMiddleware(middlewares.NewRateLimitMiddleware(settings))
+
+	var wg sync.WaitGroup
+	var passed int32
+
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+
+		send := m1.Send
+		if i%2 == 0 {
+			send = m2.Send
+		}
+
+		go func(send func(context.Context, core.Request) core.Result) {
+			defer wg.Done()
+
+			if result := send(ctx, &okCommand{}); result.E == nil {
+				atomic.AddInt32(&passed, 1)
+			}
+		}(send)
+	}
+
+	wg.Wait()
+
+	if passed != 5 {
+		t.Errorf("Test_rateLimitMiddleware_UseStateServiceShouldBeAtomic() passed = %v, expect %v", passed, 5)
+	}
+}
+
+func Test_rateLimitMiddleware_ShouldEvictIdleBuckets(t *testing.T) {
+	limiter := middlewares.NewRateLimitMiddleware(middlewares.RateLimitSettings{
+		Rate:        100,
+		Burst:       1,
+		IdleTimeout: 50 * time.Millisecond,
+		KeySelector: func(ctx context.Context, request core.Request) string {
+			tenant, _ := ctx.Value(tenantKey{}).(string)
+			return tenant
+		},
+	})
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(okCommand), okCommandHandler).
+		Create()
+	m.AddMiddleware(limiter)
+
+	for _, tenant := range []string{"a", "b", "c"} {
+		if result := m.Send(context.WithValue(context.Background(), tenantKey{}, tenant), &okCommand{}); result.E != nil {
+			t.Errorf("Test_rateLimitMiddleware_ShouldEvictIdleBuckets() err = %v", result.E)
+		}
+	}
+
+	if count := limiter.Buckets(); count != 3 {
+		t.Errorf("Test_rateLimitMiddleware_ShouldEvictIdleBuckets() buckets = %v, expect %v", count, 3)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "d")
+
+	if result := m.Send(ctx, &okCommand{}); result.E != nil {
+		t.Errorf("Test_rateLimitMiddleware_ShouldEvictIdleBuckets() err = %v", result.E)
+	}
+
+	if count := limiter.Buckets(); count != 1 {
+		t.Errorf("Test_rateLimitMiddleware_ShouldEvictIdleBuckets() buckets = %v, expect %v", count, 1)
+	}
+
+	if result := m.Send(ctx, &okCommand{}); !errors.Is(result.E, middlewares.ErrRateLimited) {
+		t.Errorf("Test_rateLimitMiddleware_ShouldEvictIdleBuckets() err = %v, expect %v", result.E, middlewares.ErrRateLimited)
+	}
+}
@@ -0,0 +1,130 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/infrastructure/mocks"
+)
+
+type okCommand struct {
+	Expect int
+}
+
+type slowCommand struct {
+	Expect int
+}
+
+var stateSync sync.Once
+
+func okCommandHandler(ctx context.Context, request interface{}) core.Result {
+	return core.Result{V: request.(*okCommand).Expect}
+}
+
+func slowCommandHandler(ctx context.Context, request interface{}) core.Result {
+	time.Sleep(100 * time.Millisecond)
+
+	return core.Result{V: request.(*slowCommand).Expect}
+}
+
+func useStateService() {
+	stateSync.Do(func() {
+		core.NewStateServiceBuilder().
+			StateAdapter(mocks.NewMockAdapter()).
+			Build()
+	})
+}
+
+type countCommand struct {
+	Key    string
+	Expect int
+}
+
+func (c *countCommand) GetIdempotencyKey() string {
+	return c.Key
+}
+
+type countResult struct {
+	Expect int
+	Count  int32
+}
+
+var handledCount int32
+
+func countCommandHandler(ctx context.Context, request interface{}) core.Result {
+	count := atomic.AddInt32(&handledCount, 1)
+
+	time.Sleep(50 * time.Millisecond)
+
+	if request.(*countCommand).Expect < 0 {
+		return core.Result{E: core.ErrBadRequest}
+	}
+
+	return core.Result{V: &countResult{
+		Expect: request.(*countCommand).Expect,
+		Count:  count,
+	}}
+}
+
+type countQuery struct {
+	Expect int
+}
+
+type countUpdated struct {
+	core.DomainEvent
+}
+
+func countQueryHandler(ctx context.Context, request interface{}) core.Result {
+	count := atomic.AddInt32(&handledCount, 1)
+
+	return core.Result{V: &countResult{
+		Expect: request.(*countQuery).Expect,
+		Count:  count,
+	}}
+}
+
+func countUpdatedHandler(ctx context.Context, notification interface{}) error {
+	return nil
+}
+
+type validCommand struct {
+	Name  string `validate:"required"`
+	Email string `validate:"omitempty,email"`
+	Age   int    `validate:"gte=0,lte=130"`
+}
+
+func (c *validCommand) Validate(ctx context.Context) error {
+	if c.Name == "admin" {
+		return errors.New("admin is reserved")
+	}
+
+	return nil
+}
+
+func validCommandHandler(ctx context.Context, request interface{}) core.Result {
+	return core.Result{V: request.(*validCommand).Name}
+}
+
+type codeCommand struct {
+	Code string `validate:"required,upper"`
+}
+
+func codeCommandHandler(ctx context.Context, request interface{}) core.Result {
+	return core.Result{V: request.(*codeCommand).Code}
+}
+
+type adminCommand struct {
+	Expect int
+}
+
+func (c *adminCommand) RequiredRoles() []string {
+	return []string{"admin"}
+}
+
+func adminCommandHandler(ctx context.Context, request interface{}) core.Result {
+	return core.Result{V: request.(*adminCommand).Expect}
+}
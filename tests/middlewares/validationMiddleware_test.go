@@ -0,0 +1,105 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jybbang/go-core-architecture/core"
+	"github.com/jybbang/go-core-architecture/middlewares"
+)
+
+func Test_validationMiddleware_ShouldReturnFieldErrors(t *testing.T) {
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(validCommand), validCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewValidationMiddleware())
+
+	result := m.Send(ctx, &validCommand{Email: "qwe", Age: -1})
+
+	if !errors.Is(result.E, core.ErrBadRequest) {
+		t.Errorf("Test_validationMiddleware_ShouldReturnFieldErrors() err = %v, expect %v", result.E, core.ErrBadRequest)
+	}
+
+	var validationError *core.ValidationError
+	if !errors.As(result.E, &validationError) {
+		t.Fatalf("Test_validationMiddleware_ShouldReturnFieldErrors() err = %v, expect %T", result.E, validationError)
+	}
+
+	expect := map[string]string{
+		"Name":  "required",
+		"Email": "email",
+		"Age":   "gte",
+	}
+
+	if len(validationError.Fields) != len(expect) {
+		t.Errorf("Test_validationMiddleware_ShouldReturnFieldErrors() fields = %v, expect %v", validationError.Fields, expect)
+	}
+
+	for _, v := range validationError.Fields {
+		if expect[v.Field] != v.Tag {
+			t.Errorf("Test_validationMiddleware_ShouldReturnFieldErrors() tag = %v, expect %v", v.Tag, expect[v.Field])
+		}
+
+		if v.Namespace != "validCommand."+v.Field {
+			t.Errorf("Test_validationMiddleware_ShouldReturnFieldErrors() namespace = %v, expect %v", v.Namespace, "validCommand."+v.Field)
+		}
+
+		if !strings.Contains(v.Message, v.Field) {
+			t.Errorf("Test_validationMiddleware_ShouldReturnFieldErrors() message = %v, expect translated message", v.Message)
+		}
+	}
+}
+
+func Test_validationMiddleware_CustomValidatorShouldBeWorking(t *testing.T) {
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(codeCommand), codeCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewValidationMiddlewareWithSettings(middlewares.ValidationSettings{
+		Validators: map[string]validator.Func{
+			"upper": func(fl validator.FieldLevel) bool {
+				return strings.ToUpper(fl.Field().String()) == fl.Field().String()
+			},
+		},
+	}))
+
+	result := m.Send(ctx, &codeCommand{Code: "ABC"})
+
+	if result.E != nil {
+		t.Errorf("Test_validationMiddleware_CustomValidatorShouldBeWorking() err = %v", result.E)
+	}
+
+	result = m.Send(ctx, &codeCommand{Code: "abc"})
+
+	var validationError *core.ValidationError
+	if !errors.As(result.E, &validationError) || validationError.Fields[0].Tag != "upper" {
+		t.Errorf("Test_validationMiddleware_CustomValidatorShouldBeWorking() err = %v, expect %v", result.E, "upper")
+	}
+}
+
+func Test_validationMiddleware_SelfValidatorShouldBeWorking(t *testing.T) {
+	ctx := context.Background()
+
+	m := core.NewMediatorBuilder().
+		AddHandler(new(validCommand), validCommandHandler).
+		Create()
+	m.AddMiddleware(middlewares.NewValidationMiddleware())
+
+	result := m.Send(ctx, &validCommand{Name: "admin"})
+
+	if !errors.Is(result.E, core.ErrBadRequest) {
+		t.Errorf("Test_validationMiddleware_SelfValidatorShouldBeWorking() err = %v, expect %v", result.E, core.ErrBadRequest)
+	}
+
+	result = m.Send(ctx, &validCommand{Name: "qwe"})
+
+	if result.E != nil || result.V != "qwe" {
+		t.Errorf("Test_validationMiddleware_SelfValidatorShouldBeWorking() result = %v, err = %v", result.V, result.E)
+	}
+}